@@ -10,33 +10,88 @@ import (
 func main() {
 	mux := http.NewServeMux()
 
+	registerRoutes(mux)
+
+	// Pokretanje servera
+	http.ListenAndServe(":8000", enableCORS(mux))
+}
+
+// registerRoutes registruje sve rute gateway-a. ServeMux prekida rad (panic) ako dva obrasca
+// mogu da se poklope sa istom putanjom, a nijedan nije specifičniji, pa rute zadataka nekog
+// projekta, sprinta ili šablona nisu pod /api/tasks/, gde bi se sudarale sa /api/tasks/{taskID}/...
+func registerRoutes(mux *http.ServeMux) {
 	mux.Handle("/api/projects/add", authMiddleware(reverseProxyURL("http://projects-service:8003"), []string{"manager"}))
-	mux.Handle("/api/projects/{id}/members", authMiddleware(reverseProxyURL("http://projects-service:8003"), []string{"manager"}))
+	mux.Handle("/api/projects/import/trello", authMiddleware(reverseProxyURL("http://projects-service:8003"), []string{"manager"}))
 	mux.Handle("/api/projects/all", authMiddleware(reverseProxyURL("http://projects-service:8003"), []string{"manager", "member"}))
 	mux.Handle("/api/projects/{id}", authMiddleware(reverseProxyURL("http://projects-service:8003"), []string{"manager", "member"}))
 	mux.Handle("/api/projects/{projectId}/members/all", authMiddleware(reverseProxyURL("http://projects-service:8003"), []string{"manager", "member"})) //
-	mux.Handle("/api/projects/{projectId}/members/{memberId}/remove", authMiddleware(reverseProxyURL("http://projects-service:8003"), []string{"manager"}))
+	mux.Handle("/api/projects/{projectId}/members/{memberId}/remove", authMiddleware(reverseProxyURL("http://projects-service:8003"), []string{"manager", "member"}))
 	mux.Handle("/api/projects/users", authMiddleware(reverseProxyURL("http://projects-service:8003"), []string{"manager", "member"}))
 	mux.Handle("/api/projects/{id}/tasks", authMiddleware(reverseProxyURL("http://projects-service:8003"), []string{"manager", "member"}))
-	mux.Handle("/api/projects/{username}", authMiddleware(reverseProxyURL("http://projects-service:8003"), []string{"manager", "member"}))
-	mux.Handle("/api/projects/{id}/delete", authMiddleware(reverseProxyURL("http://projects-service:8003"), []string{"manager"}))
+	mux.Handle("/api/projects/{projectId}/invitations", authMiddleware(reverseProxyURL("http://projects-service:8003"), []string{"manager", "member"}))
+	mux.Handle("/api/projects/{projectId}/invitations/{invitationId}", authMiddleware(reverseProxyURL("http://projects-service:8003"), []string{"manager", "member"}))
+	mux.Handle("/api/invitations", authMiddleware(reverseProxyURL("http://projects-service:8003"), []string{"manager", "member"}))
+	mux.Handle("/api/invitations/{invitationId}/accept", authMiddleware(reverseProxyURL("http://projects-service:8003"), []string{"manager", "member"}))
+	mux.Handle("/api/invitations/{invitationId}/decline", authMiddleware(reverseProxyURL("http://projects-service:8003"), []string{"manager", "member"}))
+	mux.Handle("/api/projects/{projectId}/role", authMiddleware(reverseProxyURL("http://projects-service:8003"), []string{"manager", "member"}))
+	mux.Handle("/api/projects/{projectId}/members/{memberId}/role", authMiddleware(reverseProxyURL("http://projects-service:8003"), []string{"manager", "member"}))
+	mux.Handle("/api/projects/{projectId}/sprints", authMiddleware(reverseProxyURL("http://projects-service:8003"), []string{"manager", "member"}))
+	mux.Handle("/api/projects/{projectId}/sprints/{sprintId}", authMiddleware(reverseProxyURL("http://projects-service:8003"), []string{"manager", "member"}))
+	mux.Handle("/api/projects/{projectId}/sprints/{sprintId}/start", authMiddleware(reverseProxyURL("http://projects-service:8003"), []string{"manager", "member"}))
+	mux.Handle("/api/projects/{projectId}/sprints/{sprintId}/close", authMiddleware(reverseProxyURL("http://projects-service:8003"), []string{"manager", "member"}))
+	mux.Handle("/api/projects/{projectId}/velocity", authMiddleware(reverseProxyURL("http://projects-service:8003"), []string{"manager", "member"}))
+	mux.Handle("/api/projects/{projectId}/staffing", authMiddleware(reverseProxyURL("http://projects-service:8003"), []string{"manager", "member"}))
+	mux.Handle("/api/projects/{projectId}/webhooks", authMiddleware(reverseProxyURL("http://projects-service:8003"), []string{"manager"}))
+	mux.Handle("/api/projects/{projectId}/webhooks/{webhookId}", authMiddleware(reverseProxyURL("http://projects-service:8003"), []string{"manager"}))
+	mux.Handle("/api/projects/{projectId}/webhooks/{webhookId}/deliveries", authMiddleware(reverseProxyURL("http://projects-service:8003"), []string{"manager"}))
+	mux.Handle("/api/projects/{projectId}/transfer-ownership", authMiddleware(reverseProxyURL("http://projects-service:8003"), []string{"manager"}))
+	mux.Handle("/api/projects/{projectId}/archive", authMiddleware(reverseProxyURL("http://projects-service:8003"), []string{"manager"}))
+	mux.Handle("/api/projects/{projectId}/unarchive", authMiddleware(reverseProxyURL("http://projects-service:8003"), []string{"manager"}))
+	mux.Handle("/api/projects/{id}/clone", authMiddleware(reverseProxyURL("http://projects-service:8003"), []string{"manager"}))
+	mux.Handle("/api/projects/{id}/activity", authMiddleware(reverseProxyURL("http://projects-service:8003"), []string{"manager", "member"}))
+	mux.Handle("/api/projects/admin/orphaned", authMiddleware(reverseProxyURL("http://projects-service:8003"), []string{"admin"}))
+	mux.Handle("/api/projects/admin/orphaned/{projectId}/reassign", authMiddleware(reverseProxyURL("http://projects-service:8003"), []string{"admin"}))
+	mux.Handle("/api/projects/{id}/delete", authMiddleware(reverseProxyURL("http://projects-service:8003"), []string{"manager", "member"}))
+	mux.Handle("/api/projects/{projectId}/deletion", authMiddleware(reverseProxyURL("http://projects-service:8003"), []string{"manager"}))
 
 	// Rute za Tasks Service (samo menadžer dodaje zadatke, član menja status)
-	mux.Handle("/api/tasks/create", authMiddleware(reverseProxyURL("http://tasks-service:8002"), []string{"manager"}))
-	mux.Handle("/api/tasks/status", authMiddleware(reverseProxyURL("http://tasks-service:8002"), []string{"member"}))
-	mux.Handle("/api/tasks/{taskID}/members", authMiddleware(reverseProxyURL("http://tasks-service:8002"), []string{"manager"}))
+	mux.Handle("/api/tasks/create", authMiddleware(reverseProxyURL("http://tasks-service:8002"), []string{"manager", "member"}))
+	mux.Handle("/api/tasks/status", authMiddleware(reverseProxyURL("http://tasks-service:8002"), []string{"member", "manager"}))
+	mux.Handle("/api/task-templates", authMiddleware(reverseProxyURL("http://tasks-service:8002"), []string{"manager", "member"}))
+	mux.Handle("/api/task-templates/{templateId}", authMiddleware(reverseProxyURL("http://tasks-service:8002"), []string{"manager", "member"}))
+	mux.Handle("/api/sprints/{sprintId}/tasks", authMiddleware(reverseProxyURL("http://tasks-service:8002"), []string{"manager", "member"}))
+	mux.Handle("/api/sprints/{sprintId}/summary", authMiddleware(reverseProxyURL("http://tasks-service:8002"), []string{"manager", "member"}))
+	mux.Handle("/api/tasks/{taskID}/sprint", authMiddleware(reverseProxyURL("http://tasks-service:8002"), []string{"manager", "member"}))
+	mux.Handle("/api/tasks/bulk", authMiddleware(reverseProxyURL("http://tasks-service:8002"), []string{"member", "manager"}))
+	mux.Handle("/api/tasks/{taskID}/watch", authMiddleware(reverseProxyURL("http://tasks-service:8002"), []string{"manager", "member"}))
+	mux.Handle("/api/tasks/{taskID}/watchers", authMiddleware(reverseProxyURL("http://tasks-service:8002"), []string{"manager", "member"}))
+	mux.Handle("/api/tasks/{taskID}/assignment", authMiddleware(reverseProxyURL("http://tasks-service:8002"), []string{"manager", "member"}))
+	mux.Handle("/api/tasks/{taskID}/recurrence", authMiddleware(reverseProxyURL("http://tasks-service:8002"), []string{"manager", "member"}))
+	mux.Handle("/api/tasks/{taskID}/history", authMiddleware(reverseProxyURL("http://tasks-service:8002"), []string{"manager", "member"}))
+	mux.Handle("/api/tasks/{taskID}", authMiddleware(reverseProxyURL("http://tasks-service:8002"), []string{"manager", "member"}))
+	mux.Handle("/api/tasks/{taskID}/move", authMiddleware(reverseProxyURL("http://tasks-service:8002"), []string{"manager", "member"}))
+	mux.Handle("/api/project-tasks/{projectId}/wip-limits", authMiddleware(reverseProxyURL("http://tasks-service:8002"), []string{"manager", "member"}))
+	mux.Handle("/api/tasks/{taskID}/transfer", authMiddleware(reverseProxyURL("http://tasks-service:8002"), []string{"manager", "member"}))
+	mux.Handle("/api/tasks/{taskID}/archive", authMiddleware(reverseProxyURL("http://tasks-service:8002"), []string{"manager", "member"}))
+	mux.Handle("/api/tasks/{taskID}/restore", authMiddleware(reverseProxyURL("http://tasks-service:8002"), []string{"manager", "member"}))
+	mux.Handle("/api/project-tasks/{projectId}/archived", authMiddleware(reverseProxyURL("http://tasks-service:8002"), []string{"manager", "member"}))
+	mux.Handle("/api/project-tasks/{projectId}/export", authMiddleware(reverseProxyURL("http://tasks-service:8002"), []string{"manager", "member"}))
+	mux.Handle("/api/tasks/{taskID}/members", authMiddleware(reverseProxyURL("http://tasks-service:8002"), []string{"manager", "member"}))
 	mux.Handle("/api/tasks/all", authMiddleware(reverseProxyURL("http://tasks-service:8002"), []string{"manager", "member"}))
-	mux.Handle("/api/tasks/project/{projectId}", authMiddleware(reverseProxyURL("http://tasks-service:8002"), []string{"manager", "member"}))
-	mux.Handle("/api/tasks/{taskId}/members", authMiddleware(reverseProxyURL("http://tasks-service:8002"), []string{"manager", "member"}))
-	mux.Handle("/api/tasks/{taskID}/add-members", authMiddleware(reverseProxyURL("http://tasks-service:8002"), []string{"manager"}))
-	mux.Handle("/api/tasks/{taskID}/members/{memberID}", authMiddleware(reverseProxyURL("http://tasks-service:8002"), []string{"manager"}))
-	mux.Handle("/api/tasks/{taskID}/project/{projectID}/available-members", authMiddleware(reverseProxyURL("http://tasks-service:8002"), []string{"manager"}))
+	mux.Handle("/api/project-tasks/{projectId}", authMiddleware(reverseProxyURL("http://tasks-service:8002"), []string{"manager", "member"}))
+	mux.Handle("/api/tasks/{taskID}/add-members", authMiddleware(reverseProxyURL("http://tasks-service:8002"), []string{"manager", "member"}))
+	mux.Handle("/api/tasks/{taskID}/members/{memberID}", authMiddleware(reverseProxyURL("http://tasks-service:8002"), []string{"manager", "member"}))
+	mux.Handle("/api/tasks/{taskID}/project/{projectID}/available-members", authMiddleware(reverseProxyURL("http://tasks-service:8002"), []string{"manager", "member"}))
 	// Rute za Users Service (brisanje naloga dostupno svima)
 	mux.Handle("/api/users/auth/delete-account/{username}", authMiddleware(reverseProxyURL("http://users-service:8001"), []string{"manager", "member"}))
 	mux.Handle("/api/users/users-profile", authMiddleware(reverseProxyURL("http://users-service:8001"), []string{"manager", "member"}))
 	mux.Handle("/api/users/check-username", authMiddleware(reverseProxyURL("http://users-service:8001"), []string{"manager", "member"}))
 	mux.Handle("/api/users/change-password", authMiddleware(reverseProxyURL("http://users-service:8001"), []string{"manager", "member"}))
 
+	// Kalendarski feed: klijenti ne šalju JWT, pristup daje tajni token u putanji
+	mux.Handle("/api/calendar/token", authMiddleware(reverseProxyURL("http://projects-service:8003"), []string{"manager", "member"}))
+	mux.Handle("/api/calendar/feed/{token}", reverseProxyURL("http://projects-service:8003"))
+
 	// Rute za Notifications Service (samo članovi mogu da vide notifikacije)
 	mux.Handle("/api/notifications", authMiddleware(reverseProxyURL("http://notifications-service:8004"), []string{"member"}))
 	mux.Handle("/api/notifications/read", authMiddleware(reverseProxyURL("http://notifications-service:8004"), []string{"member"}))
@@ -45,8 +100,8 @@ func main() {
 	// Rute za Workflow Service
 	mux.Handle("/api/workflow/dependency", authMiddleware(reverseProxyURL("http://workflow-service:8005"), []string{"manager"}))
 
-	// Pokretanje servera
-	http.ListenAndServe(":8000", enableCORS(mux))
+	// Rute za Analytics Service
+	mux.Handle("/api/analytics/projects/{id}", authMiddleware(reverseProxyURL("http://analytics-service:8006"), []string{"manager", "member"}))
 }
 
 // Reverse Proxy funkcija
@@ -74,7 +129,7 @@ func reverseProxyURL(target string) http.Handler {
 func enableCORS(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
 		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Role, Manager-ID")
 
 		if r.Method == http.MethodOptions {
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRegisterRoutesHasNoConflicts(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			t.Fatalf("registering gateway routes panicked: %v", r)
+		}
+	}()
+	registerRoutes(http.NewServeMux())
+}
+
+func TestRegisterRoutesMatchesExpectedPattern(t *testing.T) {
+	mux := http.NewServeMux()
+	registerRoutes(mux)
+
+	for path, want := range map[string]string{
+		"/api/projects/all":                          "/api/projects/all",
+		"/api/projects/p1":                           "/api/projects/{id}",
+		"/api/projects/p1/sprints/s1/close":          "/api/projects/{projectId}/sprints/{sprintId}/close",
+		"/api/tasks/create":                          "/api/tasks/create",
+		"/api/tasks/t1":                              "/api/tasks/{taskID}",
+		"/api/tasks/t1/members":                      "/api/tasks/{taskID}/members",
+		"/api/tasks/t1/members/m1":                   "/api/tasks/{taskID}/members/{memberID}",
+		"/api/tasks/t1/watch":                        "/api/tasks/{taskID}/watch",
+		"/api/tasks/t1/history":                      "/api/tasks/{taskID}/history",
+		"/api/tasks/t1/move":                         "/api/tasks/{taskID}/move",
+		"/api/tasks/t1/archive":                      "/api/tasks/{taskID}/archive",
+		"/api/tasks/t1/restore":                      "/api/tasks/{taskID}/restore",
+		"/api/tasks/t1/transfer":                     "/api/tasks/{taskID}/transfer",
+		"/api/tasks/t1/sprint":                       "/api/tasks/{taskID}/sprint",
+		"/api/tasks/t1/project/p1/available-members": "/api/tasks/{taskID}/project/{projectID}/available-members",
+		"/api/project-tasks/p1":                      "/api/project-tasks/{projectId}",
+		"/api/project-tasks/p1/archived":             "/api/project-tasks/{projectId}/archived",
+		"/api/project-tasks/p1/export":               "/api/project-tasks/{projectId}/export",
+		"/api/project-tasks/p1/wip-limits":           "/api/project-tasks/{projectId}/wip-limits",
+		"/api/task-templates":                        "/api/task-templates",
+		"/api/task-templates/tpl1":                   "/api/task-templates/{templateId}",
+		"/api/sprints/s1/tasks":                      "/api/sprints/{sprintId}/tasks",
+		"/api/sprints/s1/summary":                    "/api/sprints/{sprintId}/summary",
+		"/api/calendar/feed/token":                   "/api/calendar/feed/{token}",
+	} {
+		_, pattern := mux.Handler(httptest.NewRequest(http.MethodGet, path, nil))
+		if pattern != want {
+			t.Errorf("%s matched %q, want %q", path, pattern, want)
+		}
+	}
+}
@@ -1,7 +1,33 @@
-module trello-project/microservices/tasks-service
+module trello-project/microservices/analytics-service
 
 go 1.22.0
 
-require go.mongodb.org/mongo-driver v1.17.1
+require (
+	github.com/google/uuid v1.6.0
+	github.com/gorilla/mux v1.8.1
+	github.com/joho/godotenv v1.5.1
+	github.com/nats-io/nats.go v1.37.0
+	github.com/sirupsen/logrus v1.9.3
+	github.com/sony/gobreaker v1.0.0
+	go.mongodb.org/mongo-driver v1.17.1
+	gopkg.in/natefinch/lumberjack.v2 v2.2.1
+	trello-project/backend/utils v0.0.0
+)
 
-require github.com/sony/gobreaker v1.0.0 // indirect
+require (
+	github.com/golang/snappy v0.0.4 // indirect
+	github.com/klauspost/compress v1.18.0 // indirect
+	github.com/montanaflynn/stats v0.7.1 // indirect
+	github.com/nats-io/nkeys v0.4.7 // indirect
+	github.com/nats-io/nuid v1.0.1 // indirect
+	github.com/xdg-go/pbkdf2 v1.0.0 // indirect
+	github.com/xdg-go/scram v1.1.2 // indirect
+	github.com/xdg-go/stringprep v1.0.4 // indirect
+	github.com/youmark/pkcs8 v0.0.0-20240726163527-a2c0da244d78 // indirect
+	golang.org/x/crypto v0.26.0 // indirect
+	golang.org/x/sync v0.8.0 // indirect
+	golang.org/x/sys v0.23.0 // indirect
+	golang.org/x/text v0.17.0 // indirect
+)
+
+replace trello-project/backend/utils => ../utils
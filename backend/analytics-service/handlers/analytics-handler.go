@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"trello-project/microservices/analytics-service/logging"
+	"trello-project/microservices/analytics-service/services"
+
+	"trello-project/backend/utils/projectroles"
+
+	"github.com/gorilla/mux"
+)
+
+type AnalyticsHandler struct {
+	service *services.AnalyticsService
+	roles   *projectroles.Resolver
+}
+
+func NewAnalyticsHandler(service *services.AnalyticsService, roles *projectroles.Resolver) *AnalyticsHandler {
+	return &AnalyticsHandler{service: service, roles: roles}
+}
+
+func checkRole(r *http.Request, allowedRoles []string) error {
+	userRole := r.Header.Get("Role")
+	if userRole == "" {
+		logging.Logger.Warnf("Event ID: AUTH_MISSING_ROLE, Description: Role header is missing in request from %s for path %s", r.RemoteAddr, r.URL.Path)
+		return fmt.Errorf("role is missing in request header")
+	}
+
+	for _, role := range allowedRoles {
+		if role == userRole {
+			return nil
+		}
+	}
+	logging.Logger.Warnf("Event ID: AUTH_FORBIDDEN_ROLE, Description: Access forbidden for user with role '%s' to path %s. Required roles: %v", userRole, r.URL.Path, allowedRoles)
+	return fmt.Errorf("access forbidden: user does not have the required role")
+}
+
+// authorizeProject proverava da li korisnik ima bar ulogu min na projektu. Pozivi drugih
+// servisa ne šalju korisnički token, pa se za njih proverava globalno Role zaglavlje.
+func (h *AnalyticsHandler) authorizeProject(w http.ResponseWriter, r *http.Request, projectID string, min projectroles.Role, serviceRoles []string) bool {
+	authorization := r.Header.Get("Authorization")
+	if strings.TrimPrefix(authorization, "Bearer ") == "" {
+		if err := checkRole(r, serviceRoles); err != nil {
+			http.Error(w, "Access forbidden: insufficient permissions", http.StatusForbidden)
+			return false
+		}
+		return true
+	}
+
+	role, err := h.roles.Require(projectID, authorization, min)
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, projectroles.ErrNoAccess), errors.Is(err, projectroles.ErrInsufficientRole):
+		logging.Logger.Warnf("Event ID: AUTH_PROJECT_ROLE_DENIED, Description: Access to %s %s denied on project %s (role '%s', requires '%s').", r.Method, r.URL.Path, projectID, role, min)
+		http.Error(w, "Access forbidden: "+err.Error(), http.StatusForbidden)
+	case errors.Is(err, projectroles.ErrProjectNotFound):
+		http.Error(w, "Project not found", http.StatusNotFound)
+	case errors.Is(err, projectroles.ErrProjectDeleting):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		logging.Logger.Errorf("Event ID: AUTH_PROJECT_ROLE_UNAVAILABLE, Description: Failed to resolve project role for project %s: %v", projectID, err)
+		http.Error(w, "Failed to verify project role", http.StatusServiceUnavailable)
+	}
+	return false
+}
+
+// GetProjectAnalyticsHandler vraća izveštaj o projektu: broj zadataka, zadatke po statusu,
+// vreme po statusu za svaki zadatak, zaduženja korisnika i prognozu završetka.
+func (h *AnalyticsHandler) GetProjectAnalyticsHandler(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["id"]
+	if !h.authorizeProject(w, r, projectID, projectroles.Viewer, []string{"manager"}) {
+		return
+	}
+
+	analytics, err := h.service.GetProjectAnalytics(r.Context(), projectID)
+	if err != nil {
+		switch {
+		case err.Error() == "invalid project ID format":
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, services.ErrProjectNotFound):
+			http.Error(w, "Project not found", http.StatusNotFound)
+		default:
+			logging.Logger.Errorf("Event ID: ANALYTICS_SERVICE_ERROR, Description: Failed to get analytics for project %s: %v", projectID, err)
+			http.Error(w, "Failed to get project analytics", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(analytics)
+}
+
+// ReplayEventsHandler ponovo čita sve događaje iz broker-a u bazu analytics-service-a.
+// Namenjen je samo internim pozivima, pa se odbija svaki zahtev sa korisničkim tokenom.
+func (h *AnalyticsHandler) ReplayEventsHandler(w http.ResponseWriter, r *http.Request) {
+	if strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ") != "" {
+		http.Error(w, "Access forbidden: internal endpoint", http.StatusForbidden)
+		return
+	}
+	if err := checkRole(r, []string{"manager"}); err != nil {
+		http.Error(w, "Access forbidden: insufficient permissions", http.StatusForbidden)
+		return
+	}
+	reset, _ := strconv.ParseBool(r.URL.Query().Get("reset"))
+
+	replayed, err := h.service.ReplayEvents(r.Context(), reset)
+	if err != nil {
+		logging.Logger.Errorf("Event ID: EVENTS_REPLAY_FAILED, Description: %v", err)
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"replayed": replayed, "reset": reset})
+}
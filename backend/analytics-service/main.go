@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"trello-project/microservices/analytics-service/handlers"
+	"trello-project/microservices/analytics-service/logging"
+	"trello-project/microservices/analytics-service/services"
+
+	http_client "trello-project/backend/utils"
+	"trello-project/backend/utils/events"
+	"trello-project/backend/utils/projectroles"
+
+	"github.com/gorilla/mux"
+	"github.com/joho/godotenv"
+	"github.com/sony/gobreaker"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func enableCORS(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Role")
+
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func createEventIndexes(collection *mongo.Collection) error {
+	_, err := collection.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "projectId", Value: 1}, {Key: "taskId", Value: 1}}},
+		{Keys: bson.D{{Key: "taskId", Value: 1}, {Key: "occurredAt", Value: 1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create indexes on events: %v", err)
+	}
+	logging.Logger.Info("Event ID: DB_INDEX_CREATED, Description: Indexes on events created successfully")
+	return nil
+}
+
+func main() {
+	logging.InitLogger()
+
+	logging.Logger.Info("Event ID: SERVICE_START, Description: Starting Analytics Service...")
+	// U docker-compose okruženju promenljive dolaze iz environment sekcije, pa .env nije obavezan
+	if err := godotenv.Load(".env"); err != nil {
+		logging.Logger.Warnf("Event ID: ENV_LOAD_SKIPPED, Description: .env file not loaded: %v", err)
+	}
+
+	mongoURI := os.Getenv("MONGO_URI")
+	mongoDBName := os.Getenv("MONGO_DB_NAME")
+	if mongoDBName == "" {
+		mongoDBName = "analytics"
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(mongoURI))
+	if err != nil {
+		logging.Logger.Fatalf("Event ID: DB_CONNECTION_FAILED, Description: Database connection for MongoDB failed: %v", err)
+	}
+	defer client.Disconnect(context.Background())
+
+	if err := client.Ping(ctx, nil); err != nil {
+		logging.Logger.Fatalf("Event ID: DB_PING_FAILED, Description: MongoDB connection ping error: %v", err)
+	}
+	logging.Logger.Infof("Event ID: DB_CONNECTED, Description: Successfully connected to MongoDB at %s.", mongoURI)
+
+	eventsCollection := client.Database(mongoDBName).Collection("events")
+	if err := createEventIndexes(eventsCollection); err != nil {
+		logging.Logger.Fatalf("Event ID: DB_INDEX_ERROR, Description: %v", err)
+	}
+
+	natsURL := os.Getenv("NATS_URL")
+	if natsURL == "" {
+		logging.Logger.Fatalf("Event ID: CONFIG_ERROR, Description: NATS_URL is not set in the environment variables.")
+	}
+	nc, err := events.Connect(natsURL, "analytics-service")
+	if err != nil {
+		logging.Logger.Fatalf("Event ID: NATS_CONNECTION_FAILED, Description: Failed to connect to NATS at %s: %v", natsURL, err)
+	}
+	defer nc.Close()
+	js, err := nc.JetStream()
+	if err != nil {
+		logging.Logger.Fatalf("Event ID: NATS_CONNECTION_FAILED, Description: Failed to open JetStream context: %v", err)
+	}
+
+	analyticsService := services.NewAnalyticsService(eventsCollection, js)
+	analyticsService.StartEventConsumer(context.Background(), 5*time.Second)
+
+	httpClient := http_client.NewHTTPClient()
+	projectsBreaker := gobreaker.NewCircuitBreaker(gobreaker.Settings{
+		Name:        "ProjectsServiceCB",
+		MaxRequests: 1,
+		Timeout:     2 * time.Second,
+		ReadyToTrip: func(counts gobreaker.Counts) bool {
+			return counts.ConsecutiveFailures > 3
+		},
+		OnStateChange: func(name string, from, to gobreaker.State) {
+			logging.Logger.Infof("Event ID: CIRCUIT_BREAKER_STATE_CHANGE, Description: Circuit Breaker '%s' changed from '%s' to '%s'", name, from.String(), to.String())
+		},
+	})
+	// Uloge korisnika na projektima razrešava projects-service
+	roleResolver := projectroles.NewResolver(os.Getenv("PROJECTS_SERVICE_URL"), httpClient, projectsBreaker)
+	analyticsHandler := handlers.NewAnalyticsHandler(analyticsService, roleResolver)
+
+	r := mux.NewRouter()
+	r.HandleFunc("/api/analytics/projects/{id}", analyticsHandler.GetProjectAnalyticsHandler).Methods(http.MethodGet)
+	r.HandleFunc("/api/analytics/events/replay", analyticsHandler.ReplayEventsHandler).Methods(http.MethodPost)
+
+	serverPort := os.Getenv("SERVER_PORT")
+	if serverPort == "" {
+		serverPort = "8006"
+	}
+
+	serverAddress := fmt.Sprintf(":%s", serverPort)
+	logging.Logger.Infof("Event ID: SERVER_START_INFO, Description: Server running on http://localhost%s", serverAddress)
+
+	if err := http.ListenAndServe(serverAddress, enableCORS(r)); err != nil {
+		logging.Logger.Fatalf("Event ID: SERVER_FATAL_ERROR, Description: Server failed to start: %v", err)
+	}
+}
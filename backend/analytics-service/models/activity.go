@@ -15,6 +15,7 @@ const (
 	ActivityDeleteTask        ActivityType = "DeleteTask"
 	ActivityChangeTaskStatus  ActivityType = "ChangeTaskStatus"
 	ActivityAddDocumentToTask ActivityType = "AddDocumentToTask"
+	ActivityUpdateTask        ActivityType = "UpdateTask"
 )
 
 type ProjectActivity struct {
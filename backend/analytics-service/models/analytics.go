@@ -6,6 +6,9 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// ProjectAnalytics je izveštaj o projektu. TaskTimeInStatus je, po zadatku, ukupno vreme
+// provedeno u svakom statusu (time.Duration, u JSON-u nanosekunde); UserTaskAssignments
+// su zadaci dodeljeni svakom korisniku.
 type ProjectAnalytics struct {
 	ProjectID           primitive.ObjectID                              `json:"projectId" bson:"projectId"`
 	TotalTasks          int                                             `json:"totalTasks" bson:"totalTasks"`
@@ -13,4 +16,9 @@ type ProjectAnalytics struct {
 	TaskTimeInStatus    map[primitive.ObjectID]map[string]time.Duration `json:"taskTimeInStatus" bson:"taskTimeInStatus"`
 	UserTaskAssignments map[primitive.ObjectID][]primitive.ObjectID     `json:"userTaskAssignments" bson:"userTaskAssignments"`
 	IsCompletedOnTime   bool                                            `json:"isCompletedOnTime" bson:"isCompletedOnTime"`
+	// ExpectedEndDate i ForecastCompletion objašnjavaju IsCompletedOnTime: prognoza se
+	// računa iz dosadašnjeg tempa završavanja zadataka.
+	ExpectedEndDate    time.Time  `json:"expectedEndDate" bson:"expectedEndDate"`
+	ForecastCompletion *time.Time `json:"forecastCompletion,omitempty" bson:"forecastCompletion,omitempty"`
+	ComputedAt         time.Time  `json:"computedAt" bson:"computedAt"`
 }
@@ -0,0 +1,257 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"trello-project/microservices/analytics-service/logging"
+	"trello-project/microservices/analytics-service/models"
+
+	"trello-project/backend/utils/events"
+
+	"github.com/nats-io/nats.go"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const statusCompleted = "Completed"
+
+var ErrProjectNotFound = errors.New("project not found")
+
+// AnalyticsService gradi izveštaje isključivo iz domenskih događaja sačuvanih u sopstvenoj
+// bazi, pa se izveštaj uvek može ponovo izračunati ponovnim čitanjem događaja.
+type AnalyticsService struct {
+	events *mongo.Collection
+	js     nats.JetStreamContext
+}
+
+func NewAnalyticsService(eventsCollection *mongo.Collection, js nats.JetStreamContext) *AnalyticsService {
+	return &AnalyticsService{events: eventsCollection, js: js}
+}
+
+// taskState je stanje zadatka dobijeno primenom njegovih događaja redom kojim su nastali.
+type taskState struct {
+	projectID    string
+	status       string
+	since        time.Time
+	timeInStatus map[string]time.Duration
+	members      []primitive.ObjectID
+	createdAt    time.Time
+	completedAt  time.Time
+	active       bool
+}
+
+func newTaskState(taskID primitive.ObjectID) *taskState {
+	// Dok ne stigne task.created, vreme kreiranja je vreme iz ID-ja zadatka
+	created := taskID.Timestamp().UTC()
+	return &taskState{
+		since:        created,
+		createdAt:    created,
+		timeInStatus: make(map[string]time.Duration),
+		active:       true,
+	}
+}
+
+// advance dodaje trenutnom statusu vreme od poslednje promene do at.
+func (t *taskState) advance(at time.Time) {
+	if t.status != "" && at.After(t.since) {
+		t.timeInStatus[t.status] += at.Sub(t.since)
+	}
+	if at.After(t.since) {
+		t.since = at
+	}
+}
+
+func (t *taskState) apply(event events.Event) {
+	if t.projectID == "" {
+		t.projectID = event.ProjectID
+	}
+	switch event.Type {
+	case events.TaskCreated:
+		t.projectID = event.ProjectID
+		t.status = event.NewStatus
+		t.since = event.OccurredAt
+		t.createdAt = event.OccurredAt
+	case events.TaskStatusChanged:
+		if t.status == "" {
+			t.status = event.OldStatus
+		}
+		t.advance(event.OccurredAt)
+		t.status = event.NewStatus
+		if t.status == statusCompleted {
+			t.completedAt = event.OccurredAt
+		}
+	case events.TaskMoved:
+		t.projectID = event.ProjectID
+	case events.TaskMemberAdded:
+		if memberID, err := primitive.ObjectIDFromHex(event.MemberID); err == nil && !containsID(t.members, memberID) {
+			t.members = append(t.members, memberID)
+		}
+	case events.TaskMemberRemoved:
+		if memberID, err := primitive.ObjectIDFromHex(event.MemberID); err == nil {
+			t.members = removeID(t.members, memberID)
+		}
+	case events.TaskArchived, events.TaskDeleted:
+		t.active = false
+	case events.TaskRestored:
+		t.active = true
+	}
+}
+
+// GetProjectAnalytics računa izveštaj o projektu iz sačuvanih događaja projekta i svih
+// događaja zadataka koji su ikada pripadali projektu (zadatak je mogao biti premešten).
+func (s *AnalyticsService) GetProjectAnalytics(ctx context.Context, projectID string) (*models.ProjectAnalytics, error) {
+	projectObjectID, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid project ID format")
+	}
+
+	taskIDs, err := s.events.Distinct(ctx, "taskId", bson.M{"projectId": projectID, "taskId": bson.M{"$exists": true}})
+	if err != nil {
+		logging.Logger.Errorf("Event ID: ANALYTICS_EVENTS_FETCH_FAILED, Description: Failed to load task IDs for project %s: %v", projectID, err)
+		return nil, fmt.Errorf("failed to load project events: %v", err)
+	}
+	filter := bson.M{"$or": []bson.M{
+		{"projectId": projectID, "taskId": bson.M{"$exists": false}},
+		{"taskId": bson.M{"$in": taskIDs}},
+	}}
+	opts := options.Find().SetSort(bson.D{{Key: "occurredAt", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := s.events.Find(ctx, filter, opts)
+	if err != nil {
+		logging.Logger.Errorf("Event ID: ANALYTICS_EVENTS_FETCH_FAILED, Description: Failed to load events for project %s: %v", projectID, err)
+		return nil, fmt.Errorf("failed to load project events: %v", err)
+	}
+	var projectEvents []events.Event
+	if err := cursor.All(ctx, &projectEvents); err != nil {
+		return nil, fmt.Errorf("failed to decode project events: %v", err)
+	}
+	if len(projectEvents) == 0 {
+		return nil, ErrProjectNotFound
+	}
+
+	analytics, deleted := buildProjectAnalytics(projectObjectID, projectEvents, time.Now().UTC())
+	if deleted {
+		return nil, ErrProjectNotFound
+	}
+	logging.Logger.Infof("Event ID: ANALYTICS_COMPUTED, Description: Computed analytics for project %s from %d events", projectID, len(projectEvents))
+	return analytics, nil
+}
+
+// buildProjectAnalytics primenjuje događaje hronološki. Vreme po statusu se sabira iz
+// vremena samih događaja i zaokružuje na sekundu tek na kraju. Zadaci čiji početni status
+// nije poznat (nema task.created ni promene statusa) se ne računaju.
+func buildProjectAnalytics(projectID primitive.ObjectID, projectEvents []events.Event, now time.Time) (*models.ProjectAnalytics, bool) {
+	analytics := &models.ProjectAnalytics{
+		ProjectID:           projectID,
+		TasksByStatus:       make(map[string]int),
+		TaskTimeInStatus:    make(map[primitive.ObjectID]map[string]time.Duration),
+		UserTaskAssignments: make(map[primitive.ObjectID][]primitive.ObjectID),
+		ComputedAt:          now.Truncate(time.Second),
+	}
+
+	deleted := false
+	tasks := make(map[primitive.ObjectID]*taskState)
+	var order []primitive.ObjectID
+	for _, event := range projectEvents {
+		if event.TaskID == "" {
+			switch event.Type {
+			case events.ProjectCreated, events.ProjectUpdated:
+				if event.ExpectedEndDate != nil {
+					analytics.ExpectedEndDate = *event.ExpectedEndDate
+				}
+			case events.ProjectDeleted:
+				deleted = true
+			}
+			continue
+		}
+
+		taskID, err := primitive.ObjectIDFromHex(event.TaskID)
+		if err != nil {
+			continue
+		}
+		state, ok := tasks[taskID]
+		if !ok {
+			state = newTaskState(taskID)
+			tasks[taskID] = state
+			order = append(order, taskID)
+		}
+		state.apply(event)
+	}
+
+	var firstCreated, lastCompleted time.Time
+	completed := 0
+	for _, taskID := range order {
+		state := tasks[taskID]
+		if state.projectID != projectID.Hex() || !state.active || state.status == "" {
+			continue
+		}
+		state.advance(now)
+
+		analytics.TotalTasks++
+		analytics.TasksByStatus[state.status]++
+		timeInStatus := make(map[string]time.Duration, len(state.timeInStatus))
+		for status, duration := range state.timeInStatus {
+			timeInStatus[status] = duration.Truncate(time.Second)
+		}
+		analytics.TaskTimeInStatus[taskID] = timeInStatus
+		for _, memberID := range state.members {
+			analytics.UserTaskAssignments[memberID] = append(analytics.UserTaskAssignments[memberID], taskID)
+		}
+
+		if firstCreated.IsZero() || state.createdAt.Before(firstCreated) {
+			firstCreated = state.createdAt
+		}
+		if state.status == statusCompleted {
+			completed++
+			if state.completedAt.After(lastCompleted) {
+				lastCompleted = state.completedAt
+			}
+		}
+	}
+
+	analytics.ForecastCompletion = forecastCompletion(analytics.TotalTasks, completed, firstCreated, lastCompleted, now.Truncate(time.Second))
+	if analytics.ForecastCompletion != nil && !analytics.ExpectedEndDate.IsZero() {
+		analytics.IsCompletedOnTime = !analytics.ForecastCompletion.After(analytics.ExpectedEndDate)
+	}
+	return analytics, deleted
+}
+
+// forecastCompletion procenjuje kada će svi zadaci biti završeni na osnovu dosadašnjeg
+// tempa završavanja. Vraća nil ako nijedan zadatak još nije završen, pa tempo ne postoji.
+func forecastCompletion(total, completed int, firstCreated, lastCompleted, now time.Time) *time.Time {
+	switch {
+	case total == 0:
+		return &now
+	case completed == total:
+		return &lastCompleted
+	case completed == 0 || !now.After(firstCreated):
+		return nil
+	}
+
+	perTask := now.Sub(firstCreated) / time.Duration(completed)
+	forecast := now.Add(perTask * time.Duration(total-completed)).Truncate(time.Second)
+	return &forecast
+}
+
+func containsID(ids []primitive.ObjectID, id primitive.ObjectID) bool {
+	for _, existing := range ids {
+		if existing == id {
+			return true
+		}
+	}
+	return false
+}
+
+func removeID(ids []primitive.ObjectID, id primitive.ObjectID) []primitive.ObjectID {
+	result := ids[:0]
+	for _, existing := range ids {
+		if existing != id {
+			result = append(result, existing)
+		}
+	}
+	return result
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"trello-project/microservices/analytics-service/logging"
+
+	"trello-project/backend/utils/events"
+
+	"github.com/nats-io/nats.go"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// consumerName je trajni JetStream consumer; broker pamti dokle je analytics-service
+// pročitao stream, pa se posle restarta nastavlja od prvog nepotvrđenog događaja.
+const consumerName = "analytics-service"
+
+// replayFetchTimeout je koliko replay čeka sledeću poruku pre nego što odustane.
+const replayFetchTimeout = 10 * time.Second
+
+// storedEvent je događaj u bazi analytics-service-a. ID događaja je _id, pa se isti
+// događaj upisuje samo jednom bez obzira koliko puta je isporučen.
+type storedEvent struct {
+	events.Event `bson:",inline"`
+	ReceivedAt   time.Time `bson:"receivedAt"`
+}
+
+// StartEventConsumer se pretplaćuje na sve domenske događaje. Dok broker nije dostupan,
+// pretplata se ponavlja u pozadini.
+func (s *AnalyticsService) StartEventConsumer(ctx context.Context, retryInterval time.Duration) {
+	go func() {
+		for {
+			err := s.subscribe()
+			if err == nil {
+				logging.Logger.Infof("Event ID: EVENT_CONSUMER_STARTED, Description: Consuming %s as durable consumer '%s'", events.SubjectAll, consumerName)
+				return
+			}
+			logging.Logger.Warnf("Event ID: EVENT_CONSUMER_SUBSCRIBE_FAILED, Description: %v", err)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(retryInterval):
+			}
+		}
+	}()
+}
+
+func (s *AnalyticsService) subscribe() error {
+	if err := events.EnsureStream(s.js); err != nil {
+		return fmt.Errorf("failed to ensure event stream: %v", err)
+	}
+	_, err := s.js.Subscribe(events.SubjectAll, s.handleMessage,
+		nats.BindStream(events.StreamName),
+		nats.Durable(consumerName),
+		nats.DeliverAll(),
+		nats.ManualAck(),
+		nats.AckExplicit(),
+		nats.AckWait(30*time.Second),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %v", events.SubjectAll, err)
+	}
+	return nil
+}
+
+// handleMessage potvrđuje događaj tek kada je upisan; neispravan događaj se odbacuje,
+// a događaj koji nije mogao da se upiše broker isporučuje ponovo.
+func (s *AnalyticsService) handleMessage(msg *nats.Msg) {
+	event, err := decodeEvent(msg.Data)
+	if err != nil {
+		logging.Logger.Errorf("Event ID: EVENT_DECODE_FAILED, Description: Dropping message on %s: %v", msg.Subject, err)
+		msg.Term()
+		return
+	}
+
+	if err := s.storeEvent(context.Background(), event); err != nil {
+		logging.Logger.Errorf("Event ID: EVENT_STORE_FAILED, Description: %v", err)
+		msg.Nak()
+		return
+	}
+	msg.Ack()
+}
+
+func decodeEvent(data []byte) (events.Event, error) {
+	var event events.Event
+	if err := json.Unmarshal(data, &event); err != nil {
+		return event, fmt.Errorf("invalid event payload: %v", err)
+	}
+	if event.ID == "" || event.Type == "" || event.ProjectID == "" {
+		return event, fmt.Errorf("event is missing id, type or projectId")
+	}
+	return event, nil
+}
+
+func (s *AnalyticsService) storeEvent(ctx context.Context, event events.Event) error {
+	_, err := s.events.InsertOne(ctx, storedEvent{Event: event, ReceivedAt: time.Now().UTC()})
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("failed to store event %s: %v", event.ID, err)
+	}
+	return nil
+}
+
+// ReplayEvents ponovo čita ceo stream od početka i upisuje događaje koji nedostaju. Sa
+// reset=true prvo briše sve sačuvane događaje, pa se podaci grade iznova samo iz stream-a.
+func (s *AnalyticsService) ReplayEvents(ctx context.Context, reset bool) (int, error) {
+	if err := events.EnsureStream(s.js); err != nil {
+		return 0, fmt.Errorf("failed to ensure event stream: %v", err)
+	}
+	info, err := s.js.StreamInfo(events.StreamName)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read event stream info: %v", err)
+	}
+
+	if reset {
+		if _, err := s.events.DeleteMany(ctx, bson.M{}); err != nil {
+			return 0, fmt.Errorf("failed to clear stored events: %v", err)
+		}
+		logging.Logger.Warn("Event ID: EVENT_STORE_RESET, Description: Stored events cleared before replay")
+	}
+	if info.State.Msgs == 0 {
+		return 0, nil
+	}
+
+	sub, err := s.js.SubscribeSync(events.SubjectAll, nats.BindStream(events.StreamName), nats.OrderedConsumer(), nats.DeliverAll())
+	if err != nil {
+		return 0, fmt.Errorf("failed to start replay: %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	replayed := 0
+	for {
+		if ctx.Err() != nil {
+			return replayed, ctx.Err()
+		}
+		msg, err := sub.NextMsg(replayFetchTimeout)
+		if err != nil {
+			return replayed, fmt.Errorf("replay stopped after %d events: %v", replayed, err)
+		}
+		if event, err := decodeEvent(msg.Data); err != nil {
+			logging.Logger.Warnf("Event ID: EVENT_DECODE_FAILED, Description: Skipping message on %s during replay: %v", msg.Subject, err)
+		} else if err := s.storeEvent(ctx, event); err != nil {
+			return replayed, err
+		} else {
+			replayed++
+		}
+
+		meta, err := msg.Metadata()
+		if err != nil {
+			return replayed, fmt.Errorf("failed to read message metadata: %v", err)
+		}
+		if meta.Sequence.Stream >= info.State.LastSeq {
+			break
+		}
+	}
+
+	logging.Logger.Infof("Event ID: EVENTS_REPLAYED, Description: Replayed %d events up to stream sequence %d", replayed, info.State.LastSeq)
+	return replayed, nil
+}
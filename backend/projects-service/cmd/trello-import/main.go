@@ -0,0 +1,95 @@
+// Komanda trello-import šalje Trello JSON izvoz table na uvozni endpoint i ispisuje izveštaj.
+//
+// Primer:
+//
+//	trello-import -file board.json -token "$TOKEN" -dry-run -list-map "QA=In progress"
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+type listMapFlag []string
+
+func (l *listMapFlag) String() string     { return strings.Join(*l, ", ") }
+func (l *listMapFlag) Set(v string) error { *l = append(*l, v); return nil }
+
+func main() {
+	var listMap listMapFlag
+	file := flag.String("file", "", "path to the Trello board JSON export")
+	apiURL := flag.String("url", "http://localhost:8000", "API gateway (or projects-service) base URL")
+	token := flag.String("token", os.Getenv("TRELLO_IMPORT_TOKEN"), "manager JWT; defaults to $TRELLO_IMPORT_TOKEN")
+	dryRun := flag.Bool("dry-run", false, "only report what would be imported")
+	projectName := flag.String("project-name", "", "project name (defaults to the board name)")
+	endDate := flag.String("end-date", "", "expected project end date, YYYY-MM-DD")
+	maxMembers := flag.Int("max-members", 0, "maximum project members (defaults to matched members)")
+	flag.Var(&listMap, "list-map", "list to status mapping, e.g. \"QA=In progress\" (repeatable)")
+	flag.Parse()
+
+	if *file == "" || *token == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	export, err := os.ReadFile(*file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read export: %v\n", err)
+		os.Exit(1)
+	}
+
+	query := url.Values{}
+	if *dryRun {
+		query.Set("dryRun", "true")
+	}
+	if *projectName != "" {
+		query.Set("projectName", *projectName)
+	}
+	if *endDate != "" {
+		query.Set("expectedEndDate", *endDate)
+	}
+	if *maxMembers > 0 {
+		query.Set("maxMembers", fmt.Sprint(*maxMembers))
+	}
+	for _, entry := range listMap {
+		query.Add("listMap", entry)
+	}
+
+	endpoint := fmt.Sprintf("%s/api/projects/import/trello?%s", strings.TrimRight(*apiURL, "/"), query.Encode())
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(export))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create request: %v\n", err)
+		os.Exit(1)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+strings.TrimPrefix(*token, "Bearer "))
+
+	client := &http.Client{Timeout: 5 * time.Minute}
+	resp, err := client.Do(req)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "import request failed: %v\n", err)
+		os.Exit(1)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		fmt.Fprintf(os.Stderr, "import failed (%s): %s\n", resp.Status, strings.TrimSpace(string(body)))
+		os.Exit(1)
+	}
+
+	var report bytes.Buffer
+	if err := json.Indent(&report, body, "", "  "); err != nil {
+		os.Stdout.Write(body)
+		return
+	}
+	fmt.Println(report.String())
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+
+	"trello-project/microservices/projects-service/logging"
+	"trello-project/microservices/projects-service/models"
+	"trello-project/microservices/projects-service/services"
+
+	"github.com/gorilla/mux"
+)
+
+// GetCalendarFeedHandler - korisnik dobija URL svog kalendarskog feed-a
+func (h *ProjectHandler) GetCalendarFeedHandler(w http.ResponseWriter, r *http.Request) {
+	h.writeCalendarFeed(w, r, h.Service.GetCalendarFeed)
+}
+
+// RotateCalendarFeedHandler - korisnik dobija novi URL feed-a, a stari prestaje da važi
+func (h *ProjectHandler) RotateCalendarFeedHandler(w http.ResponseWriter, r *http.Request) {
+	h.writeCalendarFeed(w, r, h.Service.RotateCalendarFeed)
+}
+
+// RevokeCalendarFeedHandler - korisnik gasi svoj kalendarski feed
+func (h *ProjectHandler) RevokeCalendarFeedHandler(w http.ResponseWriter, r *http.Request) {
+	username := requestUsername(r)
+	if username == "" {
+		http.Error(w, "Authorization token required", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.Service.RevokeCalendarFeed(r.Context(), username); err != nil {
+		if errors.Is(err, services.ErrCalendarFeedNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CalendarFeedHandler - ICS feed za kalendarske klijente. Ne traži JWT; pristup daje
+// tajni token iz URL-a, pa se na nepoznat token odgovara samo sa 404.
+func (h *ProjectHandler) CalendarFeedHandler(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimSuffix(mux.Vars(r)["token"], ".ics")
+
+	calendar, err := h.Service.RenderCalendarFeed(r.Context(), token)
+	if err != nil {
+		if errors.Is(err, services.ErrCalendarFeedNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		logging.Logger.Errorf("Failed to render calendar feed: %v", err)
+		http.Error(w, "Failed to render calendar feed", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="deadlines.ics"`)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Write(calendar)
+}
+
+func (h *ProjectHandler) writeCalendarFeed(w http.ResponseWriter, r *http.Request, load func(ctx context.Context, username string) (*models.CalendarFeed, error)) {
+	username := requestUsername(r)
+	if username == "" {
+		http.Error(w, "Authorization token required", http.StatusUnauthorized)
+		return
+	}
+
+	feed, err := load(r.Context(), username)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	// CALENDAR_FEED_BASE_URL je javna adresa gateway-a; bez nje se vraća relativna putanja.
+	feed.FeedURL = strings.TrimRight(os.Getenv("CALENDAR_FEED_BASE_URL"), "/") + "/api/calendar/feed/" + feed.Token + ".ics"
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(feed)
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"trello-project/microservices/projects-service/logging"
+	"trello-project/microservices/projects-service/models"
+	"trello-project/microservices/projects-service/utils"
+
+	"trello-project/backend/utils/projectroles"
+
+	"github.com/gorilla/mux"
+)
+
+// InviteMembersHandler šalje pozive korisnicima da se pridruže projektu
+func (h *ProjectHandler) InviteMembersHandler(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["projectId"]
+	if !h.authorizeProject(w, r, projectID, projectroles.Maintainer, []string{"manager"}) {
+		return
+	}
+
+	var request struct {
+		Usernames []string `json:"usernames"`
+		Role      string   `json:"role"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	if len(request.Usernames) == 0 {
+		http.Error(w, "No usernames provided", http.StatusBadRequest)
+		return
+	}
+
+	results, err := h.Service.InviteMembers(r.Context(), projectID, request.Usernames, request.Role, requestUsername(r))
+	if err != nil {
+		logging.Logger.Warnf("Failed to invite members to project %s: %v", projectID, err)
+		writeInvitationError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(results)
+}
+
+// ListProjectInvitationsHandler vraća pozive projekta (?status=pending za one na čekanju)
+func (h *ProjectHandler) ListProjectInvitationsHandler(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["projectId"]
+	if !h.authorizeProject(w, r, projectID, projectroles.Maintainer, []string{"manager"}) {
+		return
+	}
+
+	status := models.InvitationStatus(r.URL.Query().Get("status"))
+	invitations, err := h.Service.ListProjectInvitations(r.Context(), projectID, status)
+	if err != nil {
+		writeInvitationError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(invitations)
+}
+
+// RevokeInvitationHandler povlači poziv na čekanju
+func (h *ProjectHandler) RevokeInvitationHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectID := vars["projectId"]
+	if !h.authorizeProject(w, r, projectID, projectroles.Maintainer, []string{"manager"}) {
+		return
+	}
+
+	if err := h.Service.RevokeInvitation(r.Context(), projectID, vars["invitationId"]); err != nil {
+		writeInvitationError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Invitation revoked successfully"})
+}
+
+// ListMyInvitationsHandler vraća pozive na čekanju za korisnika iz tokena
+func (h *ProjectHandler) ListMyInvitationsHandler(w http.ResponseWriter, r *http.Request) {
+	username := requestUsername(r)
+	if username == "" {
+		http.Error(w, "Authorization token required", http.StatusUnauthorized)
+		return
+	}
+
+	invitations, err := h.Service.ListUserInvitations(r.Context(), username)
+	if err != nil {
+		writeInvitationError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(invitations)
+}
+
+// AcceptInvitationHandler - pozvani korisnik prihvata poziv i postaje član projekta
+func (h *ProjectHandler) AcceptInvitationHandler(w http.ResponseWriter, r *http.Request) {
+	username := requestUsername(r)
+	if username == "" {
+		http.Error(w, "Authorization token required", http.StatusUnauthorized)
+		return
+	}
+
+	invitation, err := h.Service.AcceptInvitation(r.Context(), mux.Vars(r)["invitationId"], username)
+	if err != nil {
+		logging.Logger.Warnf("User %s failed to accept invitation %s: %v", username, mux.Vars(r)["invitationId"], err)
+		writeInvitationError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(invitation)
+}
+
+// DeclineInvitationHandler - pozvani korisnik odbija poziv
+func (h *ProjectHandler) DeclineInvitationHandler(w http.ResponseWriter, r *http.Request) {
+	username := requestUsername(r)
+	if username == "" {
+		http.Error(w, "Authorization token required", http.StatusUnauthorized)
+		return
+	}
+
+	invitation, err := h.Service.DeclineInvitation(r.Context(), mux.Vars(r)["invitationId"], username)
+	if err != nil {
+		writeInvitationError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(invitation)
+}
+
+// requestUsername vraća korisničko ime iz Authorization tokena ili prazan string.
+func requestUsername(r *http.Request) string {
+	tokenString := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if tokenString == "" {
+		return ""
+	}
+	username, err := utils.ExtractManagerUsernameFromToken(tokenString)
+	if err != nil {
+		return ""
+	}
+	return username
+}
+
+func writeInvitationError(w http.ResponseWriter, err error) {
+	switch {
+	case err.Error() == "project not found", err.Error() == "invitation not found":
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case err.Error() == "maximum number of members reached for the project", err.Error() == "user is already a member of the project",
+		err.Error() == "invitation has expired", strings.HasPrefix(err.Error(), "invitation is"), strings.HasPrefix(err.Error(), "only"),
+		errors.Is(err, projectroles.ErrProjectArchived):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case strings.HasPrefix(err.Error(), "failed"), strings.HasPrefix(err.Error(), "error"):
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	default:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"trello-project/microservices/projects-service/logging"
+	"trello-project/microservices/projects-service/models"
+
+	"trello-project/backend/utils/projectroles"
+
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	defaultActivityPageSize = 20
+	maxActivityPageSize     = 100
+)
+
+// GetProjectActivityHandler - feed aktivnosti projekta, od najnovije. Filteri: type (može
+// više, odvojeno zarezom), taskId, memberId, actor, since i until (RFC3339), uz page i pageSize.
+func (h *ProjectHandler) GetProjectActivityHandler(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["id"]
+	if !h.authorizeProject(w, r, projectID, projectroles.Viewer, []string{"manager", "member"}) {
+		return
+	}
+
+	filter, err := parseActivityFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	page, err := h.Service.ListProjectActivity(r.Context(), projectID, filter)
+	if err != nil {
+		if err.Error() == "invalid project ID format" {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		logging.Logger.Errorf("Failed to fetch activity of project %s: %v", projectID, err)
+		http.Error(w, "Failed to fetch activity", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
+
+// RecordActivityHandler - interni ulaz kojim tasks-service upisuje aktivnosti zadataka u feed
+func (h *ProjectHandler) RecordActivityHandler(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["id"]
+	if !authorizeInternalCall(w, r) {
+		return
+	}
+
+	projectObjectID, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		http.Error(w, "invalid project ID format", http.StatusBadRequest)
+		return
+	}
+	var activity models.ProjectActivity
+	if err := json.NewDecoder(r.Body).Decode(&activity); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	activity.ProjectID = projectObjectID
+
+	if err := h.Service.RecordActivity(r.Context(), activity); err != nil {
+		if strings.HasPrefix(err.Error(), "unknown activity type") {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+func parseActivityFilter(r *http.Request) (models.ActivityFilter, error) {
+	query := r.URL.Query()
+	filter := models.ActivityFilter{Page: 1, PageSize: defaultActivityPageSize, Actor: query.Get("actor")}
+
+	for _, value := range query["type"] {
+		for _, name := range strings.Split(value, ",") {
+			activityType := models.ActivityType(strings.TrimSpace(name))
+			if !models.ValidActivityType(activityType) {
+				return filter, fmt.Errorf("invalid type: %s", name)
+			}
+			filter.Types = append(filter.Types, activityType)
+		}
+	}
+	for param, target := range map[string]**primitive.ObjectID{"taskId": &filter.TaskID, "memberId": &filter.MemberID} {
+		if value := query.Get(param); value != "" {
+			id, err := primitive.ObjectIDFromHex(value)
+			if err != nil {
+				return filter, fmt.Errorf("invalid %s: %s", param, value)
+			}
+			*target = &id
+		}
+	}
+	for param, target := range map[string]**time.Time{"since": &filter.Since, "until": &filter.Until} {
+		if value := query.Get(param); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return filter, fmt.Errorf("invalid %s: %s", param, value)
+			}
+			*target = &parsed
+		}
+	}
+	if value := query.Get("page"); value != "" {
+		page, err := strconv.Atoi(value)
+		if err != nil || page < 1 {
+			return filter, fmt.Errorf("invalid page: %s", value)
+		}
+		filter.Page = page
+	}
+	if value := query.Get("pageSize"); value != "" {
+		size, err := strconv.Atoi(value)
+		if err != nil || size < 1 || size > maxActivityPageSize {
+			return filter, fmt.Errorf("invalid pageSize: %s", value)
+		}
+		filter.PageSize = size
+	}
+	return filter, nil
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"trello-project/microservices/projects-service/logging"
+
+	"trello-project/backend/utils/projectroles"
+
+	"github.com/gorilla/mux"
+)
+
+// ArchiveProjectHandler - vlasnik arhivira završen projekat
+func (h *ProjectHandler) ArchiveProjectHandler(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["projectId"]
+	if !h.authorizeArchivedProject(w, r, projectID, projectroles.Owner, []string{"manager"}) {
+		return
+	}
+
+	project, err := h.Service.ArchiveProject(r.Context(), projectID)
+	if err != nil {
+		writeArchiveError(w, projectID, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(project)
+}
+
+// UnarchiveProjectHandler - vlasnik vraća projekat iz arhive
+func (h *ProjectHandler) UnarchiveProjectHandler(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["projectId"]
+	if !h.authorizeArchivedProject(w, r, projectID, projectroles.Owner, []string{"manager"}) {
+		return
+	}
+
+	project, err := h.Service.UnarchiveProject(r.Context(), projectID)
+	if err != nil {
+		writeArchiveError(w, projectID, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(project)
+}
+
+func writeArchiveError(w http.ResponseWriter, projectID string, err error) {
+	switch {
+	case err.Error() == "project not found":
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case err.Error() == "invalid project ID format":
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case err.Error() == "project is already archived", err.Error() == "project is not archived":
+		http.Error(w, err.Error(), http.StatusConflict)
+	case strings.HasPrefix(err.Error(), "failed"), strings.HasPrefix(err.Error(), "error"):
+		logging.Logger.Errorf("Failed to change archive state of project %s: %v", projectID, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	default:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	}
+}
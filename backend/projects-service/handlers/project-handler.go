@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -12,6 +13,8 @@ import (
 	"trello-project/microservices/projects-service/services"
 	"trello-project/microservices/projects-service/utils"
 
+	"trello-project/backend/utils/projectroles"
+
 	"github.com/gorilla/mux"
 	// "go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -155,76 +158,13 @@ func (h *ProjectHandler) CreateProject(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(createdProject)
 }
 
-func (h *ProjectHandler) AddMemberToProjectHandler(w http.ResponseWriter, r *http.Request) {
-	// Provera da li je korisnik menadžer
-	if err := checkRole(r, []string{"manager"}); err != nil {
-		logging.Logger.Warnf("Access forbidden for AddMemberToProjectHandler: %v", err)
-		http.Error(w, err.Error(), http.StatusForbidden)
-		return
-	}
-
-	// Dobavljanje projectID iz URL-a
-	vars := mux.Vars(r)
-	projectID, err := primitive.ObjectIDFromHex(vars["id"])
-	if err != nil {
-		logging.Logger.Warnf("Invalid project ID for AddMemberToProjectHandler: %v", err)
-		http.Error(w, "Invalid project ID", http.StatusBadRequest)
-		return
-	}
-	logging.Logger.Infof("Attempting to add members to project ID: %s", projectID.Hex())
-
-	// Parsiranje JSON zahteva
-	var request struct {
-		Usernames []string `json:"usernames"`
-	}
-
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		logging.Logger.Warnf("Invalid members data payload for AddMemberToProjectHandler: %v", err)
-		http.Error(w, "Invalid members data", http.StatusBadRequest)
-		return
-	}
-
-	// Provera da li postoji bar jedan username za dodavanje
-	if len(request.Usernames) == 0 {
-		logging.Logger.Warn("No usernames provided for AddMemberToProjectHandler")
-		http.Error(w, "No usernames provided", http.StatusBadRequest)
-		return
-	}
-
-	// Poziv servisa za dodavanje članova pomoću username-a
-	err = h.Service.AddMembersToProject(projectID, request.Usernames)
-	if err != nil {
-		switch err.Error() {
-		case "all provided members are already part of the project":
-			logging.Logger.Warnf("One or more members already on project %s: %v", projectID.Hex(), err)
-			http.Error(w, "One or more members are already on the project", http.StatusBadRequest)
-		case "maximum number of members reached for the project":
-			logging.Logger.Warnf("Maximum members reached for project %s: %v", projectID.Hex(), err)
-			http.Error(w, err.Error(), http.StatusBadRequest)
-		case "you need to add at least the minimum required members to the project":
-			logging.Logger.Warnf("Minimum required members not met for project %s: %v", projectID.Hex(), err)
-			http.Error(w, err.Error(), http.StatusBadRequest)
-		default:
-			logging.Logger.Errorf("Failed to add members to project %s: %v", projectID.Hex(), err)
-			http.Error(w, "Failed to add members to project", http.StatusInternalServerError)
-		}
-		return
-	}
-
-	logging.Logger.Infof("Members %v added successfully to project %s", request.Usernames, projectID.Hex())
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(`{"message": "Members added successfully"}`))
-}
-
 // GetProjectMembersHandler retrieves the members of a specified project
 func (h *ProjectHandler) GetProjectMembersHandler(w http.ResponseWriter, r *http.Request) {
-	if err := checkRole(r, []string{"manager", "member"}); err != nil {
-		logging.Logger.Warnf("Access forbidden for GetProjectMembersHandler: %v", err)
-		http.Error(w, err.Error(), http.StatusForbidden)
-		return
-	}
 	vars := mux.Vars(r)
 	projectID := vars["projectId"]
+	if !h.authorizeProject(w, r, projectID, projectroles.Viewer, []string{"manager", "member"}) {
+		return
+	}
 
 	logging.Logger.Debugf("Fetching members for project: %s", projectID)
 	members, err := h.Service.GetProjectMembers(r.Context(), projectID)
@@ -240,25 +180,28 @@ func (h *ProjectHandler) GetProjectMembersHandler(w http.ResponseWriter, r *http
 
 // RemoveMemberFromProjectHandler removes a member from a project if they have no in-progress tasks
 func (h *ProjectHandler) RemoveMemberFromProjectHandler(w http.ResponseWriter, r *http.Request) {
-	if err := checkRole(r, []string{"manager"}); err != nil {
-		logging.Logger.Warnf("Access forbidden for RemoveMemberFromProjectHandler: %v", err)
-		http.Error(w, err.Error(), http.StatusForbidden)
-		return
-	}
 	logging.Logger.Debugf("Request received to remove member: %s", r.URL.Path)
 
 	vars := mux.Vars(r)
 	projectID := vars["projectId"]
 	memberID := vars["memberId"]
+	if !h.authorizeProject(w, r, projectID, projectroles.Maintainer, []string{"manager"}) {
+		return
+	}
 
 	logging.Logger.Debugf("Extracted projectID: %s, memberID: %s", projectID, memberID)
 
-	err := h.Service.RemoveMemberFromProject(r.Context(), projectID, memberID)
+	// ?confirm=true dozvoljava uklanjanje koje projekat spušta ispod minimalnog broja članova
+	confirmed := r.URL.Query().Get("confirm") == "true"
+
+	err := h.Service.RemoveMemberFromProject(r.Context(), projectID, memberID, confirmed)
 	if err != nil {
 		logging.Logger.Errorf("Error during member removal from project %s, member %s: %v", projectID, memberID, err)
-		if err.Error() == "cannot remove member assigned to an in-progress task" {
+		if errors.Is(err, services.ErrBelowMinMembers) {
+			http.Error(w, err.Error()+"; retry with ?confirm=true to proceed", http.StatusConflict)
+		} else if err.Error() == "cannot remove member assigned to an active task" {
 			http.Error(w, err.Error(), http.StatusForbidden)
-		} else if err.Error() == "project not found" {
+		} else if err.Error() == "project not found" || err.Error() == "member not found in project or already removed" {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
 			http.Error(w, "Failed to remove member from project", http.StatusInternalServerError)
@@ -296,8 +239,12 @@ func (h *ProjectHandler) ListProjectsHandler(w http.ResponseWriter, r *http.Requ
 
 	logging.Logger.Debug("Fetching all projects...")
 
-	projects, err := h.Service.GetAllProjects()
+	projects, err := h.Service.GetAllProjects(r.URL.Query().Get("archived"))
 	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid archived filter") {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		logging.Logger.Errorf("Error fetching projects from service: %v", err)
 		http.Error(w, "Error fetching projects", http.StatusInternalServerError)
 		return
@@ -311,13 +258,11 @@ func (h *ProjectHandler) ListProjectsHandler(w http.ResponseWriter, r *http.Requ
 
 // GetProjectByIDHandler - Dohvata projekat po ID-ju
 func (h *ProjectHandler) GetProjectByIDHandler(w http.ResponseWriter, r *http.Request) {
-	if err := checkRole(r, []string{"manager", "member"}); err != nil {
-		logging.Logger.Warnf("Access forbidden for GetProjectByIDHandler: %v", err)
-		http.Error(w, err.Error(), http.StatusForbidden)
-		return
-	}
 	vars := mux.Vars(r)
 	projectID := vars["id"]
+	if !h.authorizeProject(w, r, projectID, projectroles.Viewer, []string{"manager", "member"}) {
+		return
+	}
 	logging.Logger.Debugf("Fetching project by ID: %s", projectID)
 
 	project, err := h.Service.GetProjectByID(projectID)
@@ -338,14 +283,11 @@ func (h *ProjectHandler) GetProjectByIDHandler(w http.ResponseWriter, r *http.Re
 }
 
 func (h *ProjectHandler) DisplayTasksForProjectHandler(w http.ResponseWriter, r *http.Request) {
-	if err := checkRole(r, []string{"manager", "member"}); err != nil {
-		logging.Logger.Warnf("Access forbidden for DisplayTasksForProjectHandler: %v", err)
-		http.Error(w, err.Error(), http.StatusForbidden)
-		return
-	}
-
 	vars := mux.Vars(r)
 	projectID := vars["id"]
+	if !h.authorizeProject(w, r, projectID, projectroles.Viewer, []string{"manager", "member"}) {
+		return
+	}
 	if projectID == "" {
 		logging.Logger.Warn("Invalid project ID provided for DisplayTasksForProjectHandler")
 		http.Error(w, "Invalid project ID", http.StatusBadRequest)
@@ -392,8 +334,12 @@ func GetProjectsByUsername(s *services.ProjectService) http.HandlerFunc {
 
 		logging.Logger.Infof("Fetching projects for username: %s", username)
 
-		projects, err := s.GetProjectsByUsername(username)
+		projects, err := s.GetProjectsByUsername(username, r.URL.Query().Get("archived"))
 		if err != nil {
+			if strings.HasPrefix(err.Error(), "invalid archived filter") {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
 			logging.Logger.Errorf("Error fetching projects for username %s: %v", username, err)
 			http.Error(w, fmt.Sprintf("Error fetching projects: %v", err), http.StatusInternalServerError)
 			return
@@ -408,28 +354,78 @@ func GetProjectsByUsername(s *services.ProjectService) http.HandlerFunc {
 	}
 }
 
+// RemoveProjectHandler pokreće brisanje projekta. Brisanje se izvršava u pozadini, a
+// njegov tok menadžer prati preko GetProjectDeletionHandler-a.
 func (h *ProjectHandler) RemoveProjectHandler(w http.ResponseWriter, r *http.Request) {
-	if err := checkRole(r, []string{"manager"}); err != nil {
-		logging.Logger.Warnf("Access forbidden for RemoveProjectHandler: insufficient permissions. Error: %v", err)
-		http.Error(w, "Access forbidden: insufficient permissions", http.StatusForbidden)
-		return
-	}
-
 	vars := mux.Vars(r)
 	projectID := vars["projectId"]
+	if !h.authorizeArchivedProject(w, r, projectID, projectroles.Owner, []string{"manager"}) {
+		return
+	}
 
 	logging.Logger.Infof("Received request to delete project with ID: %s", projectID)
 
-	err := h.Service.DeleteProjectAndTasks(r.Context(), projectID, r)
+	requestedBy := ""
+	if tokenString := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "); tokenString != "" {
+		requestedBy, _ = utils.ExtractManagerUsernameFromToken(tokenString)
+	}
+
+	saga, err := h.Service.StartProjectDeletion(r.Context(), projectID, requestedBy)
 	if err != nil {
-		logging.Logger.Errorf("Failed to delete project and tasks for ID %s: %v", projectID, err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		logging.Logger.Errorf("Failed to start deletion of project %s: %v", projectID, err)
+		switch err.Error() {
+		case "project not found":
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case "invalid project ID format":
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
 		return
 	}
 
-	logging.Logger.Infof("Project and related tasks deleted successfully for ID: %s", projectID)
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{"message": "Project and related tasks deleted successfully"})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(saga)
+}
+
+// GetProjectDeletionHandler vraća stanje brisanja projekta. Projekat je tokom brisanja
+// zaključan, a posle brisanja više ne postoji, pa pristup ima menadžer koji je pokrenuo
+// brisanje.
+func (h *ProjectHandler) GetProjectDeletionHandler(w http.ResponseWriter, r *http.Request) {
+	if err := checkRole(r, []string{"manager"}); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	projectID := mux.Vars(r)["projectId"]
+
+	saga, err := h.Service.GetProjectDeletion(r.Context(), projectID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrDeletionNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case err.Error() == "invalid project ID format":
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if tokenString := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "); tokenString != "" {
+		username, err := utils.ExtractManagerUsernameFromToken(tokenString)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if saga.RequestedBy != "" && saga.RequestedBy != username {
+			http.Error(w, "Access forbidden: deletion was requested by another manager", http.StatusForbidden)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(saga)
 }
 
 func (h *ProjectHandler) GetAllMembersHandler(w http.ResponseWriter, r *http.Request) {
@@ -448,14 +444,11 @@ func (h *ProjectHandler) GetAllMembersHandler(w http.ResponseWriter, r *http.Req
 }
 
 func (h *ProjectHandler) AddTaskToProjectHandler(w http.ResponseWriter, r *http.Request) {
-	if err := checkRole(r, []string{"manager"}); err != nil {
-		logging.Logger.Warnf("Access forbidden for AddTaskToProjectHandler: %v", err)
-		http.Error(w, err.Error(), http.StatusForbidden)
-		return
-	}
-
 	vars := mux.Vars(r)
 	projectID := vars["projectId"]
+	if !h.authorizeProject(w, r, projectID, projectroles.Maintainer, []string{"manager"}) {
+		return
+	}
 	logging.Logger.Infof("Attempting to add task to project ID: %s", projectID)
 
 	var request struct {
@@ -475,6 +468,10 @@ func (h *ProjectHandler) AddTaskToProjectHandler(w http.ResponseWriter, r *http.
 	}
 
 	err := h.Service.AddTaskToProject(projectID, request.TaskID)
+	if errors.Is(err, projectroles.ErrProjectArchived) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
 	if err != nil {
 		logging.Logger.Errorf("Failed to add task %s to project %s: %v", request.TaskID, projectID, err)
 		http.Error(w, fmt.Sprintf("Failed to add task to project: %v", err), http.StatusInternalServerError)
@@ -486,6 +483,30 @@ func (h *ProjectHandler) AddTaskToProjectHandler(w http.ResponseWriter, r *http.
 	w.Write([]byte(`{"message": "Task added to project successfully"}`))
 }
 
+func (h *ProjectHandler) RemoveTaskFromProjectHandler(w http.ResponseWriter, r *http.Request) {
+
+	vars := mux.Vars(r)
+	projectID := vars["projectId"]
+	taskID := vars["taskId"]
+	if !h.authorizeProject(w, r, projectID, projectroles.Maintainer, []string{"manager"}) {
+		return
+	}
+	logging.Logger.Infof("Attempting to remove task %s from project ID: %s", taskID, projectID)
+
+	if err := h.Service.RemoveTaskFromProject(projectID, taskID); err != nil {
+		logging.Logger.Errorf("Failed to remove task %s from project %s: %v", taskID, projectID, err)
+		if strings.Contains(err.Error(), "no project found") {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to remove task from project: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"message": "Task removed from project successfully"}`))
+}
+
 func (h *ProjectHandler) GetUserProjectsHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	userID := vars["userID"]
@@ -531,6 +552,11 @@ func (h *ProjectHandler) RemoveUserFromProjectsHandler(w http.ResponseWriter, r
 	}
 
 	err := h.Service.RemoveUserFromProjects(userID, role, authToken)
+	if errors.Is(err, services.ErrOwnedProjectsRemain) {
+		logging.Logger.Warnf("User %s still owns projects: %v", userID, err)
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
 	if err != nil {
 		logging.Logger.Errorf("Failed to remove user %s from projects: %v", userID, err)
 		http.Error(w, fmt.Sprintf("Failed to remove user from projects: %v", err), http.StatusBadRequest)
@@ -541,3 +567,169 @@ func (h *ProjectHandler) RemoveUserFromProjectsHandler(w http.ResponseWriter, r
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("User successfully removed from all projects"))
 }
+
+// ImportTrelloBoardHandler uvozi Trello JSON izvoz table kao novi projekat.
+// Telo zahteva je sam izvoz; opcije se prosleđuju kroz query parametre:
+// dryRun, projectName, expectedEndDate, maxMembers i listMap ("Naziv liste=Status", može više puta).
+func (h *ProjectHandler) ImportTrelloBoardHandler(w http.ResponseWriter, r *http.Request) {
+	if err := checkRole(r, []string{"manager"}); err != nil {
+		logging.Logger.Warnf("Access forbidden for ImportTrelloBoardHandler: %v", err)
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	tokenString := r.Header.Get("Authorization")
+	if tokenString == "" {
+		http.Error(w, "Authorization token required", http.StatusUnauthorized)
+		return
+	}
+	username, err := utils.ExtractManagerUsernameFromToken(strings.TrimPrefix(tokenString, "Bearer "))
+	if err != nil {
+		logging.Logger.Errorf("Failed to extract manager username from token: %v", err)
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	query := r.URL.Query()
+	options := services.TrelloImportOptions{
+		DryRun:          query.Get("dryRun") == "true",
+		ProjectName:     query.Get("projectName"),
+		ListMapping:     map[string]string{},
+		ManagerUsername: username,
+		AuthToken:       tokenString,
+	}
+	if value := query.Get("expectedEndDate"); value != "" {
+		endDate, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			endDate, err = time.Parse("2006-01-02", value)
+		}
+		if err != nil {
+			http.Error(w, "Invalid expectedEndDate", http.StatusBadRequest)
+			return
+		}
+		options.ExpectedEndDate = &endDate
+	}
+	if value := query.Get("maxMembers"); value != "" {
+		if _, err := fmt.Sscanf(value, "%d", &options.MaxMembers); err != nil {
+			http.Error(w, "Invalid maxMembers", http.StatusBadRequest)
+			return
+		}
+	}
+	for _, entry := range query["listMap"] {
+		list, status, ok := strings.Cut(entry, "=")
+		if !ok {
+			http.Error(w, "listMap entries must look like 'List name=Status'", http.StatusBadRequest)
+			return
+		}
+		options.ListMapping[list] = status
+	}
+
+	var board models.TrelloBoard
+	if err := json.NewDecoder(r.Body).Decode(&board); err != nil {
+		logging.Logger.Warnf("Invalid Trello export payload: %v", err)
+		http.Error(w, "Invalid Trello export", http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.Service.ImportTrelloBoard(board, options)
+	if err != nil {
+		logging.Logger.Errorf("Trello import failed: %v", err)
+		if err.Error() == "project with the same name already exists" {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if report.DryRun {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusCreated)
+	}
+	json.NewEncoder(w).Encode(report)
+}
+
+// UpdateProjectHandler - menadžer menja ime, opis, rok i ograničenja broja članova projekta
+func (h *ProjectHandler) UpdateProjectHandler(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["id"]
+	if !h.authorizeProject(w, r, projectID, projectroles.Owner, []string{"manager"}) {
+		return
+	}
+
+	var update models.ProjectUpdate
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		logging.Logger.Warnf("Invalid request payload for UpdateProjectHandler: %v", err)
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	project, err := h.Service.UpdateProject(r.Context(), projectID, update)
+	if err != nil {
+		switch {
+		case err.Error() == "project not found":
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case err.Error() == "project with the same name already exists", strings.HasPrefix(err.Error(), "maxMembers"):
+			http.Error(w, err.Error(), http.StatusConflict)
+		case strings.HasPrefix(err.Error(), "failed"), strings.HasPrefix(err.Error(), "database error"), strings.HasPrefix(err.Error(), "error"):
+			logging.Logger.Errorf("Failed to update project %s: %v", projectID, err)
+			http.Error(w, "Failed to update project", http.StatusInternalServerError)
+		default:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(project)
+}
+
+// CloneProjectHandler - menadžer pravi novi projekat po uzoru na postojeći
+func (h *ProjectHandler) CloneProjectHandler(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["id"]
+	if err := checkRole(r, []string{"manager"}); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	// Kloniranje samo čita izvorni projekat, pa je dozvoljeno i za arhivirane projekte
+	if !h.authorizeArchivedProject(w, r, projectID, projectroles.Maintainer, []string{"manager"}) {
+		return
+	}
+
+	var clone models.ProjectClone
+	if err := json.NewDecoder(r.Body).Decode(&clone); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	managerUsername := ""
+	if tokenString := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "); tokenString != "" {
+		username, err := utils.ExtractManagerUsernameFromToken(tokenString)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		managerUsername = username
+	}
+
+	report, err := h.Service.CloneProject(r.Context(), projectID, clone, managerUsername)
+	if err != nil {
+		switch {
+		case err.Error() == "project not found":
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case err.Error() == "project with the same name already exists", errors.Is(err, projectroles.ErrProjectDeleting):
+			http.Error(w, err.Error(), http.StatusConflict)
+		case strings.HasPrefix(err.Error(), "failed"), strings.HasPrefix(err.Error(), "database error"), strings.HasPrefix(err.Error(), "error"):
+			logging.Logger.Errorf("Failed to clone project %s: %v", projectID, err)
+			http.Error(w, err.Error(), http.StatusBadGateway)
+		default:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(report)
+}
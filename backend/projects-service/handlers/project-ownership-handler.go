@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"trello-project/microservices/projects-service/logging"
+
+	"trello-project/backend/utils/projectroles"
+
+	"github.com/gorilla/mux"
+)
+
+type ownershipRequest struct {
+	NewOwnerUsername string `json:"newOwnerUsername"`
+}
+
+// TransferOwnershipHandler - vlasnik predaje projekat drugom menadžeru
+func (h *ProjectHandler) TransferOwnershipHandler(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["projectId"]
+	if !h.authorizeArchivedProject(w, r, projectID, projectroles.Owner, []string{"manager"}) {
+		return
+	}
+
+	var request ownershipRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	project, err := h.Service.TransferOwnership(r.Context(), projectID, request.NewOwnerUsername)
+	if err != nil {
+		writeOwnershipError(w, projectID, err)
+		return
+	}
+
+	logging.Logger.Infof("Ownership of project %s transferred to %s", projectID, request.NewOwnerUsername)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(project)
+}
+
+// ListOrphanedProjectsHandler - administrator pregleda projekte bez vlasnika
+func (h *ProjectHandler) ListOrphanedProjectsHandler(w http.ResponseWriter, r *http.Request) {
+	if err := checkRole(r, []string{"admin"}); err != nil {
+		logging.Logger.Warnf("Access forbidden for ListOrphanedProjectsHandler: %v", err)
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	projects, err := h.Service.ListOrphanedProjects(r.Context())
+	if err != nil {
+		logging.Logger.Errorf("Failed to list orphaned projects: %v", err)
+		if strings.HasPrefix(err.Error(), "cannot verify") {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		http.Error(w, "Failed to list orphaned projects", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(projects)
+}
+
+// ReassignOrphanedProjectHandler - administrator dodeljuje projekat bez vlasnika novom menadžeru
+func (h *ProjectHandler) ReassignOrphanedProjectHandler(w http.ResponseWriter, r *http.Request) {
+	if err := checkRole(r, []string{"admin"}); err != nil {
+		logging.Logger.Warnf("Access forbidden for ReassignOrphanedProjectHandler: %v", err)
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	projectID := mux.Vars(r)["projectId"]
+
+	var request ownershipRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	project, err := h.Service.ReassignOrphanedProject(r.Context(), projectID, request.NewOwnerUsername)
+	if err != nil {
+		writeOwnershipError(w, projectID, err)
+		return
+	}
+
+	logging.Logger.Infof("Orphaned project %s reassigned to %s", projectID, request.NewOwnerUsername)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(project)
+}
+
+func writeOwnershipError(w http.ResponseWriter, projectID string, err error) {
+	switch {
+	case err.Error() == "project not found":
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case err.Error() == "project is not orphaned", err.Error() == "project owner changed concurrently":
+		http.Error(w, err.Error(), http.StatusConflict)
+	case strings.HasPrefix(err.Error(), "cannot verify"):
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+	case strings.HasPrefix(err.Error(), "failed to transfer"):
+		logging.Logger.Errorf("Failed to change owner of project %s: %v", projectID, err)
+		http.Error(w, "Failed to transfer ownership", http.StatusInternalServerError)
+	default:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"trello-project/microservices/projects-service/logging"
+	"trello-project/microservices/projects-service/utils"
+
+	"trello-project/backend/utils/projectroles"
+
+	"github.com/gorilla/mux"
+)
+
+// authorizeProject proverava da li pozivalac ima bar ulogu min na projektu. Zahtevi sa
+// korisničkim tokenom (preko gateway-a) proveravaju se po ulozi na projektu, a pozivi
+// drugih servisa, koji ne šalju token, po globalnoj Role zaglavlju iz serviceRoles.
+// Arhiviran projekat je samo za čitanje, pa se izmene na njemu odbijaju.
+func (h *ProjectHandler) authorizeProject(w http.ResponseWriter, r *http.Request, projectID string, min projectroles.Role, serviceRoles []string) bool {
+	return h.authorize(w, r, projectID, min, serviceRoles, false)
+}
+
+// authorizeArchivedProject je authorizeProject za akcije koje su dozvoljene i na
+// arhiviranom projektu: vraćanje iz arhive, prenos vlasništva, brisanje i kloniranje.
+func (h *ProjectHandler) authorizeArchivedProject(w http.ResponseWriter, r *http.Request, projectID string, min projectroles.Role, serviceRoles []string) bool {
+	return h.authorize(w, r, projectID, min, serviceRoles, true)
+}
+
+func (h *ProjectHandler) authorize(w http.ResponseWriter, r *http.Request, projectID string, min projectroles.Role, serviceRoles []string, allowArchived bool) bool {
+	tokenString := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if tokenString == "" {
+		if err := checkRole(r, serviceRoles); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return false
+		}
+		return true
+	}
+
+	username, err := utils.ExtractManagerUsernameFromToken(tokenString)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return false
+	}
+
+	access, err := h.Service.ResolveProjectAccess(r.Context(), projectID, username)
+	if err != nil {
+		writeProjectRoleError(w, err)
+		return false
+	}
+	role := access.Role
+	if !role.AtLeast(min) {
+		logging.Logger.Warnf("User %s with project role '%s' denied access to %s %s (requires %s)", username, role, r.Method, r.URL.Path, min)
+		if role == "" {
+			http.Error(w, projectroles.ErrNoAccess.Error(), http.StatusForbidden)
+		} else {
+			http.Error(w, projectroles.ErrInsufficientRole.Error(), http.StatusForbidden)
+		}
+		return false
+	}
+	if access.Archived && !allowArchived && isMutation(r) {
+		http.Error(w, projectroles.ErrProjectArchived.Error(), http.StatusConflict)
+		return false
+	}
+	return true
+}
+
+// authorizeInternalCall propušta samo pozive drugih servisa: bez korisničkog tokena i sa
+// Role zaglavljem manager. Štiti interne rute i kada se do njih stigne preko gateway-a.
+func authorizeInternalCall(w http.ResponseWriter, r *http.Request) bool {
+	if r.Header.Get("Authorization") != "" {
+		http.Error(w, "internal endpoint", http.StatusForbidden)
+		return false
+	}
+	if err := checkRole(r, []string{"manager"}); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// isMutation vraća true za zahteve koji menjaju podatke.
+func isMutation(r *http.Request) bool {
+	return r.Method != http.MethodGet && r.Method != http.MethodHead && r.Method != http.MethodOptions
+}
+
+// GetProjectRoleHandler vraća ulogu korisnika iz tokena na projektu; koristi ga
+// projectroles.Resolver u drugim servisima.
+func (h *ProjectHandler) GetProjectRoleHandler(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["projectId"]
+	tokenString := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if tokenString == "" {
+		http.Error(w, "Authorization token required", http.StatusUnauthorized)
+		return
+	}
+	username, err := utils.ExtractManagerUsernameFromToken(tokenString)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	access, err := h.Service.ResolveProjectAccess(r.Context(), projectID, username)
+	if err != nil {
+		writeProjectRoleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"projectId": projectID,
+		"username":  username,
+		"role":      string(access.Role),
+		"archived":  access.Archived,
+	})
+}
+
+// SetMemberRoleHandler - vlasnik projekta menja ulogu člana
+func (h *ProjectHandler) SetMemberRoleHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectID := vars["projectId"]
+	if !h.authorizeProject(w, r, projectID, projectroles.Owner, []string{"manager"}) {
+		return
+	}
+
+	var request struct {
+		Role string `json:"role"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Service.SetMemberRole(r.Context(), projectID, vars["memberId"], request.Role); err != nil {
+		switch {
+		case err.Error() == "member not found in project":
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case strings.HasPrefix(err.Error(), "failed"):
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		default:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Member role updated successfully"})
+}
+
+func writeProjectRoleError(w http.ResponseWriter, err error) {
+	switch {
+	case err.Error() == "project not found", errors.Is(err, projectroles.ErrProjectNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, projectroles.ErrProjectDeleting):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case err.Error() == "invalid project ID format":
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		logging.Logger.Errorf("Failed to resolve project role: %v", err)
+		http.Error(w, "Failed to resolve project role", http.StatusServiceUnavailable)
+	}
+}
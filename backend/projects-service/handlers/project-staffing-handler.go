@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"trello-project/microservices/projects-service/logging"
+
+	"trello-project/backend/utils/projectroles"
+
+	"github.com/gorilla/mux"
+)
+
+// GetStaffingReportHandler - popunjenost projekta i opterećenje članova
+func (h *ProjectHandler) GetStaffingReportHandler(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["projectId"]
+	if !h.authorizeProject(w, r, projectID, projectroles.Viewer, []string{"manager", "member"}) {
+		return
+	}
+
+	report, err := h.Service.GetStaffingReport(r.Context(), projectID)
+	if err != nil {
+		switch err.Error() {
+		case "project not found":
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case "invalid project ID format":
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			logging.Logger.Errorf("Failed to build staffing report for project %s: %v", projectID, err)
+			http.Error(w, "Failed to build staffing report", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
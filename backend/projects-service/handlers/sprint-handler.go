@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"trello-project/microservices/projects-service/logging"
+
+	"trello-project/backend/utils/projectroles"
+
+	"github.com/gorilla/mux"
+)
+
+func (h *ProjectHandler) CreateSprintHandler(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["projectId"]
+	if !h.authorizeProject(w, r, projectID, projectroles.Maintainer, []string{"manager"}) {
+		return
+	}
+
+	var request struct {
+		Name      string    `json:"name"`
+		Goal      string    `json:"goal"`
+		StartDate time.Time `json:"startDate"`
+		EndDate   time.Time `json:"endDate"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		logging.Logger.Errorf("Failed to decode sprint request: %v", err)
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	sprint, err := h.Service.CreateSprint(r.Context(), projectID, request.Name, request.Goal, request.StartDate, request.EndDate)
+	if err != nil {
+		writeSprintError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(sprint)
+}
+
+func (h *ProjectHandler) ListSprintsHandler(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["projectId"]
+	if !h.authorizeProject(w, r, projectID, projectroles.Viewer, []string{"manager", "member"}) {
+		return
+	}
+
+	sprints, err := h.Service.ListSprints(r.Context(), projectID)
+	if err != nil {
+		writeSprintError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sprints)
+}
+
+func (h *ProjectHandler) GetSprintHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	if !h.authorizeProject(w, r, vars["projectId"], projectroles.Viewer, []string{"manager", "member"}) {
+		return
+	}
+
+	sprint, err := h.Service.GetSprint(r.Context(), vars["projectId"], vars["sprintId"])
+	if err != nil {
+		writeSprintError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sprint)
+}
+
+func (h *ProjectHandler) StartSprintHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	if !h.authorizeProject(w, r, vars["projectId"], projectroles.Maintainer, []string{"manager"}) {
+		return
+	}
+
+	sprint, err := h.Service.StartSprint(r.Context(), vars["projectId"], vars["sprintId"])
+	if err != nil {
+		logging.Logger.Errorf("Failed to start sprint %s: %v", vars["sprintId"], err)
+		writeSprintError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sprint)
+}
+
+func (h *ProjectHandler) CloseSprintHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	if !h.authorizeProject(w, r, vars["projectId"], projectroles.Maintainer, []string{"manager"}) {
+		return
+	}
+
+	sprint, err := h.Service.CloseSprint(r.Context(), vars["projectId"], vars["sprintId"])
+	if err != nil {
+		logging.Logger.Errorf("Failed to close sprint %s: %v", vars["sprintId"], err)
+		writeSprintError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sprint)
+}
+
+func (h *ProjectHandler) GetVelocityHandler(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["projectId"]
+	if !h.authorizeProject(w, r, projectID, projectroles.Viewer, []string{"manager", "member"}) {
+		return
+	}
+
+	report, err := h.Service.GetVelocity(r.Context(), projectID)
+	if err != nil {
+		writeSprintError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+func writeSprintError(w http.ResponseWriter, err error) {
+	switch {
+	case err.Error() == "project not found", err.Error() == "sprint not found":
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case strings.HasPrefix(err.Error(), "only"):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case strings.HasPrefix(err.Error(), "failed"), strings.HasPrefix(err.Error(), "error"):
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	default:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"trello-project/microservices/projects-service/logging"
+	"trello-project/microservices/projects-service/models"
+	"trello-project/microservices/projects-service/services"
+
+	"trello-project/backend/utils/projectroles"
+
+	"github.com/gorilla/mux"
+)
+
+// CreateWebhookHandler - menadžer registruje webhook projekta; tajna za potpis se vraća samo sada
+func (h *ProjectHandler) CreateWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["projectId"]
+	if err := checkRole(r, []string{"manager"}); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if !h.authorizeProject(w, r, projectID, projectroles.Maintainer, []string{"manager"}) {
+		return
+	}
+
+	var registration models.WebhookRegistration
+	if err := json.NewDecoder(r.Body).Decode(&registration); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	webhook, err := h.Service.CreateWebhook(r.Context(), projectID, registration, requestUsername(r))
+	if err != nil {
+		writeWebhookError(w, projectID, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(webhook)
+}
+
+// ListWebhooksHandler - webhook-ovi projekta
+func (h *ProjectHandler) ListWebhooksHandler(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["projectId"]
+	if !h.authorizeProject(w, r, projectID, projectroles.Maintainer, []string{"manager"}) {
+		return
+	}
+
+	webhooks, err := h.Service.ListWebhooks(r.Context(), projectID)
+	if err != nil {
+		writeWebhookError(w, projectID, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(webhooks)
+}
+
+// DeleteWebhookHandler - menadžer uklanja webhook
+func (h *ProjectHandler) DeleteWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	if err := checkRole(r, []string{"manager"}); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if !h.authorizeProject(w, r, vars["projectId"], projectroles.Maintainer, []string{"manager"}) {
+		return
+	}
+
+	if err := h.Service.DeleteWebhook(r.Context(), vars["projectId"], vars["webhookId"]); err != nil {
+		writeWebhookError(w, vars["projectId"], err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListWebhookDeliveriesHandler - istorija isporuka webhook-a (?limit=, podrazumevano 50)
+func (h *ProjectHandler) ListWebhookDeliveriesHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	if !h.authorizeProject(w, r, vars["projectId"], projectroles.Maintainer, []string{"manager"}) {
+		return
+	}
+
+	limit := int64(50)
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || parsed < 1 || parsed > 500 {
+			http.Error(w, "limit must be between 1 and 500", http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	deliveries, err := h.Service.ListWebhookDeliveries(r.Context(), vars["projectId"], vars["webhookId"], limit)
+	if err != nil {
+		writeWebhookError(w, vars["projectId"], err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(deliveries)
+}
+
+// EmitWebhookEventHandler - interni ulaz kojim tasks-service i workflow-service prijavljuju
+// događaje projekta. Nije izložen preko gateway-a i ne prihvata korisnički token.
+func (h *ProjectHandler) EmitWebhookEventHandler(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["projectId"]
+	if !authorizeInternalCall(w, r) {
+		return
+	}
+
+	var request models.WebhookEventRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Service.EmitWebhookEvent(r.Context(), projectID, request.Event, request.Data); err != nil {
+		writeWebhookError(w, projectID, err)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func writeWebhookError(w http.ResponseWriter, projectID string, err error) {
+	switch {
+	case errors.Is(err, services.ErrWebhookNotFound), err.Error() == "project not found":
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case strings.HasPrefix(err.Error(), "invalid"), strings.HasPrefix(err.Error(), "unknown webhook event"),
+		err.Error() == "at least one event is required":
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		logging.Logger.Errorf("Webhook request on project %s failed: %v", projectID, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
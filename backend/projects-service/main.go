@@ -13,6 +13,7 @@ import (
 	"trello-project/microservices/projects-service/services"
 
 	http_client "trello-project/backend/utils"
+	"trello-project/backend/utils/events"
 
 	"github.com/gorilla/mux"
 	"github.com/joho/godotenv"
@@ -35,6 +36,52 @@ func createProjectNameIndex(collection *mongo.Collection) error {
 	return nil
 }
 
+// createInvitationIndex dozvoljava najviše jedan poziv na čekanju po korisniku i projektu
+func createInvitationIndex(collection *mongo.Collection) error {
+	indexModel := mongo.IndexModel{
+		Keys: bson.D{{Key: "project_id", Value: 1}, {Key: "username", Value: 1}},
+		Options: options.Index().
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"status": "pending"}),
+	}
+	if _, err := collection.Indexes().CreateOne(context.TODO(), indexModel); err != nil {
+		return fmt.Errorf("failed to create invitation index: %v", err)
+	}
+	return nil
+}
+
+func createCalendarFeedIndexes(collection *mongo.Collection) error {
+	indexModels := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "token", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "username", Value: 1}}, Options: options.Index().SetUnique(true)},
+	}
+	if _, err := collection.Indexes().CreateMany(context.TODO(), indexModels); err != nil {
+		return fmt.Errorf("failed to create calendar feed indexes: %v", err)
+	}
+	return nil
+}
+
+func createWebhookDeliveryIndexes(collection *mongo.Collection) error {
+	indexModels := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}}},
+		{Keys: bson.D{{Key: "webhook_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	}
+	if _, err := collection.Indexes().CreateMany(context.TODO(), indexModels); err != nil {
+		return fmt.Errorf("failed to create webhook delivery indexes: %v", err)
+	}
+	return nil
+}
+
+func createActivityIndex(collection *mongo.Collection) error {
+	indexModel := mongo.IndexModel{
+		Keys: bson.D{{Key: "projectId", Value: 1}, {Key: "timestamp", Value: -1}},
+	}
+	if _, err := collection.Indexes().CreateOne(context.TODO(), indexModel); err != nil {
+		return fmt.Errorf("failed to create activity index: %v", err)
+	}
+	return nil
+}
+
 func main() {
 	logging.InitLogger() // Inicijalizacija logovanja
 
@@ -100,12 +147,53 @@ func main() {
 		},
 	})
 
+	workflowBreaker := gobreaker.NewCircuitBreaker(gobreaker.Settings{
+		Name:        "WorkflowServiceCB",
+		MaxRequests: 1,
+		Timeout:     5 * time.Second,
+		ReadyToTrip: func(counts gobreaker.Counts) bool {
+			return counts.ConsecutiveFailures > 3
+		},
+		OnStateChange: func(name string, from, to gobreaker.State) {
+			logging.Logger.Infof("Circuit Breaker '%s' changed from '%s' to '%s'", name, from.String(), to.String())
+		},
+	})
+
+	// Domenski događaji za analytics-service idu preko outbox-a na NATS JetStream
+	var eventOutbox *events.Outbox
+	if natsURL := os.Getenv("NATS_URL"); natsURL != "" {
+		nc, err := events.Connect(natsURL, "projects-service")
+		if err != nil {
+			logging.Logger.Fatalf("Failed to connect to NATS at %s: %v", natsURL, err)
+		}
+		defer nc.Close()
+		publisher, err := events.NewPublisher(nc)
+		if err != nil {
+			logging.Logger.Fatalf("Failed to open JetStream context: %v", err)
+		}
+		eventOutbox = events.NewOutbox(projectsDB.Collection("event_outbox"), publisher)
+		if err := eventOutbox.EnsureIndexes(context.TODO()); err != nil {
+			logging.Logger.Fatal(err)
+		}
+	} else {
+		logging.Logger.Warn("NATS_URL is not set, domain events will not be published")
+	}
+
 	projectService := services.NewProjectService(
 		projectsDB.Collection(mongoCollectionName),
+		projectsDB.Collection("sprints"),
+		projectsDB.Collection("invitations"),
+		projectsDB.Collection("deletion_sagas"),
+		projectsDB.Collection("calendar_feeds"),
+		projectsDB.Collection("webhooks"),
+		projectsDB.Collection("webhook_deliveries"),
+		projectsDB.Collection("project_activities"),
+		eventOutbox,
 		httpClient,
 		tasksBreaker,
 		usersBreaker,
 		notificationsBreaker,
+		workflowBreaker,
 	)
 
 	//projectService := services.NewProjectService(projectsDB.Collection(mongoCollectionName), httpClient)
@@ -114,21 +202,83 @@ func main() {
 		logging.Logger.Fatal(err)
 	}
 
+	if err := createInvitationIndex(projectsDB.Collection("invitations")); err != nil {
+		logging.Logger.Fatal(err)
+	}
+	if err := createWebhookDeliveryIndexes(projectsDB.Collection("webhook_deliveries")); err != nil {
+		logging.Logger.Fatal(err)
+	}
+	if err := createActivityIndex(projectsDB.Collection("project_activities")); err != nil {
+		logging.Logger.Fatal(err)
+	}
+	if err := createCalendarFeedIndexes(projectsDB.Collection("calendar_feeds")); err != nil {
+		logging.Logger.Fatal(err)
+	}
+	projectService.StartInvitationExpirer(context.Background(), time.Hour)
+	projectService.StartDeletionSagaWorker(context.Background(), 30*time.Second)
+	projectService.StartWebhookDispatcher(context.Background(), 15*time.Second)
+
+	// Jednokratno objavljivanje postojećih projekata kao događaja, uključuje se sa PROJECT_EVENTS_BACKFILL=true
+	if os.Getenv("PROJECT_EVENTS_BACKFILL") == "true" {
+		count, err := projectService.BackfillEvents(context.Background())
+		if err != nil {
+			logging.Logger.Errorf("Project events backfill stopped after %d events: %v", count, err)
+		} else {
+			logging.Logger.Infof("Queued %d events for existing projects", count)
+		}
+	}
+	projectService.StartEventRelay(context.Background(), 15*time.Second)
+
 	projectHandler := handlers.NewProjectHandler(projectService)
 
 	r := mux.NewRouter()
+	r.HandleFunc("/api/calendar/token", projectHandler.GetCalendarFeedHandler).Methods(http.MethodGet)
+	r.HandleFunc("/api/calendar/token", projectHandler.RotateCalendarFeedHandler).Methods(http.MethodPost)
+	r.HandleFunc("/api/calendar/token", projectHandler.RevokeCalendarFeedHandler).Methods(http.MethodDelete)
+	r.HandleFunc("/api/calendar/feed/{token}", projectHandler.CalendarFeedHandler).Methods(http.MethodGet)
 	r.HandleFunc("/api/projects/{projectId}/members/all", projectHandler.GetProjectMembersHandler).Methods("GET")
+	r.HandleFunc("/api/projects/{projectId}/staffing", projectHandler.GetStaffingReportHandler).Methods(http.MethodGet)
+	r.HandleFunc("/api/projects/{projectId}/webhooks", projectHandler.CreateWebhookHandler).Methods(http.MethodPost)
+	r.HandleFunc("/api/projects/{projectId}/webhooks", projectHandler.ListWebhooksHandler).Methods(http.MethodGet)
+	r.HandleFunc("/api/projects/{projectId}/webhooks/events", projectHandler.EmitWebhookEventHandler).Methods(http.MethodPost)
+	r.HandleFunc("/api/projects/{projectId}/webhooks/{webhookId}", projectHandler.DeleteWebhookHandler).Methods(http.MethodDelete)
+	r.HandleFunc("/api/projects/{projectId}/webhooks/{webhookId}/deliveries", projectHandler.ListWebhookDeliveriesHandler).Methods(http.MethodGet)
+	r.HandleFunc("/api/projects/{projectId}/invitations", projectHandler.InviteMembersHandler).Methods(http.MethodPost)
+	r.HandleFunc("/api/projects/{projectId}/invitations", projectHandler.ListProjectInvitationsHandler).Methods(http.MethodGet)
+	r.HandleFunc("/api/projects/{projectId}/invitations/{invitationId}", projectHandler.RevokeInvitationHandler).Methods(http.MethodDelete)
+	r.HandleFunc("/api/invitations", projectHandler.ListMyInvitationsHandler).Methods(http.MethodGet)
+	r.HandleFunc("/api/invitations/{invitationId}/accept", projectHandler.AcceptInvitationHandler).Methods(http.MethodPost)
+	r.HandleFunc("/api/invitations/{invitationId}/decline", projectHandler.DeclineInvitationHandler).Methods(http.MethodPost)
+	r.HandleFunc("/api/projects/{projectId}/role", projectHandler.GetProjectRoleHandler).Methods(http.MethodGet)
+	r.HandleFunc("/api/projects/{projectId}/members/{memberId}/role", projectHandler.SetMemberRoleHandler).Methods(http.MethodPut)
 	r.HandleFunc("/api/projects/remove/{projectId}/members/{memberId}/remove", projectHandler.RemoveMemberFromProjectHandler).Methods("DELETE")
 	r.HandleFunc("/api/projects/add", projectHandler.CreateProject).Methods("POST")
-	r.HandleFunc("/api/projects/{id}/members", projectHandler.AddMemberToProjectHandler).Methods("POST")
+	r.HandleFunc("/api/projects/import/trello", projectHandler.ImportTrelloBoardHandler).Methods(http.MethodPost)
 	r.HandleFunc("/api/projects/users", projectHandler.GetAllUsersHandler).Methods("GET")
 	r.HandleFunc("/api/projects/all", projectHandler.ListProjectsHandler).Methods("GET")
+	r.HandleFunc("/api/projects/admin/orphaned", projectHandler.ListOrphanedProjectsHandler).Methods(http.MethodGet)
+	r.HandleFunc("/api/projects/admin/orphaned/{projectId}/reassign", projectHandler.ReassignOrphanedProjectHandler).Methods(http.MethodPost)
+	r.HandleFunc("/api/projects/{projectId}/transfer-ownership", projectHandler.TransferOwnershipHandler).Methods(http.MethodPost)
+	r.HandleFunc("/api/projects/{projectId}/archive", projectHandler.ArchiveProjectHandler).Methods(http.MethodPost)
+	r.HandleFunc("/api/projects/{projectId}/unarchive", projectHandler.UnarchiveProjectHandler).Methods(http.MethodPost)
 	r.HandleFunc("/api/projects/username/{username}", handlers.GetProjectsByUsername(projectService)).Methods("GET", "OPTIONS")
 	r.HandleFunc("/api/projects/{id}", projectHandler.GetProjectByIDHandler).Methods("GET")
+	r.HandleFunc("/api/projects/{id}", projectHandler.UpdateProjectHandler).Methods(http.MethodPatch)
+	r.HandleFunc("/api/projects/{id}/clone", projectHandler.CloneProjectHandler).Methods(http.MethodPost)
+	r.HandleFunc("/api/projects/{id}/activity", projectHandler.GetProjectActivityHandler).Methods(http.MethodGet)
+	r.HandleFunc("/api/projects/{id}/activity", projectHandler.RecordActivityHandler).Methods(http.MethodPost)
 	r.HandleFunc("/api/projects/{id}/tasks", projectHandler.DisplayTasksForProjectHandler).Methods("GET")
 	r.HandleFunc("/api/projects/{projectId}", projectHandler.RemoveProjectHandler).Methods(http.MethodDelete)
+	r.HandleFunc("/api/projects/{projectId}/deletion", projectHandler.GetProjectDeletionHandler).Methods(http.MethodGet)
 	r.HandleFunc("/api/projects/members", projectHandler.GetAllMembersHandler)
+	r.HandleFunc("/api/projects/{projectId}/sprints", projectHandler.ListSprintsHandler).Methods(http.MethodGet)
+	r.HandleFunc("/api/projects/{projectId}/sprints", projectHandler.CreateSprintHandler).Methods(http.MethodPost)
+	r.HandleFunc("/api/projects/{projectId}/sprints/{sprintId}", projectHandler.GetSprintHandler).Methods(http.MethodGet)
+	r.HandleFunc("/api/projects/{projectId}/sprints/{sprintId}/start", projectHandler.StartSprintHandler).Methods(http.MethodPost)
+	r.HandleFunc("/api/projects/{projectId}/sprints/{sprintId}/close", projectHandler.CloseSprintHandler).Methods(http.MethodPost)
+	r.HandleFunc("/api/projects/{projectId}/velocity", projectHandler.GetVelocityHandler).Methods(http.MethodGet)
 	r.HandleFunc("/api/projects/{projectId}/add-task", projectHandler.AddTaskToProjectHandler).Methods("POST")
+	r.HandleFunc("/api/projects/{projectId}/tasks/{taskId}", projectHandler.RemoveTaskFromProjectHandler).Methods(http.MethodDelete)
 	r.HandleFunc("/api/projects/user-projects/{username}", handlers.GetProjectsByUsername(projectService)).Methods("GET")
 	r.HandleFunc("/api/projects/remove-user/{userID}", projectHandler.RemoveUserFromProjectsHandler).Methods("PATCH")
 
@@ -149,7 +299,7 @@ func enableCORS(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Credentials", "true")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
 		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Role, Manager-ID")
 
 		if r.Method == http.MethodOptions {
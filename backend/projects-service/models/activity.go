@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ActivityType i ProjectActivity imaju isti oblik kao u analytics-service-u, tako da se
+// zapisi feed-a mogu direktno preuzeti u analitiku.
+type ActivityType string
+
+const (
+	ActivityAddMember         ActivityType = "AddMember"
+	ActivityRemoveMember      ActivityType = "RemoveMember"
+	ActivityCreateTask        ActivityType = "CreateTask"
+	ActivityDeleteTask        ActivityType = "DeleteTask"
+	ActivityChangeTaskStatus  ActivityType = "ChangeTaskStatus"
+	ActivityAddDocumentToTask ActivityType = "AddDocumentToTask" // još se ne beleži: zadaci nemaju dokumente
+	ActivityUpdateTask        ActivityType = "UpdateTask"
+)
+
+// ValidActivityType vraća true za poznate tipove aktivnosti.
+func ValidActivityType(activityType ActivityType) bool {
+	switch activityType {
+	case ActivityAddMember, ActivityRemoveMember, ActivityCreateTask, ActivityDeleteTask,
+		ActivityChangeTaskStatus, ActivityAddDocumentToTask, ActivityUpdateTask:
+		return true
+	}
+	return false
+}
+
+type ProjectActivity struct {
+	ID           primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	ProjectID    primitive.ObjectID  `json:"projectId" bson:"projectId"`
+	ActivityType ActivityType        `json:"activityType" bson:"activityType"`
+	TaskID       *primitive.ObjectID `json:"taskId,omitempty" bson:"taskId,omitempty"`
+	MemberID     *primitive.ObjectID `json:"memberId,omitempty" bson:"memberId,omitempty"`
+	// Actor je korisnik koji je izveo akciju; nije deo modela u analytics-service-u.
+	Actor     string    `json:"actor,omitempty" bson:"actor,omitempty"`
+	Timestamp time.Time `json:"timestamp" bson:"timestamp"`
+	Details   string    `json:"details" bson:"details"`
+}
+
+// ActivityFilter sužava feed aktivnosti; prazna polja se ne primenjuju.
+type ActivityFilter struct {
+	Types    []ActivityType
+	TaskID   *primitive.ObjectID
+	MemberID *primitive.ObjectID
+	Actor    string
+	Since    *time.Time
+	Until    *time.Time
+	Page     int
+	PageSize int
+}
+
+// ActivityPage je jedna strana feed-a, od najnovije aktivnosti.
+type ActivityPage struct {
+	Items    []ProjectActivity `json:"items"`
+	Total    int64             `json:"total"`
+	Page     int               `json:"page"`
+	PageSize int               `json:"pageSize"`
+}
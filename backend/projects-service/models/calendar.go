@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CalendarFeed je tajni token preko kojeg kalendarski klijent, bez JWT-a, preuzima
+// ICS feed korisnika. Novi token poništava prethodni.
+type CalendarFeed struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Username  string             `bson:"username" json:"username"`
+	Token     string             `bson:"token" json:"token"`
+	CreatedAt time.Time          `bson:"created_at" json:"createdAt"`
+	// FeedURL se ne čuva; sastavlja se pri odgovoru.
+	FeedURL string `bson:"-" json:"feedUrl"`
+}
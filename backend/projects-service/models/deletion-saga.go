@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type DeletionSagaStatus string
+
+const (
+	DeletionRunning      DeletionSagaStatus = "running"
+	DeletionCompensating DeletionSagaStatus = "compensating"
+	DeletionCompleted    DeletionSagaStatus = "completed"
+	DeletionCompensated  DeletionSagaStatus = "compensated"
+)
+
+type DeletionStepStatus string
+
+const (
+	StepPending     DeletionStepStatus = "pending"
+	StepDone        DeletionStepStatus = "done"
+	StepCompensated DeletionStepStatus = "compensated"
+)
+
+// Koraci brisanja projekta, redom kojim se izvršavaju.
+const (
+	StepLockProject    = "lock_project"
+	StepDeleteTasks    = "delete_tasks"
+	StepDeleteWorkflow = "delete_workflow"
+	StepDeleteProject  = "delete_project"
+)
+
+type DeletionStep struct {
+	Name      string             `bson:"name" json:"name"`
+	Status    DeletionStepStatus `bson:"status" json:"status"`
+	Attempts  int                `bson:"attempts" json:"attempts"`
+	LastError string             `bson:"last_error,omitempty" json:"lastError,omitempty"`
+	UpdatedAt *time.Time         `bson:"updated_at,omitempty" json:"updatedAt,omitempty"`
+}
+
+// DeletionSaga čuva stanje brisanja projekta posle svakog koraka, tako da se brisanje
+// nastavlja posle pada servisa. Koraci koji ne uspeju ponavljaju se; ako brisanje ne
+// može da se završi pre brisanja workflow čvorova, obrisani zadaci se vraćaju.
+type DeletionSaga struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ProjectID     primitive.ObjectID `bson:"project_id" json:"projectId"`
+	ProjectName   string             `bson:"project_name" json:"projectName"`
+	RequestedBy   string             `bson:"requested_by" json:"requestedBy"`
+	Status        DeletionSagaStatus `bson:"status" json:"status"`
+	Steps         []DeletionStep     `bson:"steps" json:"steps"`
+	Error         string             `bson:"error,omitempty" json:"error,omitempty"`
+	CreatedAt     time.Time          `bson:"created_at" json:"createdAt"`
+	UpdatedAt     time.Time          `bson:"updated_at" json:"updatedAt"`
+	NextAttemptAt time.Time          `bson:"next_attempt_at" json:"nextAttemptAt"`
+	LockedUntil   time.Time          `bson:"locked_until" json:"-"`
+}
+
+// Finished vraća true kada saga više nema posla.
+func (s *DeletionSaga) Finished() bool {
+	return s.Status == DeletionCompleted || s.Status == DeletionCompensated
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type InvitationStatus string
+
+const (
+	InvitationPending  InvitationStatus = "pending"
+	InvitationAccepted InvitationStatus = "accepted"
+	InvitationDeclined InvitationStatus = "declined"
+	InvitationExpired  InvitationStatus = "expired"
+	InvitationRevoked  InvitationStatus = "revoked"
+)
+
+// Invitation je poziv korisniku da se pridruži projektu. Korisnik postaje član tek
+// kada prihvati poziv, i to samo ako projekat nije popunjen.
+type Invitation struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ProjectID   primitive.ObjectID `bson:"project_id" json:"projectId"`
+	ProjectName string             `bson:"project_name" json:"projectName"`
+	UserID      primitive.ObjectID `bson:"user_id" json:"userId"`
+	Username    string             `bson:"username" json:"username"`
+	ProjectRole string             `bson:"project_role" json:"projectRole"`
+	Status      InvitationStatus   `bson:"status" json:"status"`
+	InvitedBy   string             `bson:"invited_by" json:"invitedBy"`
+	CreatedAt   time.Time          `bson:"created_at" json:"createdAt"`
+	ExpiresAt   time.Time          `bson:"expires_at" json:"expiresAt"`
+	RespondedAt *time.Time         `bson:"responded_at,omitempty" json:"respondedAt,omitempty"`
+}
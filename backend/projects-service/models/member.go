@@ -8,4 +8,7 @@ type Member struct {
 	LastName string             `bson:"lastName" json:"lastName"`
 	Username string             `bson:"username" json:"username"`
 	Role     string             `bson:"role" json:"role"`
+	// ProjectRole je uloga člana na projektu (maintainer, contributor ili viewer);
+	// prazna vrednost kod starijih članova znači contributor.
+	ProjectRole string `bson:"project_role,omitempty" json:"projectRole,omitempty"`
 }
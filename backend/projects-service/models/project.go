@@ -16,4 +16,38 @@ type Project struct {
 	ManagerID       primitive.ObjectID   `json:"managerId" bson:"manager_id"`
 	Members         []Member             `bson:"members" json:"members"`
 	Tasks           []primitive.ObjectID `bson:"taskIDs" json:"taskIds"`
+	DeletionSagaID  *primitive.ObjectID  `bson:"deletion_saga_id,omitempty" json:"deletionSagaId,omitempty"`
+	ArchivedAt      *time.Time           `bson:"archived_at,omitempty" json:"archivedAt,omitempty"`
+}
+
+// ProjectUpdate sadrži polja projekta koja menadžer može da izmeni; nil polje ostaje nepromenjeno.
+type ProjectUpdate struct {
+	Name            *string    `json:"name"`
+	Description     *string    `json:"description"`
+	ExpectedEndDate *time.Time `json:"expectedEndDate"`
+	MinMembers      *int       `json:"minMembers"`
+	MaxMembers      *int       `json:"maxMembers"`
+}
+
+// ProjectClone opisuje novi projekat koji nastaje kloniranjem postojećeg. Nezadata
+// ograničenja broja članova i opis preuzimaju se iz izvornog projekta.
+type ProjectClone struct {
+	Name            string    `json:"name"`
+	Description     *string   `json:"description"`
+	ExpectedEndDate time.Time `json:"expectedEndDate"`
+	MinMembers      *int      `json:"minMembers"`
+	MaxMembers      *int      `json:"maxMembers"`
+	CopyMembers     bool      `json:"copyMembers"`
+	CopyTasks       bool      `json:"copyTasks"`
+	// CopyWorkflow kopira zavisnosti između zadataka i zahteva CopyTasks.
+	CopyWorkflow bool `json:"copyWorkflow"`
+}
+
+// ProjectCloneReport je rezultat kloniranja projekta.
+type ProjectCloneReport struct {
+	Project            *Project `json:"project"`
+	SourceProjectID    string   `json:"sourceProjectId"`
+	MembersCopied      int      `json:"membersCopied"`
+	TasksCopied        int      `json:"tasksCopied"`
+	DependenciesCopied int64    `json:"dependenciesCopied"`
 }
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type SprintStatus string
+
+const (
+	SprintPlanned SprintStatus = "planned"
+	SprintActive  SprintStatus = "active"
+	SprintClosed  SprintStatus = "closed"
+)
+
+// Sprint je iteracija unutar projekta. Zadaci se dodeljuju sprintu u tasks-service-u
+// (polje sprintId), a pri zatvaranju sprinta ovde se čuvaju ostvareni poeni.
+type Sprint struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ProjectID primitive.ObjectID `bson:"project_id" json:"projectId"`
+	Name      string             `bson:"name" json:"name"`
+	Goal      string             `bson:"goal" json:"goal"`
+	StartDate time.Time          `bson:"start_date" json:"startDate"`
+	EndDate   time.Time          `bson:"end_date" json:"endDate"`
+	Status    SprintStatus       `bson:"status" json:"status"`
+	StartedAt *time.Time         `bson:"started_at,omitempty" json:"startedAt,omitempty"`
+	ClosedAt  *time.Time         `bson:"closed_at,omitempty" json:"closedAt,omitempty"`
+	// Vrednosti se upisuju pri zatvaranju sprinta i koriste za računanje brzine tima.
+	CommittedPoints int                 `bson:"committed_points" json:"committedPoints"`
+	CompletedPoints int                 `bson:"completed_points" json:"completedPoints"`
+	CompletedTasks  int                 `bson:"completed_tasks" json:"completedTasks"`
+	RolledOverTasks int                 `bson:"rolled_over_tasks" json:"rolledOverTasks"`
+	RolledOverTo    *primitive.ObjectID `bson:"rolled_over_to,omitempty" json:"rolledOverTo,omitempty"`
+}
+
+// SprintSummary je stanje zadataka sprinta kako ga vraća tasks-service.
+type SprintSummary struct {
+	SprintID        string `json:"sprintId"`
+	TotalTasks      int    `json:"totalTasks"`
+	CompletedTasks  int    `json:"completedTasks"`
+	TotalPoints     int    `json:"totalPoints"`
+	CompletedPoints int    `json:"completedPoints"`
+	RolledOver      int    `json:"rolledOver"`
+}
+
+type SprintVelocity struct {
+	SprintID        string    `json:"sprintId"`
+	Name            string    `json:"name"`
+	EndDate         time.Time `json:"endDate"`
+	CommittedPoints int       `json:"committedPoints"`
+	CompletedPoints int       `json:"completedPoints"`
+}
+
+// VelocityReport sadrži završene sprintove hronološki i prosečnu brzinu
+// poslednjih sprintova (najviše VelocityWindow).
+type VelocityReport struct {
+	ProjectID       string           `json:"projectId"`
+	Sprints         []SprintVelocity `json:"sprints"`
+	AverageVelocity float64          `json:"averageVelocity"`
+	VelocityWindow  int              `json:"velocityWindow"`
+}
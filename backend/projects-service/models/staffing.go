@@ -0,0 +1,33 @@
+package models
+
+// Stanja popunjenosti projekta u odnosu na MinMembers i MaxMembers.
+const (
+	StaffingUnderstaffed = "understaffed"
+	StaffingHealthy      = "healthy"
+	StaffingFull         = "full"
+)
+
+// MemberLoad opisuje opterećenje jednog člana na projektu. HasActiveTasks je nil kada
+// tasks-service nije mogao da odgovori.
+type MemberLoad struct {
+	MemberID       string `json:"memberId"`
+	Username       string `json:"username"`
+	ProjectRole    string `json:"projectRole"`
+	HasActiveTasks *bool  `json:"hasActiveTasks"`
+}
+
+// StaffingReport poredi broj članova projekta sa ograničenjima i prikazuje opterećenje članova.
+type StaffingReport struct {
+	ProjectID   string       `json:"projectId"`
+	ProjectName string       `json:"projectName"`
+	MemberCount int          `json:"memberCount"`
+	MinMembers  int          `json:"minMembers"`
+	MaxMembers  int          `json:"maxMembers"`
+	Status      string       `json:"status"`
+	OpenSlots   int          `json:"openSlots"`
+	Shortfall   int          `json:"shortfall"`
+	ActiveCount int          `json:"activeCount"`
+	IdleCount   int          `json:"idleCount"`
+	Members     []MemberLoad `json:"members"`
+	Warnings    []string     `json:"warnings,omitempty"`
+}
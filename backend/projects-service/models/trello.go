@@ -0,0 +1,87 @@
+package models
+
+import "time"
+
+// Strukture Trello JSON izvoza table (Board -> Menu -> Print and export -> Export as JSON).
+// Sadrže samo polja koja uvoz koristi.
+type TrelloBoard struct {
+	Name       string            `json:"name"`
+	Desc       string            `json:"desc"`
+	Lists      []TrelloList      `json:"lists"`
+	Cards      []TrelloCard      `json:"cards"`
+	Members    []TrelloMember    `json:"members"`
+	Checklists []TrelloChecklist `json:"checklists"`
+	Actions    []TrelloAction    `json:"actions"`
+}
+
+type TrelloList struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Closed bool   `json:"closed"`
+}
+
+type TrelloCard struct {
+	ID          string        `json:"id"`
+	Name        string        `json:"name"`
+	Desc        string        `json:"desc"`
+	IDList      string        `json:"idList"`
+	IDMembers   []string      `json:"idMembers"`
+	Closed      bool          `json:"closed"`
+	Due         *time.Time    `json:"due"`
+	Labels      []TrelloLabel `json:"labels"`
+	Attachments []struct {
+		Name string `json:"name"`
+	} `json:"attachments"`
+}
+
+type TrelloLabel struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type TrelloMember struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	FullName string `json:"fullName"`
+}
+
+type TrelloChecklist struct {
+	ID         string `json:"id"`
+	IDCard     string `json:"idCard"`
+	Name       string `json:"name"`
+	CheckItems []struct {
+		Name  string `json:"name"`
+		State string `json:"state"` // "complete" ili "incomplete"
+	} `json:"checkItems"`
+}
+
+type TrelloAction struct {
+	Type string    `json:"type"`
+	Date time.Time `json:"date"`
+	Data struct {
+		Text string `json:"text"`
+		Card struct {
+			ID string `json:"id"`
+		} `json:"card"`
+	} `json:"data"`
+	MemberCreator struct {
+		Username string `json:"username"`
+	} `json:"memberCreator"`
+}
+
+// TrelloImportReport opisuje rezultat (ili, kod dry-run-a, plan) uvoza table.
+type TrelloImportReport struct {
+	DryRun           bool              `json:"dryRun"`
+	ProjectID        string            `json:"projectId,omitempty"`
+	ProjectName      string            `json:"projectName"`
+	ListMapping      map[string]string `json:"listMapping"`
+	MatchedMembers   []string          `json:"matchedMembers"`
+	UnmatchedMembers []string          `json:"unmatchedMembers"`
+	TasksPlanned     int               `json:"tasksPlanned"`
+	TasksCreated     int               `json:"tasksCreated"`
+	SubtasksPlanned  int               `json:"subtasksPlanned"`
+	CommentsPlanned  int               `json:"commentsPlanned"`
+	Skipped          []string          `json:"skipped"`
+	Warnings         []string          `json:"warnings"`
+	Errors           []string          `json:"errors"`
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type WebhookEvent string
+
+// Događaji na koje webhook može da se pretplati.
+const (
+	WebhookTaskCreated       WebhookEvent = "task.created"
+	WebhookTaskStatusChanged WebhookEvent = "task.status_changed"
+	WebhookMemberAdded       WebhookEvent = "member.added"
+	WebhookDependencyAdded   WebhookEvent = "dependency.added"
+)
+
+// ValidWebhookEvent vraća true za podržane događaje.
+func ValidWebhookEvent(event WebhookEvent) bool {
+	switch event {
+	case WebhookTaskCreated, WebhookTaskStatusChanged, WebhookMemberAdded, WebhookDependencyAdded:
+		return true
+	}
+	return false
+}
+
+// Webhook je URL na koji se šalju izabrani događaji projekta. Secret služi za HMAC
+// potpis isporuke i vraća se samo pri registraciji.
+type Webhook struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ProjectID primitive.ObjectID `bson:"project_id" json:"projectId"`
+	URL       string             `bson:"url" json:"url"`
+	Events    []WebhookEvent     `bson:"events" json:"events"`
+	Secret    string             `bson:"secret" json:"secret,omitempty"`
+	CreatedBy string             `bson:"created_by" json:"createdBy"`
+	CreatedAt time.Time          `bson:"created_at" json:"createdAt"`
+}
+
+// Subscribed vraća true ako je webhook pretplaćen na događaj.
+func (w Webhook) Subscribed(event WebhookEvent) bool {
+	for _, subscribed := range w.Events {
+		if subscribed == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookRegistration je zahtev za registraciju webhook-a.
+type WebhookRegistration struct {
+	URL    string         `json:"url"`
+	Events []WebhookEvent `json:"events"`
+}
+
+// WebhookEventRequest šalju drugi servisi kada se desi događaj na projektu.
+type WebhookEventRequest struct {
+	Event WebhookEvent           `json:"event"`
+	Data  map[string]interface{} `json:"data"`
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	DeliveryPending   WebhookDeliveryStatus = "pending"
+	DeliveryDelivered WebhookDeliveryStatus = "delivered"
+	DeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDelivery je jedna isporuka događaja webhook-u, sa istorijom pokušaja.
+type WebhookDelivery struct {
+	ID            primitive.ObjectID    `bson:"_id,omitempty" json:"id"`
+	WebhookID     primitive.ObjectID    `bson:"webhook_id" json:"webhookId"`
+	ProjectID     primitive.ObjectID    `bson:"project_id" json:"projectId"`
+	Event         WebhookEvent          `bson:"event" json:"event"`
+	Payload       string                `bson:"payload" json:"payload"`
+	Status        WebhookDeliveryStatus `bson:"status" json:"status"`
+	Attempts      []DeliveryAttempt     `bson:"attempts" json:"attempts"`
+	CreatedAt     time.Time             `bson:"created_at" json:"createdAt"`
+	NextAttemptAt time.Time             `bson:"next_attempt_at" json:"nextAttemptAt"`
+	DeliveredAt   *time.Time            `bson:"delivered_at,omitempty" json:"deliveredAt,omitempty"`
+}
+
+// DeliveryAttempt beleži ishod jednog pokušaja isporuke.
+type DeliveryAttempt struct {
+	At         time.Time `bson:"at" json:"at"`
+	StatusCode int       `bson:"status_code,omitempty" json:"statusCode,omitempty"`
+	Error      string    `bson:"error,omitempty" json:"error,omitempty"`
+	DurationMs int64     `bson:"duration_ms" json:"durationMs"`
+}
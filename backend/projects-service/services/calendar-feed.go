@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"trello-project/microservices/projects-service/logging"
+	"trello-project/microservices/projects-service/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrCalendarFeedNotFound vraća se za nepoznat ili opozvan token kalendarskog feed-a.
+var ErrCalendarFeedNotFound = errors.New("calendar feed not found")
+
+// calendarTask je deo zadatka iz tasks-service-a potreban za kalendar.
+type calendarTask struct {
+	ID        string     `json:"id"`
+	ProjectID string     `json:"projectId"`
+	Title     string     `json:"title"`
+	Status    string     `json:"status"`
+	DueDate   *time.Time `json:"dueDate"`
+}
+
+// GetCalendarFeed vraća token kalendarskog feed-a korisnika i kreira ga pri prvom pozivu.
+func (s *ProjectService) GetCalendarFeed(ctx context.Context, username string) (*models.CalendarFeed, error) {
+	token, err := newCalendarToken()
+	if err != nil {
+		return nil, err
+	}
+	update := bson.M{"$setOnInsert": bson.M{"username": username, "token": token, "created_at": time.Now().UTC()}}
+	return s.upsertCalendarFeed(ctx, username, update)
+}
+
+// RotateCalendarFeed dodeljuje korisniku novi token; stari URL feed-a prestaje da radi.
+func (s *ProjectService) RotateCalendarFeed(ctx context.Context, username string) (*models.CalendarFeed, error) {
+	token, err := newCalendarToken()
+	if err != nil {
+		return nil, err
+	}
+	update := bson.M{
+		"$set":         bson.M{"token": token, "created_at": time.Now().UTC()},
+		"$setOnInsert": bson.M{"username": username},
+	}
+	feed, err := s.upsertCalendarFeed(ctx, username, update)
+	if err == nil {
+		logging.Logger.Infof("Calendar feed token rotated for %s", username)
+	}
+	return feed, err
+}
+
+// RevokeCalendarFeed briše token korisnika, pa feed više nije dostupan.
+func (s *ProjectService) RevokeCalendarFeed(ctx context.Context, username string) error {
+	result, err := s.CalendarFeedsCollection.DeleteOne(ctx, bson.M{"username": username})
+	if err != nil {
+		logging.Logger.Errorf("Failed to revoke calendar feed of %s: %v", username, err)
+		return fmt.Errorf("failed to revoke calendar feed")
+	}
+	if result.DeletedCount == 0 {
+		return ErrCalendarFeedNotFound
+	}
+	logging.Logger.Infof("Calendar feed revoked for %s", username)
+	return nil
+}
+
+// RenderCalendarFeed pravi ICS kalendar za token: rokove (ExpectedEndDate) projekata na
+// kojima je korisnik i rokove njegovih zadataka. Kalendar se sastavlja pri svakom
+// zahtevu, pa klijent pri osvežavanju dobija izmenjene datume; UID događaja je stabilan.
+// Ako tasks-service nije dostupan, feed sadrži samo projekte.
+func (s *ProjectService) RenderCalendarFeed(ctx context.Context, token string) ([]byte, error) {
+	var feed models.CalendarFeed
+	if err := s.CalendarFeedsCollection.FindOne(ctx, bson.M{"token": token}).Decode(&feed); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrCalendarFeedNotFound
+		}
+		logging.Logger.Errorf("Failed to look up calendar feed: %v", err)
+		return nil, fmt.Errorf("failed to look up calendar feed")
+	}
+
+	projects, err := s.GetProjectsByUsername(feed.Username, ArchivedExclude)
+	if err != nil {
+		return nil, err
+	}
+	projectNames := make(map[string]string, len(projects))
+
+	now := time.Now().UTC()
+	cal := &icsWriter{}
+	cal.line("BEGIN:VCALENDAR")
+	cal.line("VERSION:2.0")
+	cal.line("PRODID:-//trello-project//projects-service//EN")
+	cal.line("CALSCALE:GREGORIAN")
+	cal.line("METHOD:PUBLISH")
+	cal.text("X-WR-CALNAME", "Deadlines - "+feed.Username)
+	cal.line("REFRESH-INTERVAL;VALUE=DURATION:PT1H")
+	cal.line("X-PUBLISHED-TTL:PT1H")
+
+	// Naziv i opis projekta, kao i naslovi zadataka, čuvaju se HTML-escape-ovani
+	for _, project := range projects {
+		name := html.UnescapeString(project.Name)
+		projectNames[project.ID.Hex()] = name
+		if project.ExpectedEndDate.IsZero() {
+			continue
+		}
+		end := project.ExpectedEndDate.UTC()
+		cal.line("BEGIN:VEVENT")
+		cal.line("UID:project-" + project.ID.Hex() + "@trello-project")
+		cal.line("DTSTAMP:" + now.Format(icsDateTime))
+		cal.line("DTSTART;VALUE=DATE:" + end.Format(icsDate))
+		cal.line("DTEND;VALUE=DATE:" + end.AddDate(0, 0, 1).Format(icsDate))
+		cal.text("SUMMARY", "Project deadline: "+name)
+		if project.Description != "" {
+			cal.text("DESCRIPTION", html.UnescapeString(project.Description))
+		}
+		cal.line("END:VEVENT")
+	}
+
+	var tasks []calendarTask
+	path := "/api/tasks/member/" + url.PathEscape(feed.Username) + "/due"
+	if err := s.callService(s.TasksBreaker, http.MethodGet, "TASKS_SERVICE_URL", path, nil, &tasks); err != nil {
+		logging.Logger.Warnf("[Fallback] Calendar feed of %s served without tasks: %v", feed.Username, err)
+	}
+	for _, task := range tasks {
+		if task.DueDate == nil {
+			continue
+		}
+		summary := html.UnescapeString(task.Title)
+		if name, ok := projectNames[task.ProjectID]; ok {
+			summary += " (" + name + ")"
+		}
+		cal.line("BEGIN:VEVENT")
+		cal.line("UID:task-" + task.ID + "@trello-project")
+		cal.line("DTSTAMP:" + now.Format(icsDateTime))
+		cal.line("DTSTART:" + task.DueDate.UTC().Format(icsDateTime))
+		cal.text("SUMMARY", summary)
+		cal.text("DESCRIPTION", "Status: "+task.Status)
+		cal.line("END:VEVENT")
+	}
+
+	cal.line("END:VCALENDAR")
+	return []byte(cal.String()), nil
+}
+
+func (s *ProjectService) upsertCalendarFeed(ctx context.Context, username string, update bson.M) (*models.CalendarFeed, error) {
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	var feed models.CalendarFeed
+	if err := s.CalendarFeedsCollection.FindOneAndUpdate(ctx, bson.M{"username": username}, update, opts).Decode(&feed); err != nil {
+		logging.Logger.Errorf("Failed to save calendar feed of %s: %v", username, err)
+		return nil, fmt.Errorf("failed to save calendar feed")
+	}
+	return &feed, nil
+}
+
+func newCalendarToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate calendar token: %v", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+const (
+	icsDate     = "20060102"
+	icsDateTime = "20060102T150405Z"
+)
+
+// icsWriter piše linije iCalendar formata (RFC 5545): CRLF na kraju i prelom linija
+// dužih od 75 okteta.
+type icsWriter struct {
+	strings.Builder
+}
+
+func (w *icsWriter) line(content string) {
+	for len(content) > 75 {
+		cut := 75
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		w.WriteString(content[:cut] + "\r\n")
+		content = " " + content[cut:]
+	}
+	w.WriteString(content + "\r\n")
+}
+
+// text piše svojstvo sa tekstualnom vrednošću, uz escape specijalnih znakova.
+func (w *icsWriter) text(name, value string) {
+	replacer := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	w.line(name + ":" + replacer.Replace(value))
+}
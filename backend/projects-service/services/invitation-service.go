@@ -0,0 +1,367 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"trello-project/microservices/projects-service/logging"
+	"trello-project/microservices/projects-service/models"
+
+	"trello-project/backend/utils/projectroles"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// InvitationResult je ishod poziva za jedno korisničko ime.
+type InvitationResult struct {
+	Username   string             `json:"username"`
+	Invitation *models.Invitation `json:"invitation,omitempty"`
+	Error      string             `json:"error,omitempty"`
+}
+
+// InviteMembers šalje pozive korisnicima da se pridruže projektu sa ulogom roleName
+// (podrazumevano contributor). Korisnici koji su već članovi ili već imaju poziv na
+// čekanju se preskaču.
+func (s *ProjectService) InviteMembers(ctx context.Context, projectID string, usernames []string, roleName, invitedBy string) ([]InvitationResult, error) {
+	project, err := s.GetProjectByID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	role := projectroles.DefaultMemberRole
+	if roleName != "" {
+		parsed, ok := projectroles.Parse(roleName)
+		if !ok || parsed == projectroles.Owner {
+			return nil, fmt.Errorf("invalid project role: %s", roleName)
+		}
+		role = parsed
+	}
+	if len(project.Members) >= project.MaxMembers {
+		return nil, fmt.Errorf("maximum number of members reached for the project")
+	}
+
+	existingMembers := make(map[string]bool)
+	for _, member := range project.Members {
+		existingMembers[member.Username] = true
+	}
+
+	now := time.Now().UTC()
+	results := make([]InvitationResult, 0, len(usernames))
+	seen := make(map[string]bool)
+	for _, username := range usernames {
+		if username == "" || seen[username] {
+			continue
+		}
+		seen[username] = true
+
+		result := InvitationResult{Username: username}
+		if existingMembers[username] {
+			result.Error = "user is already a member of the project"
+			results = append(results, result)
+			continue
+		}
+
+		member, err := s.fetchMemberByUsername(username)
+		if err != nil {
+			result.Error = err.Error()
+			results = append(results, result)
+			continue
+		}
+
+		invitation := &models.Invitation{
+			ID:          primitive.NewObjectID(),
+			ProjectID:   project.ID,
+			ProjectName: project.Name,
+			UserID:      member.ID,
+			Username:    member.Username,
+			ProjectRole: string(role),
+			Status:      models.InvitationPending,
+			InvitedBy:   invitedBy,
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(invitationTTL()),
+		}
+		if _, err := s.InvitationsCollection.InsertOne(ctx, invitation); err != nil {
+			if mongo.IsDuplicateKeyError(err) {
+				result.Error = "user already has a pending invitation"
+			} else {
+				logging.Logger.Errorf("Failed to create invitation for %s to project %s: %v", username, projectID, err)
+				result.Error = "failed to create invitation"
+			}
+			results = append(results, result)
+			continue
+		}
+		result.Invitation = invitation
+		results = append(results, result)
+
+		go func(m models.Member) {
+			message := fmt.Sprintf("You have been invited to join the project: %s", project.Name)
+			_, err := s.NotificationsBreaker.Execute(func() (interface{}, error) {
+				return nil, s.sendNotification(m, message)
+			})
+			if err != nil {
+				logging.Logger.Warnf("Failed to send invitation notification to %s: %v", m.Username, err)
+			}
+		}(member)
+	}
+
+	logging.Logger.Infof("%d invitations processed for project %s by %s", len(results), projectID, invitedBy)
+	return results, nil
+}
+
+// ListProjectInvitations vraća pozive projekta; bez statusa vraća sve pozive.
+func (s *ProjectService) ListProjectInvitations(ctx context.Context, projectID string, status models.InvitationStatus) ([]models.Invitation, error) {
+	projectObjectID, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid project ID format")
+	}
+	filter := bson.M{"project_id": projectObjectID}
+	if status != "" {
+		filter["status"] = status
+	}
+	return s.findInvitations(ctx, filter)
+}
+
+// ListUserInvitations vraća pozive na čekanju za korisnika.
+func (s *ProjectService) ListUserInvitations(ctx context.Context, username string) ([]models.Invitation, error) {
+	return s.findInvitations(ctx, bson.M{"username": username, "status": models.InvitationPending})
+}
+
+// RevokeInvitation povlači poziv koji još nije prihvaćen ni odbijen.
+func (s *ProjectService) RevokeInvitation(ctx context.Context, projectID, invitationID string) error {
+	projectObjectID, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return fmt.Errorf("invalid project ID format")
+	}
+	invitation, err := s.getInvitation(ctx, invitationID)
+	if err != nil {
+		return err
+	}
+	if invitation.ProjectID != projectObjectID {
+		return fmt.Errorf("invitation not found")
+	}
+	if invitation.Status != models.InvitationPending {
+		return fmt.Errorf("only a pending invitation can be revoked")
+	}
+
+	_, err = s.updateInvitationStatus(ctx, invitation.ID, models.InvitationRevoked)
+	if err != nil {
+		return err
+	}
+	logging.Logger.Infof("Invitation %s for %s to project %s revoked", invitationID, invitation.Username, projectID)
+	return nil
+}
+
+// DeclineInvitation beleži da je korisnik odbio poziv.
+func (s *ProjectService) DeclineInvitation(ctx context.Context, invitationID, username string) (*models.Invitation, error) {
+	invitation, err := s.getPendingInvitationFor(ctx, invitationID, username)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.updateInvitationStatus(ctx, invitation.ID, models.InvitationDeclined); err != nil {
+		return nil, err
+	}
+
+	logging.Logger.Infof("User %s declined invitation to project %s", username, invitation.ProjectID.Hex())
+	return s.getInvitation(ctx, invitationID)
+}
+
+// AcceptInvitation dodaje korisnika u projekat sa ulogom iz poziva. Poziv se prvo
+// označava kao prihvaćen, pa ako je projekat u međuvremenu popunjen, vraća se na čekanje.
+func (s *ProjectService) AcceptInvitation(ctx context.Context, invitationID, username string) (*models.Invitation, error) {
+	invitation, err := s.getPendingInvitationFor(ctx, invitationID, username)
+	if err != nil {
+		return nil, err
+	}
+	project, err := s.GetProjectByID(invitation.ProjectID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	if project.ArchivedAt != nil {
+		return nil, projectroles.ErrProjectArchived
+	}
+
+	claimed, err := s.updateInvitationStatus(ctx, invitation.ID, models.InvitationAccepted)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, fmt.Errorf("invitation is no longer pending")
+	}
+
+	member, err := s.fetchMemberByUsername(username)
+	if err != nil {
+		s.reopenInvitation(ctx, invitation.ID)
+		return nil, err
+	}
+	member.ProjectRole = invitation.ProjectRole
+
+	// Limit članova je deo upita, pa istovremena prihvatanja ne mogu da ga pređu
+	filter := bson.M{
+		"_id":              project.ID,
+		"members.username": bson.M{"$ne": username},
+	}
+	if project.MaxMembers > 0 {
+		filter[fmt.Sprintf("members.%d", project.MaxMembers-1)] = bson.M{"$exists": false}
+	}
+	joined := true
+	err = s.inTransaction(ctx, func(ctx context.Context) error {
+		result, err := s.ProjectsCollection.UpdateOne(ctx, filter, bson.M{"$push": bson.M{"members": member}})
+		if err != nil {
+			return err
+		}
+		if joined = result.MatchedCount > 0; !joined {
+			return nil
+		}
+		return s.recordMemberActivity(ctx, project.ID, models.ActivityAddMember, member, fmt.Sprintf("Member %s joined project as %s", username, invitation.ProjectRole))
+	})
+	if err != nil {
+		s.reopenInvitation(ctx, invitation.ID)
+		logging.Logger.Errorf("Failed to add %s to project %s: %v", username, project.ID.Hex(), err)
+		return nil, fmt.Errorf("failed to join project: %v", err)
+	}
+	if !joined {
+		s.reopenInvitation(ctx, invitation.ID)
+		for _, existing := range project.Members {
+			if existing.Username == username {
+				return nil, fmt.Errorf("user is already a member of the project")
+			}
+		}
+		return nil, fmt.Errorf("maximum number of members reached for the project")
+	}
+
+	logging.Logger.Infof("User %s accepted invitation and joined project %s as %s", username, project.ID.Hex(), invitation.ProjectRole)
+	s.emitWebhookEventAsync(project.ID.Hex(), models.WebhookMemberAdded, map[string]interface{}{
+		"memberId":    member.ID.Hex(),
+		"username":    username,
+		"projectRole": invitation.ProjectRole,
+	})
+	return s.getInvitation(ctx, invitationID)
+}
+
+// ExpireInvitations označava istekle pozive na čekanju.
+func (s *ProjectService) ExpireInvitations(ctx context.Context) (int64, error) {
+	result, err := s.InvitationsCollection.UpdateMany(ctx,
+		bson.M{"status": models.InvitationPending, "expires_at": bson.M{"$lte": time.Now().UTC()}},
+		bson.M{"$set": bson.M{"status": models.InvitationExpired, "responded_at": time.Now().UTC()}},
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire invitations: %v", err)
+	}
+	if result.ModifiedCount > 0 {
+		logging.Logger.Infof("%d project invitations expired", result.ModifiedCount)
+	}
+	return result.ModifiedCount, nil
+}
+
+// StartInvitationExpirer periodično označava istekle pozive.
+func (s *ProjectService) StartInvitationExpirer(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := s.ExpireInvitations(ctx); err != nil {
+					logging.Logger.Errorf("Invitation expiry failed: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+func (s *ProjectService) findInvitations(ctx context.Context, filter bson.M) ([]models.Invitation, error) {
+	// Istekli pozivi se označavaju pre čitanja, da lista ne bi zavisila od intervala expirer-a
+	if _, err := s.ExpireInvitations(ctx); err != nil {
+		logging.Logger.Warnf("Failed to expire invitations before listing: %v", err)
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := s.InvitationsCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch invitations: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	invitations := []models.Invitation{}
+	if err := cursor.All(ctx, &invitations); err != nil {
+		return nil, fmt.Errorf("failed to decode invitations: %v", err)
+	}
+	return invitations, nil
+}
+
+func (s *ProjectService) getInvitation(ctx context.Context, invitationID string) (*models.Invitation, error) {
+	invitationObjectID, err := primitive.ObjectIDFromHex(invitationID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid invitation ID format")
+	}
+	var invitation models.Invitation
+	err = s.InvitationsCollection.FindOne(ctx, bson.M{"_id": invitationObjectID}).Decode(&invitation)
+	if err == mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("invitation not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch invitation: %v", err)
+	}
+	return &invitation, nil
+}
+
+// getPendingInvitationFor vraća poziv ako je namenjen korisniku i još uvek važi.
+func (s *ProjectService) getPendingInvitationFor(ctx context.Context, invitationID, username string) (*models.Invitation, error) {
+	invitation, err := s.getInvitation(ctx, invitationID)
+	if err != nil {
+		return nil, err
+	}
+	if invitation.Username != username {
+		return nil, fmt.Errorf("invitation not found")
+	}
+	if invitation.Status == models.InvitationPending && !time.Now().Before(invitation.ExpiresAt) {
+		s.updateInvitationStatus(ctx, invitation.ID, models.InvitationExpired)
+		return nil, fmt.Errorf("invitation has expired")
+	}
+	if invitation.Status != models.InvitationPending {
+		return nil, fmt.Errorf("invitation is already %s", invitation.Status)
+	}
+	return invitation, nil
+}
+
+// updateInvitationStatus menja status poziva samo ako je još na čekanju; vraća false
+// ako ga je u međuvremenu promenio neko drugi.
+func (s *ProjectService) updateInvitationStatus(ctx context.Context, invitationID primitive.ObjectID, status models.InvitationStatus) (bool, error) {
+	result, err := s.InvitationsCollection.UpdateOne(ctx,
+		bson.M{"_id": invitationID, "status": models.InvitationPending},
+		bson.M{"$set": bson.M{"status": status, "responded_at": time.Now().UTC()}},
+	)
+	if err != nil {
+		logging.Logger.Errorf("Failed to set invitation %s to %s: %v", invitationID.Hex(), status, err)
+		return false, fmt.Errorf("failed to update invitation: %v", err)
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+func (s *ProjectService) reopenInvitation(ctx context.Context, invitationID primitive.ObjectID) {
+	_, err := s.InvitationsCollection.UpdateOne(ctx,
+		bson.M{"_id": invitationID, "status": models.InvitationAccepted},
+		bson.M{"$set": bson.M{"status": models.InvitationPending}, "$unset": bson.M{"responded_at": ""}},
+	)
+	if err != nil {
+		logging.Logger.Errorf("Failed to reopen invitation %s: %v", invitationID.Hex(), err)
+	}
+}
+
+// invitationTTL je rok važenja poziva (INVITATION_TTL_DAYS, podrazumevano 7 dana).
+func invitationTTL() time.Duration {
+	days := 7
+	if value := os.Getenv("INVITATION_TTL_DAYS"); value != "" {
+		var parsed int
+		if _, err := fmt.Sscanf(value, "%d", &parsed); err == nil && parsed > 0 {
+			days = parsed
+		}
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
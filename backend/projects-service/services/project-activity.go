@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"trello-project/microservices/projects-service/logging"
+	"trello-project/microservices/projects-service/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// RecordActivity upisuje aktivnost u feed projekta. Koriste ga ovaj servis i tasks-service.
+func (s *ProjectService) RecordActivity(ctx context.Context, activity models.ProjectActivity) error {
+	if !models.ValidActivityType(activity.ActivityType) {
+		return fmt.Errorf("unknown activity type: %s", activity.ActivityType)
+	}
+	if activity.ProjectID.IsZero() {
+		return fmt.Errorf("invalid project ID format")
+	}
+	activity.ID = primitive.NewObjectID()
+	if activity.Timestamp.IsZero() {
+		activity.Timestamp = time.Now().UTC()
+	}
+
+	if _, err := s.ActivitiesCollection.InsertOne(ctx, activity); err != nil {
+		logging.Logger.Errorf("Failed to record %s activity on project %s: %v", activity.ActivityType, activity.ProjectID.Hex(), err)
+		return fmt.Errorf("failed to record activity")
+	}
+	return nil
+}
+
+// recordMemberActivity upisuje događaj o dodavanju ili uklanjanju člana projekta u
+// okviru izmene (ctx iz inTransaction), a aktivnost u feed-u beleži u pozadini posle
+// potvrde izmene.
+func (s *ProjectService) recordMemberActivity(ctx context.Context, projectID primitive.ObjectID, activityType models.ActivityType, member models.Member, details string) error {
+	memberID := member.ID
+	if err := s.publishMemberEvent(ctx, projectID, activityType, member); err != nil {
+		return err
+	}
+	afterCommit(ctx, func() {
+		go func() {
+			err := s.RecordActivity(context.Background(), models.ProjectActivity{
+				ProjectID:    projectID,
+				ActivityType: activityType,
+				MemberID:     &memberID,
+				Details:      details,
+			})
+			if err != nil {
+				logging.Logger.Warnf("Activity %s for member %s on project %s was not recorded: %v", activityType, member.Username, projectID.Hex(), err)
+			}
+		}()
+	})
+	return nil
+}
+
+// ListProjectActivity vraća stranu feed-a aktivnosti projekta, od najnovije.
+func (s *ProjectService) ListProjectActivity(ctx context.Context, projectID string, filter models.ActivityFilter) (*models.ActivityPage, error) {
+	projectObjectID, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid project ID format")
+	}
+
+	query := bson.M{"projectId": projectObjectID}
+	if len(filter.Types) > 0 {
+		query["activityType"] = bson.M{"$in": filter.Types}
+	}
+	if filter.TaskID != nil {
+		query["taskId"] = *filter.TaskID
+	}
+	if filter.MemberID != nil {
+		query["memberId"] = *filter.MemberID
+	}
+	if filter.Actor != "" {
+		query["actor"] = filter.Actor
+	}
+	if filter.Since != nil || filter.Until != nil {
+		timestamp := bson.M{}
+		if filter.Since != nil {
+			timestamp["$gte"] = filter.Since.UTC()
+		}
+		if filter.Until != nil {
+			timestamp["$lt"] = filter.Until.UTC()
+		}
+		query["timestamp"] = timestamp
+	}
+
+	total, err := s.ActivitiesCollection.CountDocuments(ctx, query)
+	if err != nil {
+		logging.Logger.Errorf("Failed to count activity of project %s: %v", projectID, err)
+		return nil, fmt.Errorf("failed to fetch activity")
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64((filter.Page - 1) * filter.PageSize)).
+		SetLimit(int64(filter.PageSize))
+	cursor, err := s.ActivitiesCollection.Find(ctx, query, opts)
+	if err != nil {
+		logging.Logger.Errorf("Failed to fetch activity of project %s: %v", projectID, err)
+		return nil, fmt.Errorf("failed to fetch activity")
+	}
+	defer cursor.Close(ctx)
+
+	items := []models.ProjectActivity{}
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, fmt.Errorf("failed to decode activity: %v", err)
+	}
+	return &models.ActivityPage{Items: items, Total: total, Page: filter.Page, PageSize: filter.PageSize}, nil
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"trello-project/microservices/projects-service/logging"
+	"trello-project/microservices/projects-service/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Vrednosti parametra archived u listama projekata.
+const (
+	ArchivedExclude = ""
+	ArchivedOnly    = "only"
+	ArchivedInclude = "include"
+)
+
+// ArchiveProject arhivira projekat. Arhiviran projekat ostaje vidljiv i može se izvesti,
+// ali se ne može menjati dok se ne vrati iz arhive.
+func (s *ProjectService) ArchiveProject(ctx context.Context, projectID string) (*models.Project, error) {
+	project, err := s.GetProjectByID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	if project.ArchivedAt != nil {
+		return nil, fmt.Errorf("project is already archived")
+	}
+
+	result, err := s.ProjectsCollection.UpdateOne(ctx,
+		bson.M{"_id": project.ID, "archived_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"archived_at": time.Now().UTC()}},
+	)
+	if err != nil {
+		logging.Logger.Errorf("Failed to archive project %s: %v", projectID, err)
+		return nil, fmt.Errorf("failed to archive project: %v", err)
+	}
+	if result.MatchedCount == 0 {
+		return nil, fmt.Errorf("project is already archived")
+	}
+
+	logging.Logger.Infof("Project %s archived", projectID)
+	return s.GetProjectByID(projectID)
+}
+
+// UnarchiveProject vraća projekat iz arhive.
+func (s *ProjectService) UnarchiveProject(ctx context.Context, projectID string) (*models.Project, error) {
+	project, err := s.GetProjectByID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	if project.ArchivedAt == nil {
+		return nil, fmt.Errorf("project is not archived")
+	}
+
+	_, err = s.ProjectsCollection.UpdateOne(ctx,
+		bson.M{"_id": project.ID},
+		bson.M{"$unset": bson.M{"archived_at": ""}},
+	)
+	if err != nil {
+		logging.Logger.Errorf("Failed to unarchive project %s: %v", projectID, err)
+		return nil, fmt.Errorf("failed to unarchive project: %v", err)
+	}
+
+	logging.Logger.Infof("Project %s unarchived", projectID)
+	return s.GetProjectByID(projectID)
+}
+
+// withArchivedFilter dodaje uslov arhiviranosti u filter liste projekata: podrazumevano
+// se arhivirani projekti izostavljaju, ArchivedOnly vraća samo njih, a ArchivedInclude sve.
+func withArchivedFilter(filter bson.M, archived string) (bson.M, error) {
+	switch archived {
+	case ArchivedExclude:
+		filter["archived_at"] = bson.M{"$exists": false}
+	case ArchivedOnly:
+		filter["archived_at"] = bson.M{"$exists": true}
+	case ArchivedInclude:
+	default:
+		return nil, fmt.Errorf("invalid archived filter: %s", archived)
+	}
+	return filter, nil
+}
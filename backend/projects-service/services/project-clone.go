@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"trello-project/microservices/projects-service/logging"
+	"trello-project/microservices/projects-service/models"
+
+	"trello-project/backend/utils/projectroles"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CloneProject pravi novi projekat po uzoru na postojeći. Po izboru kopira članove sa
+// njihovim ulogama, zadatke (kroz tasks-service, sa statusom Pending) i zavisnosti među
+// zadacima (kroz workflow-service, sa ID-jevima kopija). Ako bilo koji korak ne uspe,
+// novi projekat se uklanja zajedno sa već kopiranim zadacima i čvorovima.
+func (s *ProjectService) CloneProject(ctx context.Context, projectID string, clone models.ProjectClone, managerUsername string) (*models.ProjectCloneReport, error) {
+	source, err := s.GetProjectByID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	if source.DeletionSagaID != nil {
+		return nil, projectroles.ErrProjectDeleting
+	}
+	if clone.CopyWorkflow && !clone.CopyTasks {
+		return nil, fmt.Errorf("copying the workflow requires copying tasks")
+	}
+
+	description := source.Description
+	if clone.Description != nil {
+		description = *clone.Description
+	}
+	minMembers, maxMembers := source.MinMembers, source.MaxMembers
+	if clone.MinMembers != nil {
+		minMembers = *clone.MinMembers
+	}
+	if clone.MaxMembers != nil {
+		maxMembers = *clone.MaxMembers
+	}
+	if clone.CopyMembers && len(source.Members) > maxMembers {
+		return nil, fmt.Errorf("maxMembers cannot be lower than the number of copied members (%d)", len(source.Members))
+	}
+
+	managerID := source.ManagerID
+	if managerUsername != "" {
+		if managerID, err = s.getUserIDByUsername(managerUsername); err != nil {
+			return nil, fmt.Errorf("failed to resolve manager: %v", err)
+		}
+	}
+
+	project, err := s.CreateProject(clone.Name, description, clone.ExpectedEndDate, minMembers, maxMembers, managerID)
+	if err != nil {
+		return nil, err
+	}
+	report := &models.ProjectCloneReport{SourceProjectID: projectID}
+
+	if clone.CopyMembers && len(source.Members) > 0 {
+		if _, err := s.ProjectsCollection.UpdateOne(ctx, bson.M{"_id": project.ID}, bson.M{"$set": bson.M{"members": source.Members}}); err != nil {
+			s.rollbackClone(ctx, project.ID, false)
+			return nil, fmt.Errorf("failed to copy members: %v", err)
+		}
+		report.MembersCopied = len(source.Members)
+	}
+
+	if clone.CopyTasks {
+		var cloned []struct {
+			SourceTaskID string `json:"sourceTaskId"`
+			TaskID       string `json:"taskId"`
+		}
+		payload := map[string]interface{}{
+			"targetProjectId": project.ID.Hex(),
+			"copyMembers":     clone.CopyMembers,
+			"dueDateShift":    clone.ExpectedEndDate.Sub(source.ExpectedEndDate),
+			"actor":           managerUsername,
+		}
+		err := s.callService(s.TasksBreaker, http.MethodPost, "TASKS_SERVICE_URL", fmt.Sprintf("/api/tasks/project/%s/clone", projectID), payload, &cloned)
+		if err != nil {
+			s.rollbackClone(ctx, project.ID, true)
+			return nil, fmt.Errorf("failed to copy tasks: %v", err)
+		}
+		report.TasksCopied = len(cloned)
+
+		if clone.CopyWorkflow && len(cloned) > 0 {
+			taskIDMap := make(map[string]string, len(cloned))
+			for _, task := range cloned {
+				taskIDMap[task.SourceTaskID] = task.TaskID
+			}
+			var result struct {
+				Copied int64 `json:"copied"`
+			}
+			payload := map[string]interface{}{"targetProjectId": project.ID.Hex(), "taskIdMap": taskIDMap}
+			err := s.callService(s.WorkflowBreaker, http.MethodPost, "WORKFLOW_SERVICE_URL", fmt.Sprintf("/api/workflow/project/%s/clone", projectID), payload, &result)
+			if err != nil {
+				s.rollbackClone(ctx, project.ID, true)
+				return nil, fmt.Errorf("failed to copy workflow: %v", err)
+			}
+			report.DependenciesCopied = result.Copied
+		}
+	}
+
+	report.Project, err = s.GetProjectByID(project.ID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	logging.Logger.Infof("Project %s cloned into %s: %d members, %d tasks, %d dependencies", projectID, project.ID.Hex(), report.MembersCopied, report.TasksCopied, report.DependenciesCopied)
+	return report, nil
+}
+
+// rollbackClone uklanja delimično kloniran projekat. Kopije zadataka se brišu trajno (uz
+// kompenzujuće task.deleted događaje), a ne kao pri brisanju projekta, jer nemaju šta da
+// se vrati. Zadaci i čvorovi koje ne uspe da ukloni ostaju vezani za projekat koji više
+// ne postoji i samo se loguju.
+func (s *ProjectService) rollbackClone(ctx context.Context, projectID primitive.ObjectID, tasksCopied bool) {
+	logging.Logger.Warnf("Rolling back clone into project %s", projectID.Hex())
+	if tasksCopied {
+		if err := s.callService(s.WorkflowBreaker, http.MethodDelete, "WORKFLOW_SERVICE_URL", "/api/workflow/project/"+projectID.Hex(), nil, nil); err != nil {
+			logging.Logger.Errorf("Clone rollback: failed to delete task nodes of project %s: %v", projectID.Hex(), err)
+		}
+		if err := s.callService(s.TasksBreaker, http.MethodDelete, "TASKS_SERVICE_URL", "/api/tasks/project/"+projectID.Hex()+"/clone", nil, nil); err != nil {
+			logging.Logger.Errorf("Clone rollback: failed to delete tasks of project %s: %v", projectID.Hex(), err)
+		}
+	}
+
+	rollbackCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer cancel()
+	if _, err := s.ProjectsCollection.DeleteOne(rollbackCtx, bson.M{"_id": projectID}); err != nil {
+		logging.Logger.Errorf("Clone rollback: failed to delete project %s: %v", projectID.Hex(), err)
+	}
+}
@@ -0,0 +1,401 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"trello-project/microservices/projects-service/logging"
+	"trello-project/microservices/projects-service/models"
+
+	"trello-project/backend/utils/events"
+
+	"github.com/sony/gobreaker"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// deletionMaxAttempts je broj pokušaja koraka pre brisanja workflow čvorova, nakon
+	// kojih se brisanje odustaje i pokreće kompenzacija.
+	deletionMaxAttempts = 5
+	// deletionLease je vreme za koje jedna instanca drži sagu; posle pada servisa saga
+	// se nastavlja kada lease istekne.
+	deletionLease = 2 * time.Minute
+)
+
+var ErrDeletionNotFound = errors.New("no deletion found for project")
+
+// StartProjectDeletion zaključava projekat i pokreće sagu brisanja u pozadini. Ako je
+// brisanje projekta već u toku, vraća postojeću sagu.
+func (s *ProjectService) StartProjectDeletion(ctx context.Context, projectID, requestedBy string) (*models.DeletionSaga, error) {
+	project, err := s.GetProjectByID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	if project.DeletionSagaID != nil {
+		return s.getDeletionSaga(ctx, *project.DeletionSagaID)
+	}
+
+	now := time.Now().UTC()
+	saga := &models.DeletionSaga{
+		ID:          primitive.NewObjectID(),
+		ProjectID:   project.ID,
+		ProjectName: project.Name,
+		RequestedBy: requestedBy,
+		Status:      models.DeletionRunning,
+		Steps: []models.DeletionStep{
+			{Name: models.StepLockProject, Status: models.StepDone, Attempts: 1, UpdatedAt: &now},
+			{Name: models.StepDeleteTasks, Status: models.StepPending},
+			{Name: models.StepDeleteWorkflow, Status: models.StepPending},
+			{Name: models.StepDeleteProject, Status: models.StepPending},
+		},
+		CreatedAt:     now,
+		UpdatedAt:     now,
+		NextAttemptAt: now,
+	}
+	if _, err := s.DeletionSagasCollection.InsertOne(ctx, saga); err != nil {
+		logging.Logger.Errorf("Failed to create deletion saga for project %s: %v", projectID, err)
+		return nil, fmt.Errorf("failed to start project deletion: %v", err)
+	}
+
+	// Projekat se zaključava uslovno, pa od dva istovremena zahteva pobeđuje jedan
+	result, err := s.ProjectsCollection.UpdateOne(ctx,
+		bson.M{"_id": project.ID, "deletion_saga_id": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"deletion_saga_id": saga.ID}},
+	)
+	if err != nil || result.MatchedCount == 0 {
+		s.DeletionSagasCollection.DeleteOne(ctx, bson.M{"_id": saga.ID})
+		if err != nil {
+			logging.Logger.Errorf("Failed to lock project %s for deletion: %v", projectID, err)
+			return nil, fmt.Errorf("failed to start project deletion: %v", err)
+		}
+		return s.GetProjectDeletion(ctx, projectID)
+	}
+
+	logging.Logger.Infof("Deletion saga %s started for project %s by %s", saga.ID.Hex(), projectID, requestedBy)
+	go s.RunDeletionSaga(context.Background(), saga.ID)
+	return saga, nil
+}
+
+// GetProjectDeletion vraća poslednju sagu brisanja projekta. Saga ostaje sačuvana i
+// nakon što je projekat obrisan, kako bi menadžer mogao da proveri ishod.
+func (s *ProjectService) GetProjectDeletion(ctx context.Context, projectID string) (*models.DeletionSaga, error) {
+	projectObjectID, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid project ID format")
+	}
+
+	var saga models.DeletionSaga
+	opts := options.FindOne().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	err = s.DeletionSagasCollection.FindOne(ctx, bson.M{"project_id": projectObjectID}, opts).Decode(&saga)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrDeletionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch project deletion: %v", err)
+	}
+	return &saga, nil
+}
+
+// RunDeletionSaga izvršava preostale korake sage. Stanje se čuva posle svakog koraka;
+// neuspeli korak se zakazuje za ponovni pokušaj, koji preuzima StartDeletionSagaWorker.
+func (s *ProjectService) RunDeletionSaga(ctx context.Context, sagaID primitive.ObjectID) {
+	saga, err := s.claimDeletionSaga(ctx, sagaID)
+	if err != nil {
+		logging.Logger.Errorf("Failed to claim deletion saga %s: %v", sagaID.Hex(), err)
+		return
+	}
+	if saga == nil {
+		return
+	}
+
+	for !saga.Finished() {
+		index, compensate := nextDeletionStep(saga)
+		if index < 0 {
+			s.finishDeletionSaga(ctx, saga)
+			return
+		}
+
+		step := &saga.Steps[index]
+		step.Attempts++
+		if compensate {
+			err = s.compensateDeletionStep(ctx, saga, step.Name)
+		} else {
+			err = s.executeDeletionStep(ctx, saga, step.Name)
+		}
+
+		now := time.Now().UTC()
+		step.UpdatedAt = &now
+		if err == nil {
+			step.LastError = ""
+			if compensate {
+				step.Status = models.StepCompensated
+			} else {
+				step.Status = models.StepDone
+			}
+			if err := s.saveDeletionSaga(ctx, saga, now); err != nil {
+				return
+			}
+			continue
+		}
+
+		step.LastError = err.Error()
+		logging.Logger.Warnf("Deletion saga %s: step %s for project %s failed (attempt %d): %v", saga.ID.Hex(), step.Name, saga.ProjectID.Hex(), step.Attempts, err)
+		if !compensate && step.Attempts >= deletionMaxAttempts && step.Name != models.StepDeleteProject {
+			// Čvorovi još nisu obrisani, pa se brisanje može poništiti
+			saga.Status = models.DeletionCompensating
+			saga.Error = fmt.Sprintf("%s failed: %v", step.Name, err)
+			if step.Name == models.StepDeleteTasks {
+				// Zadaci su možda obrisani iako odgovor nije stigao, pa se i ovaj korak vraća
+				step.Status = models.StepDone
+			}
+			logging.Logger.Errorf("Deletion saga %s for project %s gave up on %s, compensating", saga.ID.Hex(), saga.ProjectID.Hex(), step.Name)
+			if err := s.saveDeletionSaga(ctx, saga, now); err != nil {
+				return
+			}
+			continue
+		}
+
+		saga.NextAttemptAt = now.Add(deletionRetryDelay(step.Attempts))
+		s.saveDeletionSaga(ctx, saga, now)
+		return
+	}
+}
+
+// StartDeletionSagaWorker periodično nastavlja sage koje čekaju ponovni pokušaj ili su
+// prekinute padom servisa.
+func (s *ProjectService) StartDeletionSagaWorker(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			s.resumeDeletionSagas(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (s *ProjectService) resumeDeletionSagas(ctx context.Context) {
+	now := time.Now().UTC()
+	cursor, err := s.DeletionSagasCollection.Find(ctx, bson.M{
+		"status":          bson.M{"$in": []models.DeletionSagaStatus{models.DeletionRunning, models.DeletionCompensating}},
+		"next_attempt_at": bson.M{"$lte": now},
+		"locked_until":    bson.M{"$lte": now},
+	}, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		logging.Logger.Errorf("Failed to fetch pending deletion sagas: %v", err)
+		return
+	}
+	defer cursor.Close(ctx)
+
+	var pending []models.DeletionSaga
+	if err := cursor.All(ctx, &pending); err != nil {
+		logging.Logger.Errorf("Failed to decode pending deletion sagas: %v", err)
+		return
+	}
+	for _, saga := range pending {
+		s.RunDeletionSaga(ctx, saga.ID)
+	}
+}
+
+// nextDeletionStep vraća indeks sledećeg koraka i da li se on kompenzuje. Kompenzacija
+// ide unazad kroz završene korake; -1 znači da je saga gotova.
+func nextDeletionStep(saga *models.DeletionSaga) (int, bool) {
+	if saga.Status == models.DeletionCompensating {
+		for i := len(saga.Steps) - 1; i >= 0; i-- {
+			if saga.Steps[i].Status == models.StepDone {
+				return i, true
+			}
+		}
+		return -1, true
+	}
+	for i := range saga.Steps {
+		if saga.Steps[i].Status == models.StepPending {
+			return i, false
+		}
+	}
+	return -1, false
+}
+
+func (s *ProjectService) executeDeletionStep(ctx context.Context, saga *models.DeletionSaga, step string) error {
+	projectID := saga.ProjectID.Hex()
+	switch step {
+	case models.StepDeleteTasks:
+		return s.callService(s.TasksBreaker, http.MethodDelete, "TASKS_SERVICE_URL", "/api/tasks/project/"+projectID, nil, nil)
+	case models.StepDeleteWorkflow:
+		return s.callService(s.WorkflowBreaker, http.MethodDelete, "WORKFLOW_SERVICE_URL", "/api/workflow/project/"+projectID, nil, nil)
+	case models.StepDeleteProject:
+		if _, err := s.SprintsCollection.DeleteMany(ctx, bson.M{"project_id": saga.ProjectID}); err != nil {
+			return fmt.Errorf("failed to delete sprints: %v", err)
+		}
+		if _, err := s.InvitationsCollection.DeleteMany(ctx, bson.M{"project_id": saga.ProjectID}); err != nil {
+			return fmt.Errorf("failed to delete invitations: %v", err)
+		}
+		if _, err := s.ProjectsCollection.DeleteOne(ctx, bson.M{"_id": saga.ProjectID, "deletion_saga_id": saga.ID}); err != nil {
+			return fmt.Errorf("failed to delete project: %v", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown deletion step %s", step)
+	}
+}
+
+func (s *ProjectService) compensateDeletionStep(ctx context.Context, saga *models.DeletionSaga, step string) error {
+	switch step {
+	case models.StepLockProject:
+		_, err := s.ProjectsCollection.UpdateOne(ctx,
+			bson.M{"_id": saga.ProjectID, "deletion_saga_id": saga.ID},
+			bson.M{"$unset": bson.M{"deletion_saga_id": ""}},
+		)
+		if err != nil {
+			return fmt.Errorf("failed to unlock project: %v", err)
+		}
+		return nil
+	case models.StepDeleteTasks:
+		return s.callService(s.TasksBreaker, http.MethodPost, "TASKS_SERVICE_URL", "/api/tasks/project/"+saga.ProjectID.Hex()+"/restore", nil, nil)
+	default:
+		return fmt.Errorf("deletion step %s cannot be compensated", step)
+	}
+}
+
+// callService poziva drugi servis kao servis (bez korisničkog tokena), jer saga brisanja
+// može da se nastavi i kada je token menadžera već istekao. Odgovor se dekodira u result
+// ako nije nil.
+func (s *ProjectService) callService(breaker *gobreaker.CircuitBreaker, method, urlEnv, path string, payload, result interface{}) error {
+	baseURL := os.Getenv(urlEnv)
+	if baseURL == "" {
+		return fmt.Errorf("%s not set", urlEnv)
+	}
+	var body []byte
+	if payload != nil {
+		var err error
+		if body, err = json.Marshal(payload); err != nil {
+			return fmt.Errorf("failed to encode request: %v", err)
+		}
+	}
+
+	_, err := breaker.Execute(func() (interface{}, error) {
+		req, err := http.NewRequest(method, strings.TrimRight(baseURL, "/")+path, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Role", "manager")
+
+		resp, err := s.HTTPClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+			respBody, _ := io.ReadAll(resp.Body)
+			return nil, fmt.Errorf("%s %s returned %d: %s", method, path, resp.StatusCode, strings.TrimSpace(string(respBody)))
+		}
+		if result != nil {
+			return nil, json.NewDecoder(resp.Body).Decode(result)
+		}
+		return nil, nil
+	})
+	return err
+}
+
+// claimDeletionSaga preuzima sagu za ovu instancu; nil znači da je saga gotova ili da je
+// trenutno izvršava neko drugi.
+func (s *ProjectService) claimDeletionSaga(ctx context.Context, sagaID primitive.ObjectID) (*models.DeletionSaga, error) {
+	now := time.Now().UTC()
+	var saga models.DeletionSaga
+	err := s.DeletionSagasCollection.FindOneAndUpdate(ctx,
+		bson.M{
+			"_id":          sagaID,
+			"status":       bson.M{"$in": []models.DeletionSagaStatus{models.DeletionRunning, models.DeletionCompensating}},
+			"locked_until": bson.M{"$lte": now},
+		},
+		bson.M{"$set": bson.M{"locked_until": now.Add(deletionLease)}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&saga)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &saga, nil
+}
+
+func (s *ProjectService) finishDeletionSaga(ctx context.Context, saga *models.DeletionSaga) {
+	now := time.Now().UTC()
+	if saga.Status == models.DeletionCompensating {
+		saga.Status = models.DeletionCompensated
+		logging.Logger.Warnf("Deletion saga %s compensated, project %s was kept", saga.ID.Hex(), saga.ProjectID.Hex())
+	} else {
+		saga.Status = models.DeletionCompleted
+		saga.Error = ""
+		logging.Logger.Infof("Deletion saga %s completed, project %s deleted", saga.ID.Hex(), saga.ProjectID.Hex())
+		err := s.inTransaction(ctx, func(ctx context.Context) error {
+			if err := s.saveDeletionSaga(ctx, saga, now); err != nil {
+				return err
+			}
+			return s.publishEvent(ctx, events.Event{
+				ID:        saga.ID.Hex(),
+				Type:      events.ProjectDeleted,
+				ProjectID: saga.ProjectID.Hex(),
+				Actor:     saga.RequestedBy,
+			})
+		})
+		if err != nil {
+			logging.Logger.Errorf("Failed to complete deletion saga %s: %v", saga.ID.Hex(), err)
+		}
+		return
+	}
+	s.saveDeletionSaga(ctx, saga, now)
+}
+
+// saveDeletionSaga upisuje stanje sage i oslobađa je kada nema više posla ili čeka
+// sledeći pokušaj.
+func (s *ProjectService) saveDeletionSaga(ctx context.Context, saga *models.DeletionSaga, now time.Time) error {
+	saga.UpdatedAt = now
+	if saga.Finished() || saga.NextAttemptAt.After(now) {
+		saga.LockedUntil = time.Time{}
+	} else {
+		saga.LockedUntil = now.Add(deletionLease)
+	}
+
+	_, err := s.DeletionSagasCollection.ReplaceOne(ctx, bson.M{"_id": saga.ID}, saga)
+	if err != nil {
+		logging.Logger.Errorf("Failed to save deletion saga %s: %v", saga.ID.Hex(), err)
+		return fmt.Errorf("failed to save deletion saga: %v", err)
+	}
+	return nil
+}
+
+func (s *ProjectService) getDeletionSaga(ctx context.Context, sagaID primitive.ObjectID) (*models.DeletionSaga, error) {
+	var saga models.DeletionSaga
+	if err := s.DeletionSagasCollection.FindOne(ctx, bson.M{"_id": sagaID}).Decode(&saga); err != nil {
+		return nil, fmt.Errorf("failed to fetch deletion saga: %v", err)
+	}
+	return &saga, nil
+}
+
+// deletionRetryDelay raste sa brojem pokušaja, najviše do deset minuta.
+func deletionRetryDelay(attempts int) time.Duration {
+	delay := time.Duration(attempts*attempts) * 15 * time.Second
+	if delay > 10*time.Minute {
+		return 10 * time.Minute
+	}
+	return delay
+}
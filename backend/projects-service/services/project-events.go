@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"trello-project/microservices/projects-service/logging"
+	"trello-project/microservices/projects-service/models"
+
+	"trello-project/backend/utils/events"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// publishEvent upisuje domenski događaj u outbox i, kada se izmena potvrdi, pokušava
+// odmah da ga objavi; ako broker nije dostupan, objaviće ga StartEventRelay. Poziva se
+// iz inTransaction, zajedno sa izmenom na koju se događaj odnosi.
+func (s *ProjectService) publishEvent(ctx context.Context, event events.Event) error {
+	if s.EventOutbox == nil {
+		return nil
+	}
+	if event.ID == "" {
+		event.ID = primitive.NewObjectID().Hex()
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now().UTC()
+	}
+	if err := s.EventOutbox.Add(ctx, event); err != nil {
+		logging.Logger.Errorf("Failed to store %s event for project %s: %v", event.Type, event.ProjectID, err)
+		return fmt.Errorf("failed to store %s event: %v", event.Type, err)
+	}
+	afterCommit(ctx, func() {
+		go func() {
+			if err := s.EventOutbox.Send(context.Background(), event); err != nil {
+				logging.Logger.Warnf("Event %s will be published by the relay: %v", event.ID, err)
+			}
+		}()
+	})
+	return nil
+}
+
+type afterCommitKey struct{}
+
+// inTransaction izvršava write tako da izmena i domenski događaji koje write upiše kroz
+// publishEvent budu sačuvani u istoj transakciji, pa pad servisa posle izmene ne može da
+// izgubi događaj. Objava događaja i beleženje aktivnosti čekaju potvrdu transakcije.
+// write može biti ponovljen, pa pozivi drugih servisa i obaveštenja idu posle
+// inTransaction. Bez outbox-a (NATS nije podešen) write se izvršava bez transakcije.
+func (s *ProjectService) inTransaction(ctx context.Context, write func(ctx context.Context) error) error {
+	if s.EventOutbox == nil {
+		return write(ctx)
+	}
+	var pending *[]func()
+	err := s.EventOutbox.Transaction(ctx, func(txCtx mongo.SessionContext) error {
+		actions := []func(){}
+		pending = &actions
+		return write(context.WithValue(txCtx, afterCommitKey{}, pending))
+	})
+	if err != nil {
+		return err
+	}
+	for _, action := range *pending {
+		action()
+	}
+	return nil
+}
+
+// afterCommit izvršava action odmah, odnosno posle potvrde ako je ctx u inTransaction.
+func afterCommit(ctx context.Context, action func()) {
+	if pending, ok := ctx.Value(afterCommitKey{}).(*[]func()); ok {
+		*pending = append(*pending, action)
+		return
+	}
+	action()
+}
+
+// projectCreatedEvent ima ID projekta kao ID događaja, pa ga BackfillEvents može
+// ponovo upisati bez dupliranja.
+func projectCreatedEvent(project *models.Project) events.Event {
+	expectedEndDate := project.ExpectedEndDate
+	return events.Event{
+		ID:              project.ID.Hex(),
+		Type:            events.ProjectCreated,
+		ProjectID:       project.ID.Hex(),
+		ExpectedEndDate: &expectedEndDate,
+		OccurredAt:      project.ID.Timestamp().UTC(),
+	}
+}
+
+func (s *ProjectService) publishMemberEvent(ctx context.Context, projectID primitive.ObjectID, activityType models.ActivityType, member models.Member) error {
+	eventType := events.ProjectMemberAdded
+	if activityType == models.ActivityRemoveMember {
+		eventType = events.ProjectMemberRemoved
+	}
+	return s.publishEvent(ctx, events.Event{
+		Type:      eventType,
+		ProjectID: projectID.Hex(),
+		MemberID:  member.ID.Hex(),
+	})
+}
+
+// StartEventRelay periodično objavljuje događaje iz outbox-a koji nisu objavljeni odmah.
+func (s *ProjectService) StartEventRelay(ctx context.Context, interval time.Duration) {
+	if s.EventOutbox == nil {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				published, err := s.EventOutbox.Relay(ctx, interval, 500)
+				if published > 0 {
+					logging.Logger.Infof("Published %d pending domain events", published)
+				}
+				if err != nil {
+					logging.Logger.Warnf("Domain event relay failed: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// BackfillEvents upisuje project.created događaj za svaki postojeći projekat, da bi
+// analytics-service znao rokove projekata nastalih pre uvođenja događaja.
+func (s *ProjectService) BackfillEvents(ctx context.Context) (int, error) {
+	if s.EventOutbox == nil {
+		return 0, nil
+	}
+	cursor, err := s.ProjectsCollection.Find(ctx, bson.M{})
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	count := 0
+	for cursor.Next(ctx) {
+		var project models.Project
+		if err := cursor.Decode(&project); err != nil {
+			return count, err
+		}
+		if err := s.EventOutbox.Add(ctx, projectCreatedEvent(&project)); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, cursor.Err()
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"trello-project/microservices/projects-service/logging"
+	"trello-project/microservices/projects-service/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrOwnedProjectsRemain vraća se kada se uklanja menadžer koji je još vlasnik projekata.
+var ErrOwnedProjectsRemain = errors.New("transfer ownership of owned projects first")
+
+// TransferOwnership predaje projekat drugom menadžeru. Novi vlasnik mora imati globalnu
+// ulogu manager; ažuriranje je uslovljeno trenutnim vlasnikom kako istovremeni prenos
+// ne bi pregazio drugi.
+func (s *ProjectService) TransferOwnership(ctx context.Context, projectID, newOwnerUsername string) (*models.Project, error) {
+	project, err := s.GetProjectByID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.assignOwner(ctx, project, bson.M{"manager_id": project.ManagerID}, newOwnerUsername); err != nil {
+		return nil, err
+	}
+	return s.GetProjectByID(projectID)
+}
+
+// ListOrphanedProjects vraća projekte bez vlasnika: one kojima manager_id nedostaje i one
+// čiji menadžer više ne postoji u users-service-u.
+func (s *ProjectService) ListOrphanedProjects(ctx context.Context) ([]models.Project, error) {
+	cursor, err := s.ProjectsCollection.Find(ctx, bson.M{})
+	if err != nil {
+		logging.Logger.Errorf("Failed to fetch projects while looking for orphans: %v", err)
+		return nil, fmt.Errorf("failed to fetch projects")
+	}
+	defer cursor.Close(ctx)
+
+	var projects []models.Project
+	if err := cursor.All(ctx, &projects); err != nil {
+		return nil, fmt.Errorf("failed to decode projects: %v", err)
+	}
+
+	// Postojanje menadžera proveravamo jednom po ID-u, ne jednom po projektu
+	exists := make(map[primitive.ObjectID]bool)
+	orphaned := []models.Project{}
+	for _, project := range projects {
+		if project.ManagerID.IsZero() {
+			orphaned = append(orphaned, project)
+			continue
+		}
+		found, checked := exists[project.ManagerID]
+		if !checked {
+			found, err = s.userExists(project.ManagerID)
+			if err != nil {
+				return nil, err
+			}
+			exists[project.ManagerID] = found
+		}
+		if !found {
+			orphaned = append(orphaned, project)
+		}
+	}
+	return orphaned, nil
+}
+
+// ReassignOrphanedProject dodeljuje projekat bez vlasnika novom menadžeru. Projekat koji
+// još ima postojećeg vlasnika prenosi se kroz TransferOwnership.
+func (s *ProjectService) ReassignOrphanedProject(ctx context.Context, projectID, newOwnerUsername string) (*models.Project, error) {
+	project, err := s.GetProjectByID(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	ownerFilter := bson.M{"manager_id": project.ManagerID}
+	if project.ManagerID.IsZero() {
+		ownerFilter = bson.M{"$or": []bson.M{
+			{"manager_id": bson.M{"$exists": false}},
+			{"manager_id": nil},
+			{"manager_id": primitive.NilObjectID},
+		}}
+	} else {
+		found, err := s.userExists(project.ManagerID)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			return nil, fmt.Errorf("project is not orphaned")
+		}
+	}
+
+	if err := s.assignOwner(ctx, project, ownerFilter, newOwnerUsername); err != nil {
+		return nil, err
+	}
+	return s.GetProjectByID(projectID)
+}
+
+// CountOwnedProjects vraća broj projekata čiji je vlasnik dati menadžer. Arhivirani
+// projekti se računaju: bez vlasnika ne bi mogli da se vrate iz arhive, pa i njih treba
+// preneti pre uklanjanja menadžera.
+func (s *ProjectService) CountOwnedProjects(ctx context.Context, managerID primitive.ObjectID) (int64, error) {
+	return s.ProjectsCollection.CountDocuments(ctx, bson.M{"manager_id": managerID})
+}
+
+func (s *ProjectService) assignOwner(ctx context.Context, project *models.Project, ownerFilter bson.M, newOwnerUsername string) error {
+	if newOwnerUsername == "" {
+		return fmt.Errorf("new owner username is required")
+	}
+	role, err := s.getUserRoleByUsername(newOwnerUsername)
+	if err != nil {
+		return fmt.Errorf("failed to resolve new owner %s: %v", newOwnerUsername, err)
+	}
+	if role != "manager" {
+		return fmt.Errorf("new owner must be a manager")
+	}
+	newOwnerID, err := s.getUserIDByUsername(newOwnerUsername)
+	if err != nil {
+		return fmt.Errorf("failed to resolve new owner %s: %v", newOwnerUsername, err)
+	}
+	if newOwnerID == project.ManagerID {
+		return fmt.Errorf("user already owns the project")
+	}
+
+	filter := bson.M{"_id": project.ID}
+	for key, value := range ownerFilter {
+		filter[key] = value
+	}
+	result, err := s.ProjectsCollection.UpdateOne(ctx, filter, bson.M{
+		"$set":  bson.M{"manager_id": newOwnerID},
+		"$pull": bson.M{"members": bson.M{"username": newOwnerUsername}},
+	})
+	if err != nil {
+		logging.Logger.Errorf("Failed to assign owner %s to project %s: %v", newOwnerUsername, project.ID.Hex(), err)
+		return fmt.Errorf("failed to transfer ownership: %v", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("project owner changed concurrently")
+	}
+
+	logging.Logger.Infof("Project %s is now owned by %s", project.ID.Hex(), newOwnerUsername)
+
+	message := fmt.Sprintf("You are now the owner of project '%s'.", project.Name)
+	_, err = s.NotificationsBreaker.Execute(func() (interface{}, error) {
+		return nil, s.sendNotification(models.Member{ID: newOwnerID, Username: newOwnerUsername}, message)
+	})
+	if err != nil {
+		logging.Logger.Warnf("[Fallback] Failed to notify new owner %s: %v", newOwnerUsername, err)
+	}
+	return nil
+}
+
+// userExists proverava u users-service-u da li korisnik sa datim ID-em postoji.
+// Greška znači da postojanje nije moglo da se utvrdi.
+func (s *ProjectService) userExists(userID primitive.ObjectID) (bool, error) {
+	usersServiceURL := os.Getenv("USERS_SERVICE_URL")
+	if usersServiceURL == "" {
+		return false, fmt.Errorf("USERS_SERVICE_URL not set")
+	}
+	url := fmt.Sprintf("%s/api/users/member/id/%s", usersServiceURL, userID.Hex())
+
+	result, err := s.UsersBreaker.Execute(func() (interface{}, error) {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %v", err)
+		}
+		req.Header.Set("Role", "manager")
+
+		resp, err := s.HTTPClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to contact users-service: %v", err)
+		}
+		defer resp.Body.Close()
+
+		switch resp.StatusCode {
+		case http.StatusOK:
+			return true, nil
+		case http.StatusNotFound:
+			return false, nil
+		default:
+			return nil, fmt.Errorf("users-service returned status: %v", resp.Status)
+		}
+	})
+	if err != nil {
+		logging.Logger.Warnf("[Fallback] Could not check whether user %s exists: %v", userID.Hex(), err)
+		return false, fmt.Errorf("cannot verify project owner: %v", err)
+	}
+	return result.(bool), nil
+}
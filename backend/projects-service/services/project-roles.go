@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"trello-project/microservices/projects-service/logging"
+
+	"trello-project/backend/utils/projectroles"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ResolveProjectAccess vraća ulogu korisnika na projektu i da li je projekat arhiviran:
+// owner je menadžer koji je kreirao projekat, a članovi imaju ulogu sačuvanu uz člana.
+// Prazna uloga znači da korisnik nema pristup projektu.
+func (s *ProjectService) ResolveProjectAccess(ctx context.Context, projectID, username string) (projectroles.Access, error) {
+	project, err := s.GetProjectByID(projectID)
+	if err != nil {
+		return projectroles.Access{}, err
+	}
+	if project.DeletionSagaID != nil {
+		return projectroles.Access{}, projectroles.ErrProjectDeleting
+	}
+	access := projectroles.Access{Archived: project.ArchivedAt != nil}
+
+	for _, member := range project.Members {
+		if member.Username == username {
+			access.Role = projectroles.Effective(member.ProjectRole)
+			return access, nil
+		}
+	}
+
+	userID, err := s.getUserIDByUsername(username)
+	if err != nil {
+		return projectroles.Access{}, fmt.Errorf("failed to resolve user %s: %v", username, err)
+	}
+	if userID == project.ManagerID {
+		access.Role = projectroles.Owner
+	}
+	return access, nil
+}
+
+// SetMemberRole menja ulogu člana na projektu. Uloga owner se ne dodeljuje ovde jer
+// pripada menadžeru koji je vlasnik projekta.
+func (s *ProjectService) SetMemberRole(ctx context.Context, projectID, memberID, roleName string) error {
+	role, ok := projectroles.Parse(roleName)
+	if !ok || role == projectroles.Owner {
+		return fmt.Errorf("invalid project role: %s", roleName)
+	}
+	projectObjectID, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return fmt.Errorf("invalid project ID format")
+	}
+	memberObjectID, err := primitive.ObjectIDFromHex(memberID)
+	if err != nil {
+		return fmt.Errorf("invalid member ID format")
+	}
+
+	result, err := s.ProjectsCollection.UpdateOne(ctx,
+		bson.M{"_id": projectObjectID, "members._id": memberObjectID},
+		bson.M{"$set": bson.M{"members.$.project_role": string(role)}},
+	)
+	if err != nil {
+		logging.Logger.Errorf("Failed to set role of member %s in project %s: %v", memberID, projectID, err)
+		return fmt.Errorf("failed to set member role: %v", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("member not found in project")
+	}
+
+	logging.Logger.Infof("Member %s in project %s now has role '%s'", memberID, projectID, role)
+	return nil
+}
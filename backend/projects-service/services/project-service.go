@@ -7,7 +7,6 @@ import (
 	"errors"
 	"fmt"
 	"html"
-	"io"
 	"log"
 	"net/http"
 	"os"
@@ -16,35 +15,70 @@ import (
 	"trello-project/microservices/projects-service/logging"
 	"trello-project/microservices/projects-service/models"
 
+	"trello-project/backend/utils/events"
+	"trello-project/backend/utils/projectroles"
+
 	"github.com/sony/gobreaker"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type ProjectService struct {
-	ProjectsCollection   *mongo.Collection
-	HTTPClient           *http.Client
+	ProjectsCollection          *mongo.Collection
+	SprintsCollection           *mongo.Collection
+	InvitationsCollection       *mongo.Collection
+	DeletionSagasCollection     *mongo.Collection
+	CalendarFeedsCollection     *mongo.Collection
+	WebhooksCollection          *mongo.Collection
+	WebhookDeliveriesCollection *mongo.Collection
+	ActivitiesCollection        *mongo.Collection
+	// EventOutbox je nil kada NATS nije podešen; tada se domenski događaji ne objavljuju
+	EventOutbox *events.Outbox
+	HTTPClient  *http.Client
+	// WebhookClient šalje isporuke webhook-ova i ne dozvoljava veze ka internoj mreži
+	WebhookClient        *http.Client
 	TasksBreaker         *gobreaker.CircuitBreaker
 	UsersBreaker         *gobreaker.CircuitBreaker
 	NotificationsBreaker *gobreaker.CircuitBreaker
+	WorkflowBreaker      *gobreaker.CircuitBreaker
 }
 
 // NewProjectService initializes a new ProjectService with the necessary MongoDB collections.
 func NewProjectService(
 	projectsCollection *mongo.Collection,
+	sprintsCollection *mongo.Collection,
+	invitationsCollection *mongo.Collection,
+	deletionSagasCollection *mongo.Collection,
+	calendarFeedsCollection *mongo.Collection,
+	webhooksCollection *mongo.Collection,
+	webhookDeliveriesCollection *mongo.Collection,
+	activitiesCollection *mongo.Collection,
+	eventOutbox *events.Outbox,
 	httpClient *http.Client,
 	tasksBreaker *gobreaker.CircuitBreaker,
 	usersBreaker *gobreaker.CircuitBreaker,
 	notificationsBreaker *gobreaker.CircuitBreaker,
+	workflowBreaker *gobreaker.CircuitBreaker,
 
 ) *ProjectService {
 	return &ProjectService{
-		ProjectsCollection:   projectsCollection,
-		HTTPClient:           httpClient,
-		TasksBreaker:         tasksBreaker,
-		UsersBreaker:         usersBreaker,
-		NotificationsBreaker: notificationsBreaker,
+		ProjectsCollection:          projectsCollection,
+		SprintsCollection:           sprintsCollection,
+		InvitationsCollection:       invitationsCollection,
+		DeletionSagasCollection:     deletionSagasCollection,
+		CalendarFeedsCollection:     calendarFeedsCollection,
+		WebhooksCollection:          webhooksCollection,
+		WebhookDeliveriesCollection: webhookDeliveriesCollection,
+		ActivitiesCollection:        activitiesCollection,
+		EventOutbox:                 eventOutbox,
+		HTTPClient:                  httpClient,
+		WebhookClient:               NewWebhookHTTPClient(),
+		TasksBreaker:                tasksBreaker,
+		UsersBreaker:                usersBreaker,
+		NotificationsBreaker:        notificationsBreaker,
+		WorkflowBreaker:             workflowBreaker,
 	}
 }
 
@@ -85,13 +119,17 @@ func (s *ProjectService) CreateProject(name string, description string, expected
 		Tasks:           []primitive.ObjectID{},
 	}
 
-	result, err := s.ProjectsCollection.InsertOne(context.Background(), project)
+	err = s.inTransaction(context.Background(), func(ctx context.Context) error {
+		if _, err := s.ProjectsCollection.InsertOne(ctx, project); err != nil {
+			return err
+		}
+		return s.publishEvent(ctx, projectCreatedEvent(project))
+	})
 	if err != nil {
 		logging.Logger.Errorf("Failed to insert new project '%s' into database: %v", name, err)
 		return nil, fmt.Errorf("failed to create project: %v", err)
 	}
 
-	project.ID = result.InsertedID.(primitive.ObjectID)
 	logging.Logger.Infof("New project '%s' created successfully with ID: %s", project.Name, project.ID.Hex())
 	return project, nil
 }
@@ -172,7 +210,9 @@ func (s *ProjectService) AddMembersToProject(projectID primitive.ObjectID, usern
 
 		}
 
-		members = append(members, userData.(models.Member))
+		member := userData.(models.Member)
+		member.ProjectRole = string(projectroles.DefaultMemberRole)
+		members = append(members, member)
 	}
 
 	if len(members) == 0 {
@@ -190,7 +230,17 @@ func (s *ProjectService) AddMembersToProject(projectID primitive.ObjectID, usern
 
 	filter := bson.M{"_id": projectID}
 	update := bson.M{"$push": bson.M{"members": bson.M{"$each": members}}}
-	_, err = s.ProjectsCollection.UpdateOne(context.Background(), filter, update)
+	err = s.inTransaction(context.Background(), func(ctx context.Context) error {
+		if _, err := s.ProjectsCollection.UpdateOne(ctx, filter, update); err != nil {
+			return err
+		}
+		for _, member := range members {
+			if err := s.recordMemberActivity(ctx, project.ID, models.ActivityAddMember, member, fmt.Sprintf("Member %s added to project", member.Username)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 	if err != nil {
 		logging.Logger.Errorf("Error updating project members: %v", err)
 		return err
@@ -199,6 +249,10 @@ func (s *ProjectService) AddMembersToProject(projectID primitive.ObjectID, usern
 	log.Printf("%d new members were added to project '%s'.", len(members), project.Name)
 
 	for _, member := range members {
+		s.emitWebhookEventAsync(project.ID.Hex(), models.WebhookMemberAdded, map[string]interface{}{
+			"memberId": member.ID.Hex(),
+			"username": member.Username,
+		})
 		go func(m models.Member) {
 			message := fmt.Sprintf("You have been added to the project: %s", project.Name)
 			_, err := s.NotificationsBreaker.Execute(func() (interface{}, error) {
@@ -319,7 +373,9 @@ func (s *ProjectService) GetAllUsers() ([]models.Member, error) {
 }
 
 // RemoveMemberFromProject removes a member from a project if they are not assigned to an in-progress task.
-func (s *ProjectService) RemoveMemberFromProject(ctx context.Context, projectID, memberID string) error {
+// Uklanjanje koje bi projekat spustilo ispod MinMembers odbija se sa ErrBelowMinMembers,
+// osim ako je confirmed; tada se vlasnik projekta upozorava.
+func (s *ProjectService) RemoveMemberFromProject(ctx context.Context, projectID, memberID string, confirmed bool) error {
 	projectObjectID, err := primitive.ObjectIDFromHex(projectID)
 	if err != nil {
 		logging.Logger.Warnf("Invalid project ID format: %v", err)
@@ -332,66 +388,68 @@ func (s *ProjectService) RemoveMemberFromProject(ctx context.Context, projectID,
 		return fmt.Errorf("invalid member ID format")
 	}
 
-	taskServiceURL := os.Getenv("TASKS_SERVICE_URL")
-	if taskServiceURL == "" {
-		logging.Logger.Warnf("TASKS_SERVICE_URL is not set")
-		return fmt.Errorf("task service URL is not configured")
+	project, err := s.GetProjectByID(projectID)
+	if err != nil {
+		return err
 	}
 
-	checkURL := fmt.Sprintf("%s/api/tasks/has-active?projectId=%s&memberId=%s", taskServiceURL, projectID, memberID)
-
-	// Circuit Breaker za task servis
-	result, err := s.TasksBreaker.Execute(func() (interface{}, error) {
-		req, err := http.NewRequestWithContext(ctx, "GET", checkURL, nil)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create HTTP request: %v", err)
-		}
-
-		resp, err := s.HTTPClient.Do(req)
-		if err != nil {
-			return nil, fmt.Errorf("failed to connect to task service: %v", err)
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
-			return nil, fmt.Errorf("task service returned status %d: %s", resp.StatusCode, string(body))
-		}
-
-		var r struct {
-			HasActiveTasks bool `json:"hasActiveTasks"`
-		}
-		if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
-			return nil, fmt.Errorf("failed to decode task service response: %v", err)
-		}
-
-		return r.HasActiveTasks, nil
-	})
+	hasActiveTasks, err := s.memberHasActiveTasks(ctx, projectID, memberID)
 	if err != nil {
 		logging.Logger.Warnf("Circuit breaker error or fallback triggered: %v", err)
 		return fmt.Errorf("could not verify task assignment: %v", err)
 	}
 
-	if result.(bool) {
+	if hasActiveTasks {
 		logging.Logger.Warnf("Cannot remove member assigned to an active task")
 		return fmt.Errorf("cannot remove member assigned to an active task")
 	}
 
-	// Ako nema aktivnih zadataka, ukloni člana iz projekta
-	filter := bson.M{"_id": projectObjectID}
+	// Ako nema aktivnih zadataka, ukloni člana iz projekta. Minimum je deo upita, pa
+	// istovremena uklanjanja ne mogu neprimećeno da ga probiju.
+	filter := bson.M{"_id": projectObjectID, "members._id": memberObjectID}
+	if !confirmed && project.MinMembers > 0 {
+		filter[fmt.Sprintf("members.%d", project.MinMembers)] = bson.M{"$exists": true}
+	}
 	update := bson.M{"$pull": bson.M{"members": bson.M{"_id": memberObjectID}}}
 
-	resultUpdate, err := s.ProjectsCollection.UpdateOne(ctx, filter, update)
+	removed := true
+	err = s.inTransaction(ctx, func(ctx context.Context) error {
+		resultUpdate, err := s.ProjectsCollection.UpdateOne(ctx, filter, update)
+		if err != nil {
+			return err
+		}
+		if removed = resultUpdate.ModifiedCount > 0; !removed {
+			return nil
+		}
+		for _, member := range project.Members {
+			if member.ID == memberObjectID {
+				return s.recordMemberActivity(ctx, project.ID, models.ActivityRemoveMember, member, fmt.Sprintf("Member %s removed from project", member.Username))
+			}
+		}
+		return nil
+	})
 	if err != nil {
 		logging.Logger.Errorf("Failed to remove member from project: %v", err)
 		return fmt.Errorf("failed to remove member from project")
 	}
 
-	if resultUpdate.ModifiedCount == 0 {
+	if !removed {
+		if !confirmed && project.MinMembers > 0 {
+			for _, member := range project.Members {
+				if member.ID == memberObjectID {
+					logging.Logger.Warnf("Refusing to remove member %s: project %s would drop below %d members", memberID, projectID, project.MinMembers)
+					return ErrBelowMinMembers
+				}
+			}
+		}
 		logging.Logger.Warnf("Member not found in project or already removed")
 		return fmt.Errorf("member not found in project or already removed")
 	}
 
+	if remaining := len(project.Members) - 1; remaining < project.MinMembers {
+		go s.warnUnderstaffed(project, remaining)
+	}
+
 	logging.Logger.Infof("Member successfully removed from project.")
 	// ✅ Dohvatanje Member objekta iz user servisa
 	usersServiceURL := os.Getenv("USERS_SERVICE_URL")
@@ -436,9 +494,16 @@ func (s *ProjectService) RemoveMemberFromProject(ctx context.Context, projectID,
 }
 
 // GetAllProjects - preuzima sve projekte iz kolekcije
-func (s *ProjectService) GetAllProjects() ([]models.Project, error) {
+// GetAllProjects vraća projekte; archived bira da li se arhivirani projekti izostavljaju,
+// uključuju ili vraćaju samo oni.
+func (s *ProjectService) GetAllProjects(archived string) ([]models.Project, error) {
+	filter, err := withArchivedFilter(bson.M{}, archived)
+	if err != nil {
+		return nil, err
+	}
+
 	var projects []models.Project
-	cursor, err := s.ProjectsCollection.Find(context.Background(), bson.M{})
+	cursor, err := s.ProjectsCollection.Find(context.Background(), filter)
 	if err != nil {
 		logging.Logger.Errorf("Unsuccessful procurement of projects: %v", err)
 		return nil, fmt.Errorf("unsuccessful procurement of projects: %v", err)
@@ -644,8 +709,11 @@ func (s *ProjectService) getUserRoleByUsername(username string) (string, error)
 	return result.(string), nil
 }
 
-func (s *ProjectService) GetProjectsByUsername(username string) ([]models.Project, error) {
+func (s *ProjectService) GetProjectsByUsername(username, archived string) ([]models.Project, error) {
 	var projects []models.Project
+	if _, err := withArchivedFilter(bson.M{}, archived); err != nil {
+		return nil, err
+	}
 
 	// ✅ Pokušaj da dobaviš userID, u slučaju greške vrati prazan niz
 	userID, err := s.getUserIDByUsername(username)
@@ -668,6 +736,7 @@ func (s *ProjectService) GetProjectsByUsername(username string) ([]models.Projec
 	} else {
 		filter = bson.M{"members.username": username}
 	}
+	filter, _ = withArchivedFilter(filter, archived)
 
 	logging.Logger.Infof("Executing MongoDB query with filter: %v", filter)
 
@@ -699,82 +768,6 @@ func (s *ProjectService) GetProjectsByUsername(username string) ([]models.Projec
 	return projects, nil
 }
 
-func (s *ProjectService) DeleteProjectAndTasks(ctx context.Context, projectID string, r *http.Request) error {
-	// 1. Validacija i konverzija projectID
-	projectObjectID, err := primitive.ObjectIDFromHex(projectID)
-	if err != nil {
-		logging.Logger.Warnf("Invalid project ID format: %v", projectID)
-		return fmt.Errorf("invalid project ID format")
-	}
-
-	// 2. Provera postojanja projekta u bazi
-	filter := bson.M{"_id": projectObjectID}
-	var project bson.M
-	err = s.ProjectsCollection.FindOne(ctx, filter).Decode(&project)
-	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			logging.Logger.Warnf("Project not found: %v", projectID)
-			return fmt.Errorf("project not found")
-		}
-		logging.Logger.Errorf("Failed to fetch project: %v", err)
-		return fmt.Errorf("failed to fetch project: %v", err)
-	}
-
-	// 3. Priprema URL-a za tasks-service
-	taskServiceURL := os.Getenv("TASKS_SERVICE_URL")
-	if taskServiceURL == "" {
-		logging.Logger.Errorf("TASKS_SERVICE_URL not set")
-		return fmt.Errorf("TASKS_SERVICE_URL not set")
-	}
-	url := fmt.Sprintf("%s/api/tasks/project/%s", taskServiceURL, projectID)
-
-	// 4. Circuit breaker za tasks-service DELETE
-	_, err = s.TasksBreaker.Execute(func() (interface{}, error) {
-		// Kreiranje HTTP DELETE zahteva
-		req, err := http.NewRequest(http.MethodDelete, url, nil)
-		if err != nil {
-			logging.Logger.Errorf("Failed to create request to tasks-service: %v", err)
-			return nil, fmt.Errorf("failed to create request: %v", err)
-		}
-
-		// Prosleđivanje zaglavlja iz originalnog requesta
-		req.Header.Set("Authorization", r.Header.Get("Authorization"))
-		req.Header.Set("Role", r.Header.Get("Role"))
-
-		// Slanje HTTP zahteva
-		resp, err := s.HTTPClient.Do(req)
-		if err != nil {
-			logging.Logger.Errorf("Failed to contact tasks-service: %v", err)
-			return nil, fmt.Errorf("failed to contact tasks-service: %v", err)
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode != http.StatusOK {
-			logging.Logger.Errorf("Tasks-service returned non-OK status: %v", resp.Status)
-			return nil, fmt.Errorf("task service returned error: %v", resp.Status)
-		}
-
-		return nil, nil
-	})
-
-	// 5. Fallback ako breaker odbije ili task servis padne
-	if err != nil {
-		logging.Logger.Warnf("[Fallback] Tasks were not deleted due to error: %v", err)
-		// Možemo odlučiti da prekinemo ceo proces ili samo logujemo
-		// return fmt.Errorf("failed to delete tasks: %v", err)
-	}
-
-	// 6. Brisanje projekta iz baze
-	_, err = s.ProjectsCollection.DeleteOne(ctx, filter)
-	if err != nil {
-		logging.Logger.Errorf("Failed to delete project: %v", err)
-		return fmt.Errorf("failed to delete project: %v", err)
-	}
-
-	logging.Logger.Infof("Successfully deleted project and (if possible) related tasks for ID: %s", projectID)
-	return nil
-}
-
 func (s *ProjectService) GetAllMembers() ([]models.Member, error) {
 	// 1. Učitaj URL users-servisa iz .env fajla
 	usersServiceURL := os.Getenv("USERS_SERVICE_URL")
@@ -840,8 +833,8 @@ func (s *ProjectService) AddTaskToProject(projectID string, taskID string) error
 
 	logging.Logger.Infof("Received request to add task %s to project %s", taskID, projectID)
 
-	// Ažuriranje projekta dodavanjem ID-ja zadatka
-	filter := bson.M{"_id": projectObjectID}
+	// Ažuriranje projekta dodavanjem ID-ja zadatka; arhiviran projekat ne prima nove zadatke
+	filter := bson.M{"_id": projectObjectID, "archived_at": bson.M{"$exists": false}}
 	update := bson.M{"$push": bson.M{"taskIDs": taskObjectID}}
 
 	logging.Logger.Debugf("MongoDB filter: %+v", filter)
@@ -854,6 +847,10 @@ func (s *ProjectService) AddTaskToProject(projectID string, taskID string) error
 	}
 
 	if result.ModifiedCount == 0 {
+		if project, err := s.GetProjectByID(projectID); err == nil && project.ArchivedAt != nil {
+			logging.Logger.Warnf("Rejected adding task %s to archived project %s", taskID, projectID)
+			return projectroles.ErrProjectArchived
+		}
 		logging.Logger.Warnf("No project was updated. Possible that project ID %s does not exist.", projectID)
 		return fmt.Errorf("no project found with ID %s", projectID)
 	}
@@ -862,83 +859,56 @@ func (s *ProjectService) AddTaskToProject(projectID string, taskID string) error
 	return nil
 }
 
-func (s *ProjectService) RemoveUserFromProjects(userID string, role string, authToken string) error {
-	tasksServiceURL := os.Getenv("TASKS_SERVICE_URL")
-	if tasksServiceURL == "" {
-		logging.Logger.Errorf("TASKS_SERVICE_URL not set")
-		return fmt.Errorf("TASKS_SERVICE_URL not set")
+// RemoveTaskFromProject uklanja ID zadatka iz liste zadataka projekta.
+func (s *ProjectService) RemoveTaskFromProject(projectID string, taskID string) error {
+	projectObjectID, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		logging.Logger.Errorf("Invalid project ID format: %v", err)
+		return fmt.Errorf("invalid project ID format: %v", err)
 	}
 
-	if role == "manager" {
-		projectFilter := bson.M{"manager_id": userID}
-		cursor, err := s.ProjectsCollection.Find(context.Background(), projectFilter)
-		if err != nil {
-			logging.Logger.Errorf("Error fetching projects for manager %s: %v", userID, err)
-			return fmt.Errorf("failed to fetch projects")
-		}
-		defer cursor.Close(context.Background())
-
-		for cursor.Next(context.Background()) {
-			var project models.Project
-			if err := cursor.Decode(&project); err != nil {
-				logging.Logger.Errorf("Error decoding project: %v", err)
-				continue
-			}
-
-			url := fmt.Sprintf("%s/api/tasks/project/%s/has-unfinished", tasksServiceURL, project.ID.Hex())
-			result, err := s.TasksBreaker.Execute(func() (interface{}, error) {
-				req, err := http.NewRequest("GET", url, nil)
-				if err != nil {
-					return nil, fmt.Errorf("failed to create request: %v", err)
-				}
-				req.Header.Set("Authorization", "Bearer "+authToken)
-
-				resp, err := s.HTTPClient.Do(req)
-				if err != nil {
-					return nil, fmt.Errorf("failed to contact task service: %v", err)
-				}
-				defer resp.Body.Close()
-
-				if resp.StatusCode != http.StatusOK {
-					return nil, fmt.Errorf("task service returned non-OK status for project %s: %s", project.ID.Hex(), resp.Status)
-				}
-
-				var r struct {
-					HasUnfinishedTasks bool `json:"hasUnfinishedTasks"`
-				}
-				if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
-					return nil, fmt.Errorf("failed to decode task service response: %v", err)
-				}
+	taskObjectID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		logging.Logger.Errorf("Invalid task ID format: %v", err)
+		return fmt.Errorf("invalid task ID format: %v", err)
+	}
 
-				return r, nil
-			})
+	result, err := s.ProjectsCollection.UpdateOne(context.Background(),
+		bson.M{"_id": projectObjectID},
+		bson.M{"$pull": bson.M{"taskIDs": taskObjectID}},
+	)
+	if err != nil {
+		logging.Logger.Errorf("Failed to remove task %s from project %s: %v", taskID, projectID, err)
+		return fmt.Errorf("failed to remove task from project: %v", err)
+	}
 
-			if err != nil {
-				logging.Logger.Warnf("Circuit breaker error or fallback triggered for task service: %v", err)
-				// Fallback ponašanje: ne dozvoli uklanjanje ako ne može da proveri zadatke
-				return fmt.Errorf("failed to check tasks for project %s", project.ID.Hex())
-			}
+	if result.MatchedCount == 0 {
+		logging.Logger.Warnf("No project found with ID %s when removing task %s", projectID, taskID)
+		return fmt.Errorf("no project found with ID %s", projectID)
+	}
 
-			r, ok := result.(struct{ HasUnfinishedTasks bool })
-			if !ok {
-				logging.Logger.Errorf("Unexpected result type from circuit breaker: %T", result)
-				return fmt.Errorf("unexpected result type from circuit breaker")
-			}
-			if r.HasUnfinishedTasks {
-				logging.Logger.Warnf("Cannot remove manager %s: unfinished tasks in project %s", userID, project.ID.Hex())
-				return fmt.Errorf("manager cannot be removed from project %s due to unfinished tasks", project.ID.Hex())
-			}
+	logging.Logger.Infof("Task %s successfully removed from project %s", taskID, projectID)
+	return nil
+}
 
+func (s *ProjectService) RemoveUserFromProjects(userID string, role string, authToken string) error {
+	if role == "manager" {
+		// Menadžer koji je još vlasnik projekata ne može biti uklonjen; projekti bi ostali
+		// bez vlasnika. Vlasništvo se prvo prenosi (TransferOwnership).
+		managerID, err := primitive.ObjectIDFromHex(userID)
+		if err != nil {
+			return fmt.Errorf("invalid user ID format")
 		}
-
-		update := bson.M{"$unset": bson.M{"manager_id": ""}}
-		_, err = s.ProjectsCollection.UpdateMany(context.Background(), projectFilter, update)
+		owned, err := s.CountOwnedProjects(context.Background(), managerID)
 		if err != nil {
-			logging.Logger.Errorf("Failed to remove manager %s from projects: %v", userID, err)
-			return fmt.Errorf("failed to update projects")
+			logging.Logger.Errorf("Error counting projects of manager %s: %v", userID, err)
+			return fmt.Errorf("failed to fetch projects")
 		}
-		logging.Logger.Infof("Manager %s successfully removed from all managed projects.", userID)
-
+		if owned > 0 {
+			logging.Logger.Warnf("Cannot remove manager %s: still owns %d project(s)", userID, owned)
+			return fmt.Errorf("%w: manager still owns %d project(s)", ErrOwnedProjectsRemain, owned)
+		}
+		logging.Logger.Infof("Manager %s owns no projects; nothing to remove.", userID)
 	}
 
 	if role == "member" {
@@ -980,7 +950,25 @@ func (s *ProjectService) RemoveUserFromProjects(userID string, role string, auth
 		filter := bson.M{"members._id": objectID}
 		update := bson.M{"$pull": bson.M{"members": bson.M{"_id": objectID}}}
 
-		_, err = s.ProjectsCollection.UpdateMany(context.Background(), filter, update)
+		// Projekti se čitaju pre uklanjanja, da bi se uklanjanje zabeležilo u feed-u svakog od njih
+		var affected []models.Project
+		if cursor, err := s.ProjectsCollection.Find(context.Background(), filter, options.Find().SetProjection(bson.M{"_id": 1})); err == nil {
+			if err := cursor.All(context.Background(), &affected); err != nil {
+				logging.Logger.Warnf("Failed to decode projects of user %s: %v", userID, err)
+			}
+		}
+
+		err = s.inTransaction(context.Background(), func(ctx context.Context) error {
+			if _, err := s.ProjectsCollection.UpdateMany(ctx, filter, update); err != nil {
+				return err
+			}
+			for _, project := range affected {
+				if err := s.recordMemberActivity(ctx, project.ID, models.ActivityRemoveMember, member, fmt.Sprintf("Member %s removed from project (account deleted)", member.Username)); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
 		if err != nil {
 			logging.Logger.Errorf("Failed to remove user %s from projects: %v", userID, err)
 			return fmt.Errorf("failed to update projects")
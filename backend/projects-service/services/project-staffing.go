@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"trello-project/backend/utils/projectroles"
+	"trello-project/microservices/projects-service/logging"
+	"trello-project/microservices/projects-service/models"
+)
+
+// ErrBelowMinMembers vraća se kada bi uklanjanje člana spustilo projekat ispod minimalnog
+// broja članova, a uklanjanje nije potvrđeno.
+var ErrBelowMinMembers = errors.New("removal would leave the project below its minimum number of members")
+
+// GetStaffingReport poredi broj članova projekta sa MinMembers i MaxMembers i za svakog
+// člana proverava u tasks-service-u da li ima aktivnih zadataka. Nedostupan tasks-service
+// ne obara izveštaj; opterećenje tih članova ostaje nepoznato, uz upozorenje.
+func (s *ProjectService) GetStaffingReport(ctx context.Context, projectID string) (*models.StaffingReport, error) {
+	project, err := s.GetProjectByID(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.StaffingReport{
+		ProjectID:   project.ID.Hex(),
+		ProjectName: project.Name,
+		MemberCount: len(project.Members),
+		MinMembers:  project.MinMembers,
+		MaxMembers:  project.MaxMembers,
+		Members:     make([]models.MemberLoad, 0, len(project.Members)),
+	}
+
+	switch {
+	case report.MemberCount < project.MinMembers:
+		report.Status = models.StaffingUnderstaffed
+		report.Shortfall = project.MinMembers - report.MemberCount
+		report.Warnings = append(report.Warnings, fmt.Sprintf("project needs %d more member(s) to reach its minimum of %d", report.Shortfall, project.MinMembers))
+	case project.MaxMembers > 0 && report.MemberCount >= project.MaxMembers:
+		report.Status = models.StaffingFull
+	default:
+		report.Status = models.StaffingHealthy
+	}
+	if project.MaxMembers > report.MemberCount {
+		report.OpenSlots = project.MaxMembers - report.MemberCount
+	}
+
+	unknown := 0
+	for _, member := range project.Members {
+		load := models.MemberLoad{
+			MemberID:    member.ID.Hex(),
+			Username:    member.Username,
+			ProjectRole: string(projectroles.Effective(member.ProjectRole)),
+		}
+		active, err := s.memberHasActiveTasks(ctx, projectID, member.ID.Hex())
+		if err != nil {
+			logging.Logger.Warnf("[Fallback] Could not check active tasks of %s on project %s: %v", member.Username, projectID, err)
+			unknown++
+		} else {
+			load.HasActiveTasks = &active
+			if active {
+				report.ActiveCount++
+			} else {
+				report.IdleCount++
+			}
+		}
+		report.Members = append(report.Members, load)
+	}
+	if unknown > 0 {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("task load unknown for %d member(s): tasks service unavailable", unknown))
+	}
+
+	return report, nil
+}
+
+// memberHasActiveTasks pita tasks-service da li je član dodeljen zadatku u toku.
+func (s *ProjectService) memberHasActiveTasks(ctx context.Context, projectID, memberID string) (bool, error) {
+	query := url.Values{}
+	query.Set("projectId", projectID)
+	query.Set("memberId", memberID)
+
+	var result struct {
+		HasActiveTasks bool `json:"hasActiveTasks"`
+	}
+	if err := s.callService(s.TasksBreaker, http.MethodGet, "TASKS_SERVICE_URL", "/api/tasks/has-active?"+query.Encode(), nil, &result); err != nil {
+		return false, err
+	}
+	return result.HasActiveTasks, nil
+}
+
+// warnUnderstaffed obaveštava vlasnika projekta da je projekat pao ispod minimalnog broja članova.
+func (s *ProjectService) warnUnderstaffed(project *models.Project, remaining int) {
+	logging.Logger.Warnf("Project %s is below its minimum of %d members (%d remaining)", project.ID.Hex(), project.MinMembers, remaining)
+
+	var owner models.Member
+	if err := s.callService(s.UsersBreaker, http.MethodGet, "USERS_SERVICE_URL", "/api/users/member/id/"+project.ManagerID.Hex(), nil, &owner); err != nil {
+		logging.Logger.Warnf("[Fallback] Could not fetch owner of project %s to warn about staffing: %v", project.ID.Hex(), err)
+		return
+	}
+	message := fmt.Sprintf("Project %s has %d member(s), below its minimum of %d.", project.Name, remaining, project.MinMembers)
+	if _, err := s.NotificationsBreaker.Execute(func() (interface{}, error) {
+		return nil, s.sendNotification(owner, message)
+	}); err != nil {
+		logging.Logger.Warnf("[Fallback] Failed to warn owner of project %s about staffing: %v", project.ID.Hex(), err)
+	}
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"trello-project/microservices/projects-service/logging"
+	"trello-project/microservices/projects-service/models"
+
+	"trello-project/backend/utils/events"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// errMaxMembersBelowCurrent vraća se kada su članovi dodati između provere i izmene.
+var errMaxMembersBelowCurrent = errors.New("maxMembers cannot be lower than the current number of members")
+
+// UpdateProject menja osnovne podatke projekta. Ime mora ostati jedinstveno, a novi
+// MaxMembers ne sme biti manji od trenutnog broja članova. Ako se rok pomeri, članovi
+// projekta dobijaju obaveštenje.
+func (s *ProjectService) UpdateProject(ctx context.Context, projectID string, update models.ProjectUpdate) (*models.Project, error) {
+	project, err := s.GetProjectByID(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	set := bson.M{}
+	if update.Name != nil {
+		name := strings.TrimSpace(*update.Name)
+		if name == "" {
+			return nil, fmt.Errorf("project name must not be empty")
+		}
+		name = html.EscapeString(name)
+		if name != project.Name {
+			count, err := s.ProjectsCollection.CountDocuments(ctx, bson.M{"name": name, "_id": bson.M{"$ne": project.ID}})
+			if err != nil {
+				return nil, fmt.Errorf("database error: %v", err)
+			}
+			if count > 0 {
+				return nil, fmt.Errorf("project with the same name already exists")
+			}
+			set["name"] = name
+		}
+	}
+	if update.Description != nil {
+		set["description"] = html.EscapeString(*update.Description)
+	}
+
+	deadlineMoved := false
+	if update.ExpectedEndDate != nil && !update.ExpectedEndDate.Equal(project.ExpectedEndDate) {
+		if update.ExpectedEndDate.Before(time.Now()) {
+			return nil, fmt.Errorf("expected end date must be in the future")
+		}
+		set["expected_end_date"] = *update.ExpectedEndDate
+		deadlineMoved = true
+	}
+
+	minMembers, maxMembers := project.MinMembers, project.MaxMembers
+	if update.MinMembers != nil {
+		minMembers = *update.MinMembers
+	}
+	if update.MaxMembers != nil {
+		maxMembers = *update.MaxMembers
+	}
+	if minMembers < 1 || maxMembers < minMembers {
+		return nil, fmt.Errorf("invalid member constraints: minMembers=%d, maxMembers=%d", minMembers, maxMembers)
+	}
+	if maxMembers < len(project.Members) {
+		return nil, fmt.Errorf("maxMembers cannot be lower than the current number of members (%d)", len(project.Members))
+	}
+	if minMembers != project.MinMembers {
+		set["min_members"] = minMembers
+	}
+	if maxMembers != project.MaxMembers {
+		set["max_members"] = maxMembers
+	}
+
+	if len(set) == 0 {
+		return project, nil
+	}
+
+	// Broj članova je uslov i u samom upitu, da istovremeno dodavanje članova ne bi
+	// prešlo novi limit
+	filter := bson.M{"_id": project.ID, fmt.Sprintf("members.%d", maxMembers): bson.M{"$exists": false}}
+	var updated models.Project
+	err = s.inTransaction(ctx, func(ctx context.Context) error {
+		result, err := s.ProjectsCollection.UpdateOne(ctx, filter, bson.M{"$set": set})
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return errMaxMembersBelowCurrent
+		}
+		if err := s.ProjectsCollection.FindOne(ctx, bson.M{"_id": project.ID}).Decode(&updated); err != nil {
+			return err
+		}
+		expectedEndDate := updated.ExpectedEndDate
+		return s.publishEvent(ctx, events.Event{
+			Type:            events.ProjectUpdated,
+			ProjectID:       updated.ID.Hex(),
+			ExpectedEndDate: &expectedEndDate,
+		})
+	})
+	if err != nil {
+		if errors.Is(err, errMaxMembersBelowCurrent) {
+			return nil, err
+		}
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, fmt.Errorf("project with the same name already exists")
+		}
+		logging.Logger.Errorf("Failed to update project %s: %v", projectID, err)
+		return nil, fmt.Errorf("failed to update project: %v", err)
+	}
+
+	if deadlineMoved {
+		message := fmt.Sprintf("The deadline of project %s has moved from %s to %s",
+			updated.Name, project.ExpectedEndDate.Format("2006-01-02"), updated.ExpectedEndDate.Format("2006-01-02"))
+		for _, member := range updated.Members {
+			go func(m models.Member) {
+				_, err := s.NotificationsBreaker.Execute(func() (interface{}, error) {
+					return nil, s.sendNotification(m, message)
+				})
+				if err != nil {
+					logging.Logger.Warnf("Failed to notify %s about the new deadline: %v", m.Username, err)
+				}
+			}(member)
+		}
+	}
+
+	logging.Logger.Infof("Project %s updated (%d fields changed)", projectID, len(set))
+	return &updated, nil
+}
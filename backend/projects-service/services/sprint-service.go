@@ -0,0 +1,273 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"trello-project/microservices/projects-service/logging"
+	"trello-project/microservices/projects-service/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// velocityWindow je broj poslednjih zatvorenih sprintova iz kojih se računa prosečna brzina.
+const velocityWindow = 3
+
+// CreateSprint kreira planirani sprint u projektu.
+func (s *ProjectService) CreateSprint(ctx context.Context, projectID, name, goal string, startDate, endDate time.Time) (*models.Sprint, error) {
+	project, err := s.GetProjectByID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("sprint name must not be empty")
+	}
+	if startDate.IsZero() || endDate.IsZero() || !endDate.After(startDate) {
+		return nil, fmt.Errorf("sprint end date must be after start date")
+	}
+
+	sprint := &models.Sprint{
+		ID:        primitive.NewObjectID(),
+		ProjectID: project.ID,
+		Name:      html.EscapeString(name),
+		Goal:      html.EscapeString(goal),
+		StartDate: startDate.UTC(),
+		EndDate:   endDate.UTC(),
+		Status:    models.SprintPlanned,
+	}
+	if _, err := s.SprintsCollection.InsertOne(ctx, sprint); err != nil {
+		logging.Logger.Errorf("Failed to create sprint for project %s: %v", projectID, err)
+		return nil, fmt.Errorf("failed to create sprint: %v", err)
+	}
+
+	logging.Logger.Infof("Sprint %s ('%s') created in project %s", sprint.ID.Hex(), sprint.Name, projectID)
+	return sprint, nil
+}
+
+// ListSprints vraća sprintove projekta po datumu početka.
+func (s *ProjectService) ListSprints(ctx context.Context, projectID string) ([]models.Sprint, error) {
+	projectObjectID, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid project ID format")
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "start_date", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := s.SprintsCollection.Find(ctx, bson.M{"project_id": projectObjectID}, opts)
+	if err != nil {
+		logging.Logger.Errorf("Failed to fetch sprints for project %s: %v", projectID, err)
+		return nil, fmt.Errorf("failed to fetch sprints: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	sprints := []models.Sprint{}
+	if err := cursor.All(ctx, &sprints); err != nil {
+		return nil, fmt.Errorf("failed to decode sprints: %v", err)
+	}
+	return sprints, nil
+}
+
+// GetSprint vraća sprint ako pripada projektu.
+func (s *ProjectService) GetSprint(ctx context.Context, projectID, sprintID string) (*models.Sprint, error) {
+	projectObjectID, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid project ID format")
+	}
+	sprintObjectID, err := primitive.ObjectIDFromHex(sprintID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sprint ID format")
+	}
+
+	var sprint models.Sprint
+	err = s.SprintsCollection.FindOne(ctx, bson.M{"_id": sprintObjectID, "project_id": projectObjectID}).Decode(&sprint)
+	if err == mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("sprint not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch sprint: %v", err)
+	}
+	return &sprint, nil
+}
+
+// StartSprint pokreće planirani sprint. Ako projekat već ima aktivan sprint, on se
+// prvo zatvara, a njegovi nezavršeni zadaci prelaze u sprint koji se pokreće.
+func (s *ProjectService) StartSprint(ctx context.Context, projectID, sprintID string) (*models.Sprint, error) {
+	sprint, err := s.GetSprint(ctx, projectID, sprintID)
+	if err != nil {
+		return nil, err
+	}
+	if sprint.Status != models.SprintPlanned {
+		return nil, fmt.Errorf("only a planned sprint can be started")
+	}
+
+	var active models.Sprint
+	err = s.SprintsCollection.FindOne(ctx, bson.M{"project_id": sprint.ProjectID, "status": models.SprintActive}).Decode(&active)
+	if err == nil {
+		if _, err := s.closeSprint(ctx, &active, sprint); err != nil {
+			return nil, fmt.Errorf("failed to close active sprint '%s': %v", active.Name, err)
+		}
+	} else if err != mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("failed to fetch active sprint: %v", err)
+	}
+
+	now := time.Now().UTC()
+	_, err = s.SprintsCollection.UpdateOne(ctx,
+		bson.M{"_id": sprint.ID, "status": models.SprintPlanned},
+		bson.M{"$set": bson.M{"status": models.SprintActive, "started_at": now}},
+	)
+	if err != nil {
+		logging.Logger.Errorf("Failed to start sprint %s: %v", sprintID, err)
+		return nil, fmt.Errorf("failed to start sprint: %v", err)
+	}
+
+	logging.Logger.Infof("Sprint %s started in project %s", sprintID, projectID)
+	return s.GetSprint(ctx, projectID, sprintID)
+}
+
+// CloseSprint zatvara aktivan sprint. Nezavršeni zadaci prelaze u sledeći planirani
+// sprint, a ako on ne postoji, vraćaju se u backlog projekta.
+func (s *ProjectService) CloseSprint(ctx context.Context, projectID, sprintID string) (*models.Sprint, error) {
+	sprint, err := s.GetSprint(ctx, projectID, sprintID)
+	if err != nil {
+		return nil, err
+	}
+	if sprint.Status != models.SprintActive {
+		return nil, fmt.Errorf("only an active sprint can be closed")
+	}
+
+	var next *models.Sprint
+	var planned models.Sprint
+	opts := options.FindOne().SetSort(bson.D{{Key: "start_date", Value: 1}, {Key: "_id", Value: 1}})
+	err = s.SprintsCollection.FindOne(ctx, bson.M{"project_id": sprint.ProjectID, "status": models.SprintPlanned}, opts).Decode(&planned)
+	if err == nil {
+		next = &planned
+	} else if err != mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("failed to fetch next sprint: %v", err)
+	}
+
+	return s.closeSprint(ctx, sprint, next)
+}
+
+func (s *ProjectService) closeSprint(ctx context.Context, sprint *models.Sprint, next *models.Sprint) (*models.Sprint, error) {
+	targetSprintID := ""
+	if next != nil {
+		targetSprintID = next.ID.Hex()
+	}
+
+	summary, err := s.rolloverSprintTasks(sprint.ID.Hex(), targetSprintID)
+	if err != nil {
+		logging.Logger.Errorf("Failed to roll over tasks of sprint %s: %v", sprint.ID.Hex(), err)
+		return nil, fmt.Errorf("failed to roll over sprint tasks: %v", err)
+	}
+
+	now := time.Now().UTC()
+	set := bson.M{
+		"status":            models.SprintClosed,
+		"closed_at":         now,
+		"committed_points":  summary.TotalPoints,
+		"completed_points":  summary.CompletedPoints,
+		"completed_tasks":   summary.CompletedTasks,
+		"rolled_over_tasks": summary.RolledOver,
+	}
+	if next != nil {
+		set["rolled_over_to"] = next.ID
+	}
+	if _, err := s.SprintsCollection.UpdateOne(ctx, bson.M{"_id": sprint.ID}, bson.M{"$set": set}); err != nil {
+		logging.Logger.Errorf("Failed to close sprint %s: %v", sprint.ID.Hex(), err)
+		return nil, fmt.Errorf("failed to close sprint: %v", err)
+	}
+
+	logging.Logger.Infof("Sprint %s closed: %d/%d points completed, %d tasks rolled over to '%s'",
+		sprint.ID.Hex(), summary.CompletedPoints, summary.TotalPoints, summary.RolledOver, targetSprintID)
+	return s.GetSprint(ctx, sprint.ProjectID.Hex(), sprint.ID.Hex())
+}
+
+// GetVelocity vraća ostvarene poene po zatvorenom sprintu i prosečnu brzinu.
+func (s *ProjectService) GetVelocity(ctx context.Context, projectID string) (*models.VelocityReport, error) {
+	sprints, err := s.ListSprints(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.VelocityReport{
+		ProjectID:      projectID,
+		Sprints:        []models.SprintVelocity{},
+		VelocityWindow: velocityWindow,
+	}
+	for _, sprint := range sprints {
+		if sprint.Status != models.SprintClosed {
+			continue
+		}
+		report.Sprints = append(report.Sprints, models.SprintVelocity{
+			SprintID:        sprint.ID.Hex(),
+			Name:            sprint.Name,
+			EndDate:         sprint.EndDate,
+			CommittedPoints: sprint.CommittedPoints,
+			CompletedPoints: sprint.CompletedPoints,
+		})
+	}
+
+	recent := report.Sprints
+	if len(recent) > velocityWindow {
+		recent = recent[len(recent)-velocityWindow:]
+	}
+	if len(recent) > 0 {
+		total := 0
+		for _, sprint := range recent {
+			total += sprint.CompletedPoints
+		}
+		report.AverageVelocity = float64(total) / float64(len(recent))
+	}
+	return report, nil
+}
+
+// rolloverSprintTasks traži od tasks-service-a da prebaci nezavršene zadatke sprinta u
+// targetSprintID (prazan string znači backlog) i vraća stanje sprinta pre prebacivanja.
+func (s *ProjectService) rolloverSprintTasks(sprintID, targetSprintID string) (*models.SprintSummary, error) {
+	taskServiceURL := os.Getenv("TASKS_SERVICE_URL")
+	if taskServiceURL == "" {
+		return nil, fmt.Errorf("TASKS_SERVICE_URL not set")
+	}
+
+	body, _ := json.Marshal(map[string]string{"targetSprintId": targetSprintID})
+	url := fmt.Sprintf("%s/api/sprints/%s/rollover", strings.TrimRight(taskServiceURL, "/"), sprintID)
+
+	result, err := s.TasksBreaker.Execute(func() (interface{}, error) {
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Role", "manager")
+		resp, err := s.HTTPClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			respBody, _ := io.ReadAll(resp.Body)
+			return nil, fmt.Errorf("tasks-service error: %s", string(respBody))
+		}
+
+		var summary models.SprintSummary
+		if err := json.NewDecoder(resp.Body).Decode(&summary); err != nil {
+			return nil, fmt.Errorf("failed to decode sprint summary: %v", err)
+		}
+		return &summary, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*models.SprintSummary), nil
+}
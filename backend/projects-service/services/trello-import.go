@@ -0,0 +1,361 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"trello-project/microservices/projects-service/logging"
+	"trello-project/microservices/projects-service/models"
+)
+
+// importChunkSize je broj zadataka po jednom pozivu tasks-service-a; tasks-service za
+// svaki zadatak zove i projects-service i workflow-service, pa se zahtevi drže malim
+// da bi stali u timeout HTTP klijenta.
+const importChunkSize = 10
+
+// TrelloImportOptions podešava uvoz Trello table.
+type TrelloImportOptions struct {
+	DryRun          bool
+	ProjectName     string            // ako je prazno, koristi se naziv table
+	ExpectedEndDate *time.Time        // ako nije zadato, najkasniji rok kartice ili +90 dana
+	MaxMembers      int               // ako nije zadato, broj prepoznatih članova
+	ListMapping     map[string]string // naziv Trello liste -> status zadatka
+	ManagerUsername string
+	AuthToken       string
+}
+
+// importedTask odgovara services.ImportedTask u tasks-service-u.
+type importedTask struct {
+	SourceID    string          `json:"sourceId"`
+	Title       string          `json:"title"`
+	Description string          `json:"description"`
+	Status      string          `json:"status"`
+	Members     []models.Member `json:"members"`
+	Labels      []string        `json:"labels"`
+	DueDate     *time.Time      `json:"dueDate,omitempty"`
+	Subtasks    []importSubtask `json:"subtasks"`
+	Comments    []importComment `json:"comments"`
+}
+
+type importSubtask struct {
+	Title string `json:"title"`
+	Done  bool   `json:"done"`
+}
+
+type importComment struct {
+	Author    string    `json:"author"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+var validTaskStatuses = map[string]bool{"Pending": true, "In progress": true, "Completed": true}
+
+// guessStatusForList mapira uobičajene nazive Trello lista na statuse zadataka.
+func guessStatusForList(name string) (string, bool) {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.Contains(lower, "done"), strings.Contains(lower, "complete"), strings.Contains(lower, "finished"):
+		return "Completed", true
+	case strings.Contains(lower, "doing"), strings.Contains(lower, "progress"), strings.Contains(lower, "review"), strings.Contains(lower, "testing"):
+		return "In progress", true
+	case strings.Contains(lower, "to do"), strings.Contains(lower, "todo"), strings.Contains(lower, "backlog"), strings.Contains(lower, "pending"):
+		return "Pending", true
+	}
+	return "Pending", false
+}
+
+// ImportTrelloBoard pravi projekat i zadatke iz Trello izvoza. Kod dry-run-a ništa ne
+// upisuje, već vraća izveštaj o tome šta bi bilo uvezeno i šta ne može da se mapira.
+func (s *ProjectService) ImportTrelloBoard(board models.TrelloBoard, options TrelloImportOptions) (*models.TrelloImportReport, error) {
+	report := &models.TrelloImportReport{
+		DryRun:           options.DryRun,
+		ProjectName:      strings.TrimSpace(options.ProjectName),
+		ListMapping:      map[string]string{},
+		MatchedMembers:   []string{},
+		UnmatchedMembers: []string{},
+		Skipped:          []string{},
+		Warnings:         []string{},
+		Errors:           []string{},
+	}
+	if report.ProjectName == "" {
+		report.ProjectName = strings.TrimSpace(board.Name)
+	}
+	if report.ProjectName == "" {
+		return nil, fmt.Errorf("board name is missing; provide a project name")
+	}
+
+	// 1. Liste -> statusi
+	mapping := make(map[string]string)
+	for name, status := range options.ListMapping {
+		if !validTaskStatuses[status] {
+			return nil, fmt.Errorf("invalid status '%s' for list '%s'", status, name)
+		}
+		mapping[strings.ToLower(strings.TrimSpace(name))] = status
+	}
+	listStatus := make(map[string]string)
+	for _, list := range board.Lists {
+		if list.Closed {
+			report.Skipped = append(report.Skipped, fmt.Sprintf("list '%s' is archived", list.Name))
+			continue
+		}
+		status, ok := mapping[strings.ToLower(strings.TrimSpace(list.Name))]
+		if !ok {
+			var recognised bool
+			status, recognised = guessStatusForList(list.Name)
+			if !recognised {
+				report.Warnings = append(report.Warnings, fmt.Sprintf("list '%s' has no known status; mapped to %s", list.Name, status))
+			}
+		}
+		listStatus[list.ID] = status
+		report.ListMapping[list.Name] = status
+	}
+
+	// 2. Članovi table -> korisnici
+	members := make(map[string]models.Member)
+	for _, trelloMember := range board.Members {
+		member, err := s.fetchMemberByUsername(trelloMember.Username)
+		if err != nil {
+			report.UnmatchedMembers = append(report.UnmatchedMembers, trelloMember.Username)
+			continue
+		}
+		if member.Role == "manager" {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("user '%s' is a manager and is not added as a project or task member", member.Username))
+			continue
+		}
+		members[trelloMember.ID] = member
+		report.MatchedMembers = append(report.MatchedMembers, member.Username)
+	}
+
+	// 3. Checkliste i komentari po kartici
+	checklists := make(map[string][]models.TrelloChecklist)
+	for _, checklist := range board.Checklists {
+		checklists[checklist.IDCard] = append(checklists[checklist.IDCard], checklist)
+	}
+	comments := make(map[string][]importComment)
+	for _, action := range board.Actions {
+		if action.Type != "commentCard" {
+			continue
+		}
+		comments[action.Data.Card.ID] = append(comments[action.Data.Card.ID], importComment{
+			Author:    action.MemberCreator.Username,
+			Text:      action.Data.Text,
+			CreatedAt: action.Date,
+		})
+	}
+
+	// 4. Kartice -> zadaci
+	var tasks []importedTask
+	var latestDue time.Time
+	for _, card := range board.Cards {
+		if card.Closed {
+			report.Skipped = append(report.Skipped, fmt.Sprintf("card '%s' is archived", card.Name))
+			continue
+		}
+		status, ok := listStatus[card.IDList]
+		if !ok {
+			report.Skipped = append(report.Skipped, fmt.Sprintf("card '%s' is in an archived or unknown list", card.Name))
+			continue
+		}
+
+		task := importedTask{
+			SourceID:    card.ID,
+			Title:       card.Name,
+			Description: card.Desc,
+			Status:      status,
+			Members:     []models.Member{},
+			Labels:      []string{},
+			DueDate:     card.Due,
+			Subtasks:    []importSubtask{},
+			Comments:    comments[card.ID],
+		}
+		for _, memberID := range card.IDMembers {
+			if member, ok := members[memberID]; ok {
+				task.Members = append(task.Members, member)
+			} else {
+				report.Warnings = append(report.Warnings, fmt.Sprintf("card '%s': member %s could not be matched", card.Name, trelloUsername(board, memberID)))
+			}
+		}
+		for _, label := range card.Labels {
+			name := label.Name
+			if name == "" {
+				name = label.Color
+			}
+			if name != "" {
+				task.Labels = append(task.Labels, name)
+			}
+		}
+		cardChecklists := checklists[card.ID]
+		for _, checklist := range cardChecklists {
+			for _, item := range checklist.CheckItems {
+				title := item.Name
+				if len(cardChecklists) > 1 {
+					title = fmt.Sprintf("%s: %s", checklist.Name, item.Name)
+				}
+				task.Subtasks = append(task.Subtasks, importSubtask{Title: title, Done: item.State == "complete"})
+			}
+		}
+		if len(card.Attachments) > 0 {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("card '%s': %d attachments were not imported", card.Name, len(card.Attachments)))
+		}
+		if card.Due != nil && card.Due.After(latestDue) {
+			latestDue = *card.Due
+		}
+
+		report.SubtasksPlanned += len(task.Subtasks)
+		report.CommentsPlanned += len(task.Comments)
+		tasks = append(tasks, task)
+	}
+	report.TasksPlanned = len(tasks)
+
+	if options.DryRun {
+		logging.Logger.Infof("Trello import dry run for '%s': %d tasks, %d matched members, %d unmatched", report.ProjectName, report.TasksPlanned, len(report.MatchedMembers), len(report.UnmatchedMembers))
+		return report, nil
+	}
+
+	// 5. Projekat i članovi
+	expectedEndDate := time.Now().AddDate(0, 0, 90)
+	if options.ExpectedEndDate != nil {
+		expectedEndDate = *options.ExpectedEndDate
+	} else if latestDue.After(time.Now()) {
+		expectedEndDate = latestDue
+	}
+	maxMembers := options.MaxMembers
+	if maxMembers < len(report.MatchedMembers) {
+		maxMembers = len(report.MatchedMembers)
+	}
+	if maxMembers < 1 {
+		maxMembers = 1
+	}
+
+	managerID, err := s.getUserIDByUsername(options.ManagerUsername)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve manager: %v", err)
+	}
+	project, err := s.CreateProject(report.ProjectName, board.Desc, expectedEndDate, 1, maxMembers, managerID)
+	if err != nil {
+		return nil, err
+	}
+	report.ProjectID = project.ID.Hex()
+
+	if len(report.MatchedMembers) > 0 {
+		if err := s.AddMembersToProject(project.ID, report.MatchedMembers); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("failed to add members to project: %v", err))
+		}
+	}
+
+	// 6. Zadaci, u manjim paketima
+	for start := 0; start < len(tasks); start += importChunkSize {
+		end := start + importChunkSize
+		if end > len(tasks) {
+			end = len(tasks)
+		}
+		created, errs := s.sendImportedTasks(report.ProjectID, tasks[start:end], options)
+		report.TasksCreated += created
+		report.Errors = append(report.Errors, errs...)
+	}
+
+	logging.Logger.Infof("Trello board imported as project %s: %d/%d tasks created, %d errors", report.ProjectID, report.TasksCreated, report.TasksPlanned, len(report.Errors))
+	return report, nil
+}
+
+func trelloUsername(board models.TrelloBoard, memberID string) string {
+	for _, member := range board.Members {
+		if member.ID == memberID {
+			return "'" + member.Username + "'"
+		}
+	}
+	return memberID
+}
+
+// fetchMemberByUsername dohvata korisnika iz users-service-a po korisničkom imenu.
+func (s *ProjectService) fetchMemberByUsername(username string) (models.Member, error) {
+	usersServiceURL := os.Getenv("USERS_SERVICE_URL")
+	if usersServiceURL == "" {
+		return models.Member{}, fmt.Errorf("USERS_SERVICE_URL not set")
+	}
+	url := fmt.Sprintf("%s/api/users/member/%s", usersServiceURL, username)
+
+	result, err := s.UsersBreaker.Execute(func() (interface{}, error) {
+		resp, err := s.HTTPClient.Get(url)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("users-service returned status: %v", resp.Status)
+		}
+		var member models.Member
+		if err := json.NewDecoder(resp.Body).Decode(&member); err != nil {
+			return nil, err
+		}
+		return member, nil
+	})
+	if err != nil {
+		return models.Member{}, err
+	}
+	return result.(models.Member), nil
+}
+
+func (s *ProjectService) sendImportedTasks(projectID string, tasks []importedTask, options TrelloImportOptions) (int, []string) {
+	taskServiceURL := os.Getenv("TASKS_SERVICE_URL")
+	if taskServiceURL == "" {
+		return 0, []string{"TASKS_SERVICE_URL not set"}
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"projectId": projectID,
+		"actor":     options.ManagerUsername,
+		"tasks":     tasks,
+	})
+	if err != nil {
+		return 0, []string{fmt.Sprintf("failed to encode tasks: %v", err)}
+	}
+
+	var results []struct {
+		SourceID string `json:"sourceId"`
+		TaskID   string `json:"taskId"`
+		Error    string `json:"error"`
+	}
+	_, err = s.TasksBreaker.Execute(func() (interface{}, error) {
+		req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/tasks/import", taskServiceURL), bytes.NewBuffer(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Role", "manager")
+		if options.AuthToken != "" {
+			req.Header.Set("Authorization", options.AuthToken)
+		}
+		resp, err := s.HTTPClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated {
+			respBody, _ := io.ReadAll(resp.Body)
+			return nil, fmt.Errorf("tasks-service error: %s", string(respBody))
+		}
+		return nil, json.NewDecoder(resp.Body).Decode(&results)
+	})
+	if err != nil {
+		return 0, []string{fmt.Sprintf("failed to import %d tasks: %v", len(tasks), err)}
+	}
+
+	created := 0
+	var errs []string
+	for _, result := range results {
+		if result.TaskID != "" {
+			created++
+		}
+		if result.Error != "" {
+			errs = append(errs, fmt.Sprintf("card %s: %s", result.SourceID, result.Error))
+		}
+	}
+	return created, errs
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// errBlockedWebhookTarget vraća se kada webhook pokazuje na internu mrežu.
+var errBlockedWebhookTarget = errors.New("webhook target address is not allowed")
+
+// blockedWebhookNetworks su opsezi koji nisu pokriveni proverama iz net paketa, a ne smeju
+// biti odredište webhook-a (deljeni CGNAT opseg i "ova mreža").
+var blockedWebhookNetworks = []*net.IPNet{
+	mustParseCIDR("100.64.0.0/10"),
+	mustParseCIDR("0.0.0.0/8"),
+}
+
+// internalHostSuffixes su nazivi koji se razrešavaju samo unutar interne mreže.
+var internalHostSuffixes = []string{".localhost", ".local", ".internal", ".docker"}
+
+func mustParseCIDR(cidr string) *net.IPNet {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		panic(err)
+	}
+	return network
+}
+
+// isBlockedWebhookIP vraća true za loopback, privatne, link-local i ostale adrese koje
+// nisu javno dostupne.
+func isBlockedWebhookIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return true
+	}
+	for _, network := range blockedWebhookNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateWebhookURL proverava URL pri registraciji. Nazivi bez domena (npr. users-service
+// ili mongo-projects u docker mreži) i adrese interne mreže se odbijaju; naziv sa domenom
+// mora da se razreši isključivo na javne adrese.
+func validateWebhookURL(ctx context.Context, rawURL string) (*url.URL, error) {
+	target, err := url.Parse(rawURL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Hostname() == "" {
+		return nil, fmt.Errorf("invalid webhook URL")
+	}
+	if target.User != nil {
+		return nil, fmt.Errorf("invalid webhook URL: credentials are not allowed")
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(target.Hostname(), "."))
+	if ip := net.ParseIP(host); ip != nil {
+		if isBlockedWebhookIP(ip) {
+			return nil, fmt.Errorf("invalid webhook URL: %v", errBlockedWebhookTarget)
+		}
+		return target, nil
+	}
+	if !strings.Contains(host, ".") {
+		return nil, fmt.Errorf("invalid webhook URL: internal host names are not allowed")
+	}
+	for _, suffix := range internalHostSuffixes {
+		if strings.HasSuffix(host, suffix) {
+			return nil, fmt.Errorf("invalid webhook URL: internal host names are not allowed")
+		}
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	addrs, err := net.DefaultResolver.LookupIPAddr(lookupCtx, host)
+	if err != nil || len(addrs) == 0 {
+		return nil, fmt.Errorf("invalid webhook URL: host cannot be resolved")
+	}
+	for _, addr := range addrs {
+		if isBlockedWebhookIP(addr.IP) {
+			return nil, fmt.Errorf("invalid webhook URL: %v", errBlockedWebhookTarget)
+		}
+	}
+	return target, nil
+}
+
+// NewWebhookHTTPClient pravi klijenta za isporuke webhook-ova. Adresa se proverava pri
+// svakom uspostavljanju veze, posle DNS razrešavanja, pa naziv koji je pri registraciji
+// pokazivao na javnu adresu ne može kasnije (ili kroz redirect) da dovede do interne mreže.
+func NewWebhookHTTPClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   5 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || isBlockedWebhookIP(ip) {
+				return fmt.Errorf("%v: %s", errBlockedWebhookTarget, host)
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			// Bez proxy-ja, da bi se proveravala adresa samog odredišta
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			MaxIdleConnsPerHost: 2,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 3 {
+				return fmt.Errorf("stopped after %d redirects", len(via))
+			}
+			return nil
+		},
+	}
+}
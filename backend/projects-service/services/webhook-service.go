@@ -0,0 +1,386 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"trello-project/microservices/projects-service/logging"
+	"trello-project/microservices/projects-service/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrWebhookNotFound vraća se kada webhook ne postoji na projektu.
+var ErrWebhookNotFound = errors.New("webhook not found")
+
+const (
+	webhookMaxAttempts = 6
+	webhookLease       = time.Minute
+)
+
+// webhookEnvelope je telo koje se šalje webhook-u. ID je isti za sve webhook-ove koji
+// primaju isti događaj, pa primalac može da prepozna duplikate.
+type webhookEnvelope struct {
+	ID         string                 `json:"id"`
+	Event      models.WebhookEvent    `json:"event"`
+	ProjectID  string                 `json:"projectId"`
+	OccurredAt time.Time              `json:"occurredAt"`
+	Data       map[string]interface{} `json:"data"`
+}
+
+// CreateWebhook registruje webhook projekta za izabrane događaje i generiše tajnu za potpis.
+func (s *ProjectService) CreateWebhook(ctx context.Context, projectID string, registration models.WebhookRegistration, createdBy string) (*models.Webhook, error) {
+	project, err := s.GetProjectByID(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := validateWebhookURL(ctx, registration.URL)
+	if err != nil {
+		return nil, err
+	}
+	if len(registration.Events) == 0 {
+		return nil, fmt.Errorf("at least one event is required")
+	}
+	events := make([]models.WebhookEvent, 0, len(registration.Events))
+	seen := map[models.WebhookEvent]bool{}
+	for _, event := range registration.Events {
+		if !models.ValidWebhookEvent(event) {
+			return nil, fmt.Errorf("unknown webhook event: %s", event)
+		}
+		if !seen[event] {
+			seen[event] = true
+			events = append(events, event)
+		}
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate webhook secret: %v", err)
+	}
+
+	webhook := models.Webhook{
+		ID:        primitive.NewObjectID(),
+		ProjectID: project.ID,
+		URL:       target.String(),
+		Events:    events,
+		Secret:    hex.EncodeToString(secret),
+		CreatedBy: createdBy,
+		CreatedAt: time.Now().UTC(),
+	}
+	if _, err := s.WebhooksCollection.InsertOne(ctx, webhook); err != nil {
+		logging.Logger.Errorf("Failed to save webhook for project %s: %v", projectID, err)
+		return nil, fmt.Errorf("failed to save webhook")
+	}
+
+	logging.Logger.Infof("Webhook %s registered on project %s for %v", webhook.ID.Hex(), projectID, events)
+	return &webhook, nil
+}
+
+// ListWebhooks vraća webhook-ove projekta, bez tajni.
+func (s *ProjectService) ListWebhooks(ctx context.Context, projectID string) ([]models.Webhook, error) {
+	projectObjectID, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid project ID format")
+	}
+	cursor, err := s.WebhooksCollection.Find(ctx, bson.M{"project_id": projectObjectID}, options.Find().SetSort(bson.M{"created_at": 1}))
+	if err != nil {
+		logging.Logger.Errorf("Failed to fetch webhooks of project %s: %v", projectID, err)
+		return nil, fmt.Errorf("failed to fetch webhooks")
+	}
+	defer cursor.Close(ctx)
+
+	webhooks := []models.Webhook{}
+	if err := cursor.All(ctx, &webhooks); err != nil {
+		return nil, fmt.Errorf("failed to decode webhooks: %v", err)
+	}
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+	return webhooks, nil
+}
+
+// DeleteWebhook uklanja webhook; istorija isporuka ostaje, a isporuke na čekanju se prekidaju.
+func (s *ProjectService) DeleteWebhook(ctx context.Context, projectID, webhookID string) error {
+	webhook, err := s.getWebhook(ctx, projectID, webhookID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.WebhooksCollection.DeleteOne(ctx, bson.M{"_id": webhook.ID}); err != nil {
+		logging.Logger.Errorf("Failed to delete webhook %s: %v", webhookID, err)
+		return fmt.Errorf("failed to delete webhook")
+	}
+	logging.Logger.Infof("Webhook %s removed from project %s", webhookID, projectID)
+	return nil
+}
+
+// ListWebhookDeliveries vraća poslednje isporuke webhook-a, od najnovije.
+func (s *ProjectService) ListWebhookDeliveries(ctx context.Context, projectID, webhookID string, limit int64) ([]models.WebhookDelivery, error) {
+	projectObjectID, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid project ID format")
+	}
+	webhookObjectID, err := primitive.ObjectIDFromHex(webhookID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook ID format")
+	}
+
+	opts := options.Find().SetSort(bson.M{"created_at": -1}).SetLimit(limit)
+	cursor, err := s.WebhookDeliveriesCollection.Find(ctx, bson.M{"project_id": projectObjectID, "webhook_id": webhookObjectID}, opts)
+	if err != nil {
+		logging.Logger.Errorf("Failed to fetch deliveries of webhook %s: %v", webhookID, err)
+		return nil, fmt.Errorf("failed to fetch webhook deliveries")
+	}
+	defer cursor.Close(ctx)
+
+	deliveries := []models.WebhookDelivery{}
+	if err := cursor.All(ctx, &deliveries); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook deliveries: %v", err)
+	}
+	return deliveries, nil
+}
+
+// EmitWebhookEvent upisuje isporuku za svaki webhook projekta pretplaćen na događaj i
+// odmah pokušava slanje. Isporuke koje ne uspeju ponavlja StartWebhookDispatcher.
+func (s *ProjectService) EmitWebhookEvent(ctx context.Context, projectID string, event models.WebhookEvent, data map[string]interface{}) error {
+	if !models.ValidWebhookEvent(event) {
+		return fmt.Errorf("unknown webhook event: %s", event)
+	}
+	projectObjectID, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return fmt.Errorf("invalid project ID format")
+	}
+
+	cursor, err := s.WebhooksCollection.Find(ctx, bson.M{"project_id": projectObjectID, "events": event})
+	if err != nil {
+		logging.Logger.Errorf("Failed to fetch webhooks for %s on project %s: %v", event, projectID, err)
+		return fmt.Errorf("failed to fetch webhooks")
+	}
+	var webhooks []models.Webhook
+	if err := cursor.All(ctx, &webhooks); err != nil {
+		return fmt.Errorf("failed to decode webhooks: %v", err)
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	payload, err := json.Marshal(webhookEnvelope{
+		ID:         primitive.NewObjectID().Hex(),
+		Event:      event,
+		ProjectID:  projectID,
+		OccurredAt: now,
+		Data:       data,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %v", err)
+	}
+
+	for _, webhook := range webhooks {
+		if !webhook.Subscribed(event) {
+			continue
+		}
+		delivery := models.WebhookDelivery{
+			ID:            primitive.NewObjectID(),
+			WebhookID:     webhook.ID,
+			ProjectID:     projectObjectID,
+			Event:         event,
+			Payload:       string(payload),
+			Status:        models.DeliveryPending,
+			Attempts:      []models.DeliveryAttempt{},
+			CreatedAt:     now,
+			NextAttemptAt: now,
+		}
+		if _, err := s.WebhookDeliveriesCollection.InsertOne(ctx, delivery); err != nil {
+			logging.Logger.Errorf("Failed to queue %s delivery for webhook %s: %v", event, webhook.ID.Hex(), err)
+			continue
+		}
+		go s.dispatchWebhookDelivery(context.Background(), delivery.ID)
+	}
+	return nil
+}
+
+// emitWebhookEventAsync šalje događaj iz koda ovog servisa, bez čekanja na upis isporuka.
+func (s *ProjectService) emitWebhookEventAsync(projectID string, event models.WebhookEvent, data map[string]interface{}) {
+	go func() {
+		if err := s.EmitWebhookEvent(context.Background(), projectID, event, data); err != nil {
+			logging.Logger.Warnf("Failed to emit %s webhook event for project %s: %v", event, projectID, err)
+		}
+	}()
+}
+
+// StartWebhookDispatcher periodično ponavlja isporuke kojima je došlo vreme za novi pokušaj.
+func (s *ProjectService) StartWebhookDispatcher(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			s.retryWebhookDeliveries(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (s *ProjectService) retryWebhookDeliveries(ctx context.Context) {
+	cursor, err := s.WebhookDeliveriesCollection.Find(ctx, bson.M{
+		"status":          models.DeliveryPending,
+		"next_attempt_at": bson.M{"$lte": time.Now().UTC()},
+	}, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		logging.Logger.Errorf("Failed to fetch pending webhook deliveries: %v", err)
+		return
+	}
+	defer cursor.Close(ctx)
+
+	var pending []models.WebhookDelivery
+	if err := cursor.All(ctx, &pending); err != nil {
+		logging.Logger.Errorf("Failed to decode pending webhook deliveries: %v", err)
+		return
+	}
+	for _, delivery := range pending {
+		s.dispatchWebhookDelivery(ctx, delivery.ID)
+	}
+}
+
+// dispatchWebhookDelivery preuzima isporuku (zakup sprečava da je dve instance šalju
+// istovremeno), šalje je i beleži ishod pokušaja.
+func (s *ProjectService) dispatchWebhookDelivery(ctx context.Context, deliveryID primitive.ObjectID) {
+	now := time.Now().UTC()
+	var delivery models.WebhookDelivery
+	err := s.WebhookDeliveriesCollection.FindOneAndUpdate(ctx,
+		bson.M{"_id": deliveryID, "status": models.DeliveryPending, "next_attempt_at": bson.M{"$lte": now}},
+		bson.M{"$set": bson.M{"next_attempt_at": now.Add(webhookLease)}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&delivery)
+	if err == mongo.ErrNoDocuments {
+		return
+	}
+	if err != nil {
+		logging.Logger.Errorf("Failed to claim webhook delivery %s: %v", deliveryID.Hex(), err)
+		return
+	}
+
+	var webhook models.Webhook
+	if err := s.WebhooksCollection.FindOne(ctx, bson.M{"_id": delivery.WebhookID}).Decode(&webhook); err != nil {
+		attempt := models.DeliveryAttempt{At: now, Error: "webhook no longer exists"}
+		s.recordDeliveryAttempt(ctx, &delivery, attempt, models.DeliveryFailed)
+		return
+	}
+
+	attempt := s.sendWebhook(ctx, &webhook, &delivery)
+	switch {
+	case attempt.Error == "":
+		s.recordDeliveryAttempt(ctx, &delivery, attempt, models.DeliveryDelivered)
+	case len(delivery.Attempts)+1 >= webhookMaxAttempts:
+		logging.Logger.Warnf("Webhook delivery %s to %s failed permanently: %s", delivery.ID.Hex(), webhook.URL, attempt.Error)
+		s.recordDeliveryAttempt(ctx, &delivery, attempt, models.DeliveryFailed)
+	default:
+		s.recordDeliveryAttempt(ctx, &delivery, attempt, models.DeliveryPending)
+	}
+}
+
+// sendWebhook šalje isporuku potpisanu sa HMAC-SHA256. Potpis pokriva
+// "<X-Webhook-Timestamp>.<telo>", pa primalac može da odbije ponovljene stare zahteve.
+func (s *ProjectService) sendWebhook(ctx context.Context, webhook *models.Webhook, delivery *models.WebhookDelivery) models.DeliveryAttempt {
+	started := time.Now().UTC()
+	attempt := models.DeliveryAttempt{At: started}
+
+	timestamp := strconv.FormatInt(started.Unix(), 10)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewBufferString(delivery.Payload))
+	if err != nil {
+		attempt.Error = fmt.Sprintf("failed to create request: %v", err)
+		return attempt
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "trello-project-webhooks/1.0")
+	req.Header.Set("X-Webhook-Event", string(delivery.Event))
+	req.Header.Set("X-Webhook-Delivery", delivery.ID.Hex())
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Signature", "sha256="+SignWebhookPayload(webhook.Secret, timestamp, delivery.Payload))
+
+	resp, err := s.WebhookClient.Do(req)
+	attempt.DurationMs = time.Since(started).Milliseconds()
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	attempt.StatusCode = resp.StatusCode
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		attempt.Error = fmt.Sprintf("endpoint returned status %d", resp.StatusCode)
+	}
+	return attempt
+}
+
+// SignWebhookPayload vraća heksadecimalni HMAC-SHA256 potpis isporuke.
+func SignWebhookPayload(secret, timestamp, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *ProjectService) recordDeliveryAttempt(ctx context.Context, delivery *models.WebhookDelivery, attempt models.DeliveryAttempt, status models.WebhookDeliveryStatus) {
+	set := bson.M{"status": status}
+	switch status {
+	case models.DeliveryDelivered:
+		set["delivered_at"] = attempt.At
+	case models.DeliveryPending:
+		set["next_attempt_at"] = time.Now().UTC().Add(webhookRetryDelay(len(delivery.Attempts) + 1))
+	}
+	_, err := s.WebhookDeliveriesCollection.UpdateOne(ctx,
+		bson.M{"_id": delivery.ID},
+		bson.M{"$set": set, "$push": bson.M{"attempts": attempt}},
+	)
+	if err != nil {
+		logging.Logger.Errorf("Failed to record attempt of webhook delivery %s: %v", delivery.ID.Hex(), err)
+	}
+}
+
+func (s *ProjectService) getWebhook(ctx context.Context, projectID, webhookID string) (*models.Webhook, error) {
+	projectObjectID, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid project ID format")
+	}
+	webhookObjectID, err := primitive.ObjectIDFromHex(webhookID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook ID format")
+	}
+	var webhook models.Webhook
+	err = s.WebhooksCollection.FindOne(ctx, bson.M{"_id": webhookObjectID, "project_id": projectObjectID}).Decode(&webhook)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrWebhookNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch webhook: %v", err)
+	}
+	return &webhook, nil
+}
+
+// webhookRetryDelay se udvostručuje posle svakog pokušaja: 30s, 1m, 2m, 4m, 8m.
+func webhookRetryDelay(attempts int) time.Duration {
+	delay := 30 * time.Second << (attempts - 1)
+	if delay > time.Hour {
+		return time.Hour
+	}
+	return delay
+}
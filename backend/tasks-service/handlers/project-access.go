@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"trello-project/microservices/tasks-service/logging"
+	"trello-project/microservices/tasks-service/models"
+
+	"trello-project/backend/utils/projectroles"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Uloge na projektu: viewer čita zadatke, contributor kreira zadatke i radi na njima,
+// maintainer upravlja zadacima, članovima, sprintovima i podešavanjima, a owner i samim
+// projektom. Pozivi drugih servisa ne šalju korisnički token, pa se za njih i dalje
+// proverava globalno Role zaglavlje.
+
+func hasUserToken(r *http.Request) bool {
+	return strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ") != ""
+}
+
+// isMutation vraća true za zahteve koji menjaju podatke; na arhiviranom projektu su
+// dozvoljeni samo zahtevi za čitanje, uključujući izvoz.
+func isMutation(r *http.Request) bool {
+	return r.Method != http.MethodGet && r.Method != http.MethodHead && r.Method != http.MethodOptions
+}
+
+// serviceProjectRole preslikava globalnu ulogu iz poziva drugog servisa u ulogu na
+// projektu: manager ima prava maintainer-a, a ostali contributor-a.
+func serviceProjectRole(r *http.Request) projectroles.Role {
+	if r.Header.Get("Role") == "manager" {
+		return projectroles.Maintainer
+	}
+	return projectroles.Contributor
+}
+
+// authorizeProject proverava da li pozivalac ima bar ulogu min na projektu.
+func (h *TaskHandler) authorizeProject(w http.ResponseWriter, r *http.Request, projectID string, min projectroles.Role, serviceRoles []string) bool {
+	_, ok := h.projectRole(w, r, projectID, min, serviceRoles)
+	return ok
+}
+
+// projectRole je authorizeProject koji vraća i utvrđenu ulogu pozivaoca, za provere
+// koje zavise od uloge (npr. zaobilaženje WIP limita).
+func (h *TaskHandler) projectRole(w http.ResponseWriter, r *http.Request, projectID string, min projectroles.Role, serviceRoles []string) (projectroles.Role, bool) {
+	if !hasUserToken(r) {
+		if err := checkRole(r, serviceRoles); err != nil {
+			http.Error(w, "Access forbidden: insufficient permissions", http.StatusForbidden)
+			return "", false
+		}
+		return serviceProjectRole(r), true
+	}
+
+	require := h.roles.Require
+	if isMutation(r) {
+		require = h.roles.RequireWrite
+	}
+	role, err := require(projectID, r.Header.Get("Authorization"), min)
+	if err != nil {
+		writeProjectAccessError(w, r, projectID, role, min, err)
+		return "", false
+	}
+	return role, true
+}
+
+// authorizeTask proverava ulogu pozivaoca na projektu kome zadatak pripada.
+func (h *TaskHandler) authorizeTask(w http.ResponseWriter, r *http.Request, taskID string, min projectroles.Role, serviceRoles []string) bool {
+	_, ok := h.taskRole(w, r, taskID, min, serviceRoles)
+	return ok
+}
+
+// taskRole je authorizeTask koji vraća i utvrđenu ulogu pozivaoca na projektu zadatka.
+func (h *TaskHandler) taskRole(w http.ResponseWriter, r *http.Request, taskID string, min projectroles.Role, serviceRoles []string) (projectroles.Role, bool) {
+	if !hasUserToken(r) {
+		return h.projectRole(w, r, "", min, serviceRoles)
+	}
+
+	taskObjectID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		http.Error(w, "Invalid task ID format", http.StatusBadRequest)
+		return "", false
+	}
+	task, err := h.service.GetTaskByID(taskObjectID)
+	if err != nil {
+		http.Error(w, "Task not found", http.StatusNotFound)
+		return "", false
+	}
+	return h.projectRole(w, r, task.ProjectID, min, serviceRoles)
+}
+
+func writeProjectAccessError(w http.ResponseWriter, r *http.Request, projectID string, role, min projectroles.Role, err error) {
+	switch {
+	case errors.Is(err, projectroles.ErrNoAccess), errors.Is(err, projectroles.ErrInsufficientRole):
+		logging.Logger.Warnf("Event ID: AUTH_PROJECT_ROLE_DENIED, Description: Access to %s %s denied on project %s (role '%s', requires '%s').", r.Method, r.URL.Path, projectID, role, min)
+		http.Error(w, "Access forbidden: "+err.Error(), http.StatusForbidden)
+	case errors.Is(err, projectroles.ErrProjectNotFound):
+		http.Error(w, "Project not found", http.StatusNotFound)
+	case errors.Is(err, projectroles.ErrProjectDeleting), errors.Is(err, projectroles.ErrProjectArchived):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		logging.Logger.Errorf("Event ID: AUTH_PROJECT_ROLE_UNAVAILABLE, Description: Failed to resolve project role for project %s: %v", projectID, err)
+		http.Error(w, "Failed to verify project role", http.StatusServiceUnavailable)
+	}
+}
+
+// authorizeTemplate proverava pristup šablonu: za šablon projekta važi uloga na projektu,
+// a globalnim šablonima i dalje upravljaju menadžeri.
+func (h *TaskHandler) authorizeTemplate(w http.ResponseWriter, r *http.Request, template *models.TaskTemplate, min projectroles.Role, globalRoles []string) bool {
+	if template.ProjectID == "" {
+		if err := checkRole(r, globalRoles); err != nil {
+			http.Error(w, "Access forbidden: insufficient permissions", http.StatusForbidden)
+			return false
+		}
+		return true
+	}
+	return h.authorizeProject(w, r, template.ProjectID, min, globalRoles)
+}
+
+// authorizeSprint proverava ulogu na projektu sprinta. Sprint bez zadataka nema šta da
+// otkrije, pa je tada dovoljna globalna uloga.
+func (h *TaskHandler) authorizeSprint(w http.ResponseWriter, r *http.Request, sprintID string, min projectroles.Role, serviceRoles []string) bool {
+	if !hasUserToken(r) {
+		return h.authorizeProject(w, r, "", min, serviceRoles)
+	}
+
+	projectID, err := h.service.SprintProjectID(r.Context(), sprintID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return false
+	}
+	if projectID == "" {
+		if err := checkRole(r, serviceRoles); err != nil {
+			http.Error(w, "Access forbidden: insufficient permissions", http.StatusForbidden)
+			return false
+		}
+		return true
+	}
+	return h.authorizeProject(w, r, projectID, min, serviceRoles)
+}
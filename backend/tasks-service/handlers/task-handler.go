@@ -1,13 +1,18 @@
 package handlers
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 	"trello-project/microservices/tasks-service/logging"
 	"trello-project/microservices/tasks-service/models"
 	"trello-project/microservices/tasks-service/services"
+	"trello-project/microservices/tasks-service/utils"
+
+	"trello-project/backend/utils/projectroles"
 
 	"github.com/gorilla/mux"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -15,10 +20,11 @@ import (
 
 type TaskHandler struct {
 	service *services.TaskService
+	roles   *projectroles.Resolver
 }
 
-func NewTaskHandler(service *services.TaskService) *TaskHandler {
-	return &TaskHandler{service: service}
+func NewTaskHandler(service *services.TaskService, roles *projectroles.Resolver) *TaskHandler {
+	return &TaskHandler{service: service, roles: roles}
 }
 func checkRole(r *http.Request, allowedRoles []string) error {
 	userRole := r.Header.Get("Role")
@@ -37,29 +43,113 @@ func checkRole(r *http.Request, allowedRoles []string) error {
 	return fmt.Errorf("access forbidden: user does not have the required role")
 }
 
+// actorFromRequest vraća username korisnika iz Authorization tokena; prazan string
+// ako token nije prosleđen ili nije validan (npr. poziv iz drugog servisa).
+func actorFromRequest(r *http.Request) string {
+	if !hasUserToken(r) {
+		return ""
+	}
+	username, err := tokenActor(r)
+	if err != nil {
+		return ""
+	}
+	return username
+}
+
+// tokenActor vraća username iz Authorization tokena ili grešku ako token ne može da se
+// pročita; koriste ga izmene koje se beleže u istoriji pod imenom korisnika.
+func tokenActor(r *http.Request) (string, error) {
+	tokenString := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	username, err := utils.ExtractUsernameFromToken(tokenString)
+	if err != nil {
+		logging.Logger.Errorf("Event ID: ACTOR_NOT_RESOLVED, Description: Could not resolve actor for %s: %v", r.URL.Path, err)
+		return "", err
+	}
+	return username, nil
+}
+
 func (h *TaskHandler) CreateTask(w http.ResponseWriter, r *http.Request) {
-	if err := checkRole(r, []string{"manager"}); err != nil {
-		http.Error(w, "Access forbidden: insufficient permissions", http.StatusForbidden)
-		return
+	var request struct {
+		models.Task
+		// TemplateID je opcion; polja zadatka navedena uz njega zamenjuju vrednosti iz šablona
+		TemplateID string   `json:"templateId"`
+		Checklist  []string `json:"checklist"`
 	}
-	var task models.Task
-	if err := json.NewDecoder(r.Body).Decode(&task); err != nil {
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
 		logging.Logger.Errorf("Event ID: TASK_CREATE_DECODE_ERROR, Description: Failed to decode request body for task creation: %v", err)
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	task := request.Task
+	if !h.authorizeProject(w, r, task.ProjectID, projectroles.Contributor, []string{"manager"}) {
+		return
+	}
+	// Rok i pravilo ponavljanja se proveravaju pre kreiranja, da neispravan zahtev ne bi
+	// ostavio kreiran zadatak sa već upisanom istorijom i poslatim događajima
+	if task.DueDate != nil && task.DueDate.IsZero() {
+		http.Error(w, "invalid due date", http.StatusBadRequest)
+		return
+	}
+	if task.Recurrence != nil {
+		if err := task.Recurrence.Normalize(); err != nil {
+			logging.Logger.Warnf("Event ID: TASK_CREATE_INVALID_RECURRENCE, Description: Rejected task with invalid recurrence: %v", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	var createdTask *models.Task
+	var err error
+	if request.TemplateID != "" {
+		templateID, idErr := primitive.ObjectIDFromHex(request.TemplateID)
+		if idErr != nil {
+			http.Error(w, "Invalid template ID format", http.StatusBadRequest)
+			return
+		}
+		overrides := models.TemplateOverrides{
+			Status:    task.Status,
+			Labels:    task.Labels,
+			Checklist: request.Checklist,
+			DueDate:   task.DueDate,
+		}
+		if task.Title != "" {
+			overrides.Title = &task.Title
+		}
+		if task.Description != "" {
+			overrides.Description = &task.Description
+		}
+		createdTask, err = h.service.CreateTaskFromTemplate(r.Context(), templateID, task.ProjectID, overrides, actorFromRequest(r))
+		if err != nil {
+			logging.Logger.Errorf("Event ID: TASK_CREATE_SERVICE_ERROR, Description: Failed to create task from template %s: %v", request.TemplateID, err)
+			writeTemplateError(w, err)
+			return
+		}
+		// Rok je već postavljen iz šablona ili override-a
+		task.DueDate = nil
+	} else {
+		// Ako status nije naveden, postavi ga na "pending"
+		if task.Status == "" {
+			task.Status = models.StatusPending
+		}
 
-	// Ako status nije naveden, postavi ga na "pending"
-	if task.Status == "" {
-		task.Status = models.StatusPending
+		// Sada prosleđujemo status prilikom kreiranja taska
+		createdTask, err = h.service.CreateTask(task.ProjectID, task.Title, task.Description, task.Status, actorFromRequest(r))
+		if err != nil {
+			logging.Logger.Errorf("Event ID: TASK_CREATE_SERVICE_ERROR, Description: Failed to create task in service: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
 	}
 
-	// Sada prosleđujemo status prilikom kreiranja taska
-	createdTask, err := h.service.CreateTask(task.ProjectID, task.Title, task.Description, task.Status)
-	if err != nil {
-		logging.Logger.Errorf("Event ID: TASK_CREATE_SERVICE_ERROR, Description: Failed to create task in service: %v", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+	// Već provereni rok i pravilo ponavljanja se upisuju posle kreiranja
+	if task.DueDate != nil || task.Recurrence != nil {
+		scheduledTask, err := h.service.SetTaskRecurrence(r.Context(), createdTask.ID, task.DueDate, task.Recurrence)
+		if err != nil {
+			logging.Logger.Errorf("Event ID: TASK_CREATE_RECURRENCE_ERROR, Description: Failed to set recurrence for task %s: %v", createdTask.ID.Hex(), err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		createdTask = scheduledTask
 	}
 	logging.Logger.Infof("Event ID: TASK_CREATED_SUCCESS, Description: Task '%s' created successfully for Project ID '%s'. Task ID: %s", createdTask.Title, createdTask.ProjectID, createdTask.ID.Hex())
 	w.WriteHeader(http.StatusCreated)
@@ -82,13 +172,12 @@ func (h *TaskHandler) GetAllTasks(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(tasks)
 }
 func (h *TaskHandler) GetAvailableMembersForTask(w http.ResponseWriter, r *http.Request) {
-	if err := checkRole(r, []string{"manager"}); err != nil {
-		http.Error(w, "Access forbidden: insufficient permissions", http.StatusForbidden)
-		return
-	}
 	vars := mux.Vars(r)
 	taskID := vars["taskID"]
 	projectID := vars["projectID"]
+	if !h.authorizeProject(w, r, projectID, projectroles.Maintainer, []string{"manager"}) {
+		return
+	}
 
 	logging.Logger.Debugf("Event ID: MEMBERS_AVAILABLE_REQUEST, Description: Request to get available members for taskID: %s, projectID: %s", taskID, projectID)
 
@@ -113,16 +202,11 @@ func (h *TaskHandler) GetAvailableMembersForTask(w http.ResponseWriter, r *http.
 }
 
 func (h TaskHandler) GetTasksByProjectID(w http.ResponseWriter, r *http.Request) {
-	if err := checkRole(r, []string{"manager", "member"}); err != nil {
-		http.Error(w, "Access forbidden: insufficient permissions", http.StatusForbidden)
-		return
-	}
-
 	// Loguj celu URL putanju
 	logging.Logger.Debugf("Event ID: TASKS_BY_PROJECT_REQUEST, Description: Requested URL: %s", r.URL.Path)
 
 	// Ekstraktuj projectID
-	projectID := strings.TrimPrefix(r.URL.Path, "/api/tasks/project/")
+	projectID := mux.Vars(r)["projectId"]
 	logging.Logger.Debugf("Event ID: TASKS_BY_PROJECT_EXTRACTED_ID, Description: Extracted Project ID: %s", projectID)
 
 	// Ukloni eventualne nepotrebne '/' karaktere
@@ -134,6 +218,9 @@ func (h TaskHandler) GetTasksByProjectID(w http.ResponseWriter, r *http.Request)
 		http.Error(w, "Missing project ID", http.StatusBadRequest)
 		return
 	}
+	if !h.authorizeProject(w, r, projectID, projectroles.Viewer, []string{"manager", "member"}) {
+		return
+	}
 
 	tasks, err := h.service.GetTasksByProjectID(projectID)
 	if err != nil {
@@ -151,12 +238,11 @@ func (h TaskHandler) GetTasksByProjectID(w http.ResponseWriter, r *http.Request)
 }
 
 func (h *TaskHandler) AddMembersToTask(w http.ResponseWriter, r *http.Request) {
-	if err := checkRole(r, []string{"manager"}); err != nil {
-		http.Error(w, "Access forbidden: insufficient permissions", http.StatusForbidden)
-		return
-	}
 	vars := mux.Vars(r)
 	taskID := vars["taskID"]
+	if !h.authorizeTask(w, r, taskID, projectroles.Maintainer, []string{"manager"}) {
+		return
+	}
 
 	logging.Logger.Debugf("Event ID: TASK_ADD_MEMBERS_REQUEST, Description: Task ID received for adding members: %s", taskID)
 
@@ -169,7 +255,7 @@ func (h *TaskHandler) AddMembersToTask(w http.ResponseWriter, r *http.Request) {
 
 	logging.Logger.Debugf("Event ID: TASK_ADD_MEMBERS_PAYLOAD, Description: Members received for task %s: %+v", taskID, members)
 
-	err := h.service.AddMembersToTask(taskID, members)
+	err := h.service.AddMembersToTask(taskID, members, actorFromRequest(r))
 	if err != nil {
 		logging.Logger.Errorf("Event ID: TASK_ADD_MEMBERS_SERVICE_ERROR, Description: Error adding members to task %s: %v", taskID, err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -183,12 +269,11 @@ func (h *TaskHandler) AddMembersToTask(w http.ResponseWriter, r *http.Request) {
 
 // GetMembersForTaskHandler dohvaća članove dodeljene određenom tasku
 func (h *TaskHandler) GetMembersForTaskHandler(w http.ResponseWriter, r *http.Request) {
-	if err := checkRole(r, []string{"manager", "member"}); err != nil {
-		http.Error(w, "Access forbidden: insufficient permissions", http.StatusForbidden)
-		return
-	}
 	vars := mux.Vars(r)
 	taskID := vars["taskID"]
+	if !h.authorizeTask(w, r, taskID, projectroles.Viewer, []string{"manager", "member"}) {
+		return
+	}
 
 	// Konverzija taskID u ObjectID
 	taskObjectID, err := primitive.ObjectIDFromHex(taskID)
@@ -213,15 +298,11 @@ func (h *TaskHandler) GetMembersForTaskHandler(w http.ResponseWriter, r *http.Re
 
 // promena statusa
 func (h TaskHandler) ChangeTaskStatus(w http.ResponseWriter, r *http.Request) {
-	if err := checkRole(r, []string{"member"}); err != nil {
-		http.Error(w, "Access forbidden: insufficient permissions", http.StatusForbidden)
-		return
-	}
-
 	var request struct {
-		TaskID   string            `json:"taskId"`
-		Status   models.TaskStatus `json:"status"`
-		Username string            `json:"username"`
+		TaskID      string            `json:"taskId"`
+		Status      models.TaskStatus `json:"status"`
+		Username    string            `json:"username"`
+		OverrideWIP bool              `json:"overrideWip"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
@@ -229,6 +310,20 @@ func (h TaskHandler) ChangeTaskStatus(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Invalid request", http.StatusBadRequest)
 		return
 	}
+	role, ok := h.taskRole(w, r, request.TaskID, projectroles.Contributor, []string{"member", "manager"})
+	if !ok {
+		return
+	}
+
+	// Korisnik se uvek uzima iz tokena; username iz tela važi samo za pozive drugih servisa
+	if hasUserToken(r) {
+		actor, err := tokenActor(r)
+		if err != nil {
+			http.Error(w, "Failed to identify user from token", http.StatusUnauthorized)
+			return
+		}
+		request.Username = actor
+	}
 
 	taskObjectID, err := primitive.ObjectIDFromHex(request.TaskID)
 	if err != nil {
@@ -237,9 +332,13 @@ func (h TaskHandler) ChangeTaskStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	updatedTask, err := h.service.ChangeTaskStatus(taskObjectID, request.Status, request.Username)
+	updatedTask, err := h.service.ChangeTaskStatus(taskObjectID, request.Status, request.Username, role, request.OverrideWIP)
 	if err != nil {
 		logging.Logger.Errorf("Event ID: TASK_CHANGE_STATUS_SERVICE_ERROR, Description: Failed to change task status for task %s to %s by user %s: %v", request.TaskID, request.Status, request.Username, err)
+		if strings.Contains(err.Error(), "WIP limit reached") {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -250,12 +349,11 @@ func (h TaskHandler) ChangeTaskStatus(w http.ResponseWriter, r *http.Request) {
 
 // RemoveMemberFromTaskHandler uklanja člana sa zadatka
 func (h *TaskHandler) RemoveMemberFromTaskHandler(w http.ResponseWriter, r *http.Request) {
-	if err := checkRole(r, []string{"manager"}); err != nil {
-		http.Error(w, "Access forbidden: insufficient permissions", http.StatusForbidden)
-		return
-	}
 	vars := mux.Vars(r)
 	taskID := vars["taskID"]
+	if !h.authorizeTask(w, r, taskID, projectroles.Maintainer, []string{"manager"}) {
+		return
+	}
 	memberIDStr := vars["memberID"]
 
 	// Konvertuj memberID u ObjectID
@@ -267,7 +365,7 @@ func (h *TaskHandler) RemoveMemberFromTaskHandler(w http.ResponseWriter, r *http
 	}
 
 	// Pozivamo servis za uklanjanje člana
-	err = h.service.RemoveMemberFromTask(taskID, memberID)
+	err = h.service.RemoveMemberFromTask(taskID, memberID, actorFromRequest(r))
 	if err != nil {
 		logging.Logger.Errorf("Event ID: TASK_REMOVE_MEMBER_SERVICE_ERROR, Description: Failed to remove member %s from task %s: %v", memberIDStr, taskID, err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -280,14 +378,13 @@ func (h *TaskHandler) RemoveMemberFromTaskHandler(w http.ResponseWriter, r *http
 }
 func (h *TaskHandler) DeleteTasksByProjectHandler(w http.ResponseWriter, r *http.Request) {
 	// Provera uloge korisnika
-	if err := checkRole(r, []string{"manager"}); err != nil {
-		http.Error(w, "Access forbidden: insufficient permissions", http.StatusForbidden)
-		return
-	}
 
 	// Ekstrakcija projectId iz URL-a
 	vars := mux.Vars(r)
 	projectID := vars["projectId"]
+	if !h.authorizeProject(w, r, projectID, projectroles.Owner, []string{"manager"}) {
+		return
+	}
 
 	// Pozivanje servisa za brisanje zadataka
 	err := h.service.DeleteTasksByProject(projectID)
@@ -302,6 +399,45 @@ func (h *TaskHandler) DeleteTasksByProjectHandler(w http.ResponseWriter, r *http
 	json.NewEncoder(w).Encode(map[string]string{"message": "Tasks deleted successfully"})
 }
 
+// RestoreTasksByProjectHandler vraća zadatke obrisane zajedno sa projektom; koristi ga
+// projects-service kao kompenzaciju kada brisanje projekta ne uspe.
+func (h *TaskHandler) RestoreTasksByProjectHandler(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["projectId"]
+	if !h.authorizeProject(w, r, projectID, projectroles.Owner, []string{"manager"}) {
+		return
+	}
+
+	restored, err := h.service.RestoreTasksByProject(r.Context(), projectID)
+	if err != nil {
+		logging.Logger.Errorf("Event ID: TASKS_RESTORE_BY_PROJECT_SERVICE_ERROR, Description: Failed to restore tasks for project ID %s: %v", projectID, err)
+		http.Error(w, "Failed to restore tasks", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]int64{"restored": restored})
+}
+
+// GetMemberDueTasksHandler vraća zadatke sa rokom dodeljene korisniku; poziva ga samo
+// projects-service za kalendarski feed.
+func (h *TaskHandler) GetMemberDueTasksHandler(w http.ResponseWriter, r *http.Request) {
+	if err := checkRole(r, []string{"manager"}); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	username := mux.Vars(r)["username"]
+
+	tasks, err := h.service.GetMemberDueTasks(r.Context(), username)
+	if err != nil {
+		logging.Logger.Errorf("Event ID: MEMBER_DUE_TASKS_SERVICE_ERROR, Description: Failed to fetch due tasks for %s: %v", username, err)
+		http.Error(w, "Failed to fetch tasks", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tasks)
+}
+
 func (h *TaskHandler) HasActiveTasksHandler(w http.ResponseWriter, r *http.Request) {
 	projectID := r.URL.Query().Get("projectId")
 	memberID := r.URL.Query().Get("memberId")
@@ -369,3 +505,1088 @@ func (h *TaskHandler) RemoveUserFromAllTasksByUsername(w http.ResponseWriter, r
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("User removed from all tasks successfully"))
 }
+
+// MoveTaskHandler menja poziciju zadatka na tabli (unutar kolone ili u drugu kolonu)
+func (h *TaskHandler) MoveTaskHandler(w http.ResponseWriter, r *http.Request) {
+	taskID := mux.Vars(r)["taskID"]
+	role, ok := h.taskRole(w, r, taskID, projectroles.Contributor, []string{"manager", "member"})
+	if !ok {
+		return
+	}
+
+	taskObjectID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		logging.Logger.Warnf("Event ID: TASK_MOVE_INVALID_ID, Description: Invalid task ID format for move: %v", err)
+		http.Error(w, "Invalid task ID format", http.StatusBadRequest)
+		return
+	}
+
+	var request struct {
+		Status         models.TaskStatus `json:"status"`
+		PreviousTaskID string            `json:"previousTaskId"`
+		NextTaskID     string            `json:"nextTaskId"`
+		Username       string            `json:"username"`
+		OverrideWIP    bool              `json:"overrideWip"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		logging.Logger.Errorf("Event ID: TASK_MOVE_DECODE_ERROR, Description: Invalid request payload for moving task %s: %v", taskID, err)
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	if hasUserToken(r) {
+		actor, err := tokenActor(r)
+		if err != nil {
+			http.Error(w, "Failed to identify user from token", http.StatusUnauthorized)
+			return
+		}
+		request.Username = actor
+	}
+
+	movedTask, err := h.service.MoveTask(taskObjectID, request.Status, request.PreviousTaskID, request.NextTaskID, request.Username, role, request.OverrideWIP)
+	if err != nil {
+		logging.Logger.Errorf("Event ID: TASK_MOVE_SERVICE_ERROR, Description: Failed to move task %s: %v", taskID, err)
+		if strings.Contains(err.Error(), "WIP limit reached") {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	logging.Logger.Infof("Event ID: TASK_MOVE_SUCCESS, Description: Task %s moved to '%s' (rank %f).", taskID, movedTask.Status, movedTask.Rank)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(movedTask)
+}
+
+func (h *TaskHandler) GetWIPLimitsHandler(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["projectId"]
+	if !h.authorizeProject(w, r, projectID, projectroles.Viewer, []string{"manager", "member"}) {
+		return
+	}
+
+	settings, err := h.service.GetProjectSettings(r.Context(), projectID)
+	if err != nil {
+		logging.Logger.Errorf("Event ID: WIP_LIMITS_GET_SERVICE_ERROR, Description: Failed to get WIP limits for project %s: %v", projectID, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings.WIPLimits)
+}
+
+func (h *TaskHandler) SetWIPLimitsHandler(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["projectId"]
+	if !h.authorizeProject(w, r, projectID, projectroles.Maintainer, []string{"manager"}) {
+		return
+	}
+
+	var limits map[string]int
+	if err := json.NewDecoder(r.Body).Decode(&limits); err != nil {
+		logging.Logger.Errorf("Event ID: WIP_LIMITS_DECODE_ERROR, Description: Invalid WIP limits payload for project %s: %v", projectID, err)
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	settings, err := h.service.SetWIPLimits(r.Context(), projectID, limits)
+	if err != nil {
+		logging.Logger.Errorf("Event ID: WIP_LIMITS_SET_SERVICE_ERROR, Description: Failed to set WIP limits for project %s: %v", projectID, err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	logging.Logger.Infof("Event ID: WIP_LIMITS_SET_SUCCESS, Description: WIP limits updated for project %s.", projectID)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings.WIPLimits)
+}
+
+// UpdateTaskHandler menja naslov i opis zadatka
+func (h *TaskHandler) UpdateTaskHandler(w http.ResponseWriter, r *http.Request) {
+	taskID := mux.Vars(r)["taskID"]
+	if !h.authorizeTask(w, r, taskID, projectroles.Maintainer, []string{"manager"}) {
+		return
+	}
+
+	taskObjectID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		logging.Logger.Warnf("Event ID: TASK_UPDATE_INVALID_ID, Description: Invalid task ID format for update: %v", err)
+		http.Error(w, "Invalid task ID format", http.StatusBadRequest)
+		return
+	}
+
+	var request struct {
+		Title       *string `json:"title"`
+		Description *string `json:"description"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		logging.Logger.Errorf("Event ID: TASK_UPDATE_DECODE_ERROR, Description: Invalid request payload for updating task %s: %v", taskID, err)
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	updatedTask, err := h.service.UpdateTask(r.Context(), taskObjectID, request.Title, request.Description, actorFromRequest(r))
+	if err != nil {
+		logging.Logger.Errorf("Event ID: TASK_UPDATE_SERVICE_ERROR, Description: Failed to update task %s: %v", taskID, err)
+		if err.Error() == "task not found" {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	logging.Logger.Infof("Event ID: TASK_UPDATE_SUCCESS, Description: Task %s updated successfully.", taskID)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updatedTask)
+}
+
+// GetTaskHistoryHandler vraća istoriju izmena zadatka
+func (h *TaskHandler) GetTaskHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	taskID := mux.Vars(r)["taskID"]
+	if !h.authorizeTask(w, r, taskID, projectroles.Viewer, []string{"manager", "member"}) {
+		return
+	}
+
+	taskObjectID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		logging.Logger.Warnf("Event ID: TASK_HISTORY_INVALID_ID, Description: Invalid task ID format for history: %v", err)
+		http.Error(w, "Invalid task ID format", http.StatusBadRequest)
+		return
+	}
+
+	history, err := h.service.GetTaskHistory(r.Context(), taskObjectID)
+	if err != nil {
+		logging.Logger.Errorf("Event ID: TASK_HISTORY_SERVICE_ERROR, Description: Failed to get history for task %s: %v", taskID, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
+
+// GetProjectStatusHistoryHandler vraća istoriju statusa svih zadataka projekta
+func (h *TaskHandler) GetProjectStatusHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["projectId"]
+	if !h.authorizeProject(w, r, projectID, projectroles.Viewer, []string{"manager"}) {
+		return
+	}
+
+	history, err := h.service.GetProjectStatusHistory(r.Context(), projectID)
+	if err != nil {
+		logging.Logger.Errorf("Event ID: PROJECT_STATUS_HISTORY_SERVICE_ERROR, Description: Failed to get status history for project %s: %v", projectID, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
+
+// WatchTaskHandler dodaje pozivaoca među posmatrače zadatka
+func (h *TaskHandler) WatchTaskHandler(w http.ResponseWriter, r *http.Request) {
+	taskID := mux.Vars(r)["taskID"]
+	if !h.authorizeTask(w, r, taskID, projectroles.Viewer, []string{"manager", "member"}) {
+		return
+	}
+
+	taskObjectID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		logging.Logger.Warnf("Event ID: TASK_WATCH_INVALID_ID, Description: Invalid task ID format for watch: %v", err)
+		http.Error(w, "Invalid task ID format", http.StatusBadRequest)
+		return
+	}
+
+	var watcher models.Member
+	if err := json.NewDecoder(r.Body).Decode(&watcher); err != nil {
+		logging.Logger.Errorf("Event ID: TASK_WATCH_DECODE_ERROR, Description: Invalid watcher payload for task %s: %v", taskID, err)
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	// Korisnik može da prati zadatak samo u svoje ime
+	if actor := actorFromRequest(r); actor != "" && actor != watcher.Username {
+		logging.Logger.Warnf("Event ID: TASK_WATCH_FORBIDDEN, Description: User %s attempted to add %s as watcher of task %s.", actor, watcher.Username, taskID)
+		http.Error(w, "You can only watch tasks on your own behalf", http.StatusForbidden)
+		return
+	}
+
+	task, err := h.service.WatchTask(r.Context(), taskObjectID, watcher)
+	if err != nil {
+		logging.Logger.Errorf("Event ID: TASK_WATCH_SERVICE_ERROR, Description: Failed to watch task %s: %v", taskID, err)
+		if err.Error() == "task not found" {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(task.Watchers)
+}
+
+// UnwatchTaskHandler uklanja pozivaoca iz posmatrača zadatka
+func (h *TaskHandler) UnwatchTaskHandler(w http.ResponseWriter, r *http.Request) {
+	taskID := mux.Vars(r)["taskID"]
+	if !h.authorizeTask(w, r, taskID, projectroles.Viewer, []string{"manager", "member"}) {
+		return
+	}
+
+	taskObjectID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		logging.Logger.Warnf("Event ID: TASK_UNWATCH_INVALID_ID, Description: Invalid task ID format for unwatch: %v", err)
+		http.Error(w, "Invalid task ID format", http.StatusBadRequest)
+		return
+	}
+
+	username := actorFromRequest(r)
+	if username == "" {
+		http.Error(w, "Authorization token required", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.service.UnwatchTask(r.Context(), taskObjectID, username); err != nil {
+		logging.Logger.Errorf("Event ID: TASK_UNWATCH_SERVICE_ERROR, Description: Failed to unwatch task %s for %s: %v", taskID, username, err)
+		if err.Error() == "task not found" {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"message": "Task unwatched successfully"}`))
+}
+
+// GetWatchersHandler vraća posmatrače zadatka
+func (h *TaskHandler) GetWatchersHandler(w http.ResponseWriter, r *http.Request) {
+	taskID := mux.Vars(r)["taskID"]
+	if !h.authorizeTask(w, r, taskID, projectroles.Viewer, []string{"manager", "member"}) {
+		return
+	}
+
+	taskObjectID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		http.Error(w, "Invalid task ID format", http.StatusBadRequest)
+		return
+	}
+
+	task, err := h.service.GetTaskByID(taskObjectID)
+	if err != nil {
+		http.Error(w, "Task not found", http.StatusNotFound)
+		return
+	}
+
+	watchers := task.Watchers
+	if watchers == nil {
+		watchers = []models.Member{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(watchers)
+}
+
+// GetTaskAssignmentHandler vraća odgovornog člana i saradnike na zadatku
+func (h *TaskHandler) GetTaskAssignmentHandler(w http.ResponseWriter, r *http.Request) {
+	taskID := mux.Vars(r)["taskID"]
+	if !h.authorizeTask(w, r, taskID, projectroles.Viewer, []string{"manager", "member"}) {
+		return
+	}
+
+	taskObjectID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		http.Error(w, "Invalid task ID format", http.StatusBadRequest)
+		return
+	}
+
+	assignment, err := h.service.GetTaskAssignment(r.Context(), taskObjectID)
+	if err != nil {
+		logging.Logger.Errorf("Event ID: TASK_ASSIGNMENT_SERVICE_ERROR, Description: Failed to get assignment for task %s: %v", taskID, err)
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(assignment)
+}
+
+// SetResponsibleMemberHandler postavlja odgovornog člana zadatka
+func (h *TaskHandler) SetResponsibleMemberHandler(w http.ResponseWriter, r *http.Request) {
+	taskID := mux.Vars(r)["taskID"]
+	if !h.authorizeTask(w, r, taskID, projectroles.Maintainer, []string{"manager"}) {
+		return
+	}
+
+	taskObjectID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		logging.Logger.Warnf("Event ID: TASK_ASSIGNMENT_INVALID_ID, Description: Invalid task ID format for assignment: %v", err)
+		http.Error(w, "Invalid task ID format", http.StatusBadRequest)
+		return
+	}
+
+	var request struct {
+		ResponsibleID string `json:"responsibleId"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		logging.Logger.Errorf("Event ID: TASK_ASSIGNMENT_DECODE_ERROR, Description: Invalid assignment payload for task %s: %v", taskID, err)
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	memberObjectID, err := primitive.ObjectIDFromHex(request.ResponsibleID)
+	if err != nil {
+		http.Error(w, "Invalid member ID format", http.StatusBadRequest)
+		return
+	}
+
+	assignment, err := h.service.SetResponsibleMember(r.Context(), taskObjectID, memberObjectID, actorFromRequest(r))
+	if err != nil {
+		logging.Logger.Errorf("Event ID: TASK_ASSIGNMENT_SERVICE_ERROR, Description: Failed to set responsible member for task %s: %v", taskID, err)
+		if err.Error() == "task not found" {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(assignment)
+}
+
+// SetTaskRecurrenceHandler postavlja rok i pravilo ponavljanja zadatka
+func (h *TaskHandler) SetTaskRecurrenceHandler(w http.ResponseWriter, r *http.Request) {
+	taskID := mux.Vars(r)["taskID"]
+	if !h.authorizeTask(w, r, taskID, projectroles.Maintainer, []string{"manager"}) {
+		return
+	}
+
+	taskObjectID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		http.Error(w, "Invalid task ID format", http.StatusBadRequest)
+		return
+	}
+
+	var request struct {
+		DueDate    *time.Time         `json:"dueDate"`
+		Recurrence *models.Recurrence `json:"recurrence"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		logging.Logger.Errorf("Event ID: TASK_RECURRENCE_DECODE_ERROR, Description: Invalid recurrence payload for task %s: %v", taskID, err)
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	if request.Recurrence == nil {
+		http.Error(w, "Recurrence is required", http.StatusBadRequest)
+		return
+	}
+
+	task, err := h.service.SetTaskRecurrence(r.Context(), taskObjectID, request.DueDate, request.Recurrence)
+	if err != nil {
+		logging.Logger.Errorf("Event ID: TASK_RECURRENCE_SERVICE_ERROR, Description: Failed to set recurrence for task %s: %v", taskID, err)
+		if err.Error() == "task not found" {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(task)
+}
+
+// StopTaskRecurrenceHandler uklanja pravilo ponavljanja, pa serija staje na ovom zadatku
+func (h *TaskHandler) StopTaskRecurrenceHandler(w http.ResponseWriter, r *http.Request) {
+	taskID := mux.Vars(r)["taskID"]
+	if !h.authorizeTask(w, r, taskID, projectroles.Maintainer, []string{"manager"}) {
+		return
+	}
+
+	taskObjectID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		http.Error(w, "Invalid task ID format", http.StatusBadRequest)
+		return
+	}
+
+	task, err := h.service.SetTaskRecurrence(r.Context(), taskObjectID, nil, nil)
+	if err != nil {
+		logging.Logger.Errorf("Event ID: TASK_RECURRENCE_SERVICE_ERROR, Description: Failed to stop recurrence for task %s: %v", taskID, err)
+		if err.Error() == "task not found" {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(task)
+}
+
+// BulkTasksHandler primenjuje jednu akciju na više zadataka. Članovi mogu samo da
+// menjaju status; ostale akcije su dozvoljene menadžeru.
+func (h *TaskHandler) BulkTasksHandler(w http.ResponseWriter, r *http.Request) {
+	if err := checkRole(r, []string{"manager", "member"}); err != nil {
+		http.Error(w, "Access forbidden: insufficient permissions", http.StatusForbidden)
+		return
+	}
+
+	var request services.BulkTaskRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		logging.Logger.Errorf("Event ID: TASKS_BULK_DECODE_ERROR, Description: Invalid bulk request payload: %v", err)
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	serviceRoles := []string{"manager"}
+	minRole := projectroles.Maintainer
+	if request.Action == services.BulkActionStatus {
+		serviceRoles = []string{"manager", "member"}
+		minRole = projectroles.Contributor
+	}
+	var role projectroles.Role
+	if !hasUserToken(r) {
+		var ok bool
+		if role, ok = h.projectRole(w, r, "", minRole, serviceRoles); !ok {
+			return
+		}
+	} else {
+		// Pozivalac mora imati potrebnu ulogu na svakom projektu iz zahteva; za celu
+		// akciju važi najniža od tih uloga
+		projectIDs, err := h.service.ProjectIDsOfTasks(r.Context(), request.TaskIDs)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		role = projectroles.Owner
+		for _, projectID := range projectIDs {
+			projectRole, ok := h.projectRole(w, r, projectID, minRole, serviceRoles)
+			if !ok {
+				return
+			}
+			if !projectRole.AtLeast(role) {
+				role = projectRole
+			}
+		}
+	}
+
+	results, err := h.service.BulkUpdateTasks(r.Context(), request, actorFromRequest(r), role)
+	if err != nil {
+		logging.Logger.Warnf("Event ID: TASKS_BULK_INVALID, Description: Rejected bulk request: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	succeeded := 0
+	for _, result := range results {
+		if result.Success {
+			succeeded++
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"results":   results,
+		"succeeded": succeeded,
+		"failed":    len(results) - succeeded,
+	})
+}
+
+// DeleteTaskHandler briše zadatak
+func (h *TaskHandler) DeleteTaskHandler(w http.ResponseWriter, r *http.Request) {
+	taskID := mux.Vars(r)["taskID"]
+	if !h.authorizeTask(w, r, taskID, projectroles.Maintainer, []string{"manager"}) {
+		return
+	}
+
+	taskObjectID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		http.Error(w, "Invalid task ID format", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.DeleteTask(r.Context(), taskObjectID, actorFromRequest(r)); err != nil {
+		logging.Logger.Errorf("Event ID: TASK_DELETE_SERVICE_ERROR, Description: Failed to delete task %s: %v", taskID, err)
+		if err.Error() == "task not found" {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"message": "Task deleted successfully"}`))
+}
+
+// ImportTasksHandler uvozi zadatke u projekat (koristi ga projects-service pri uvozu table)
+func (h *TaskHandler) ImportTasksHandler(w http.ResponseWriter, r *http.Request) {
+
+	var request struct {
+		ProjectID string                  `json:"projectId"`
+		Actor     string                  `json:"actor"`
+		Tasks     []services.ImportedTask `json:"tasks"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		logging.Logger.Errorf("Event ID: TASK_IMPORT_DECODE_ERROR, Description: Invalid import payload: %v", err)
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	if !h.authorizeProject(w, r, request.ProjectID, projectroles.Maintainer, []string{"manager"}) {
+		return
+	}
+
+	actor := actorFromRequest(r)
+	if actor == "" {
+		actor = request.Actor
+	}
+
+	results, err := h.service.ImportTasks(r.Context(), request.ProjectID, request.Tasks, actor)
+	if err != nil {
+		logging.Logger.Errorf("Event ID: TASK_IMPORT_SERVICE_ERROR, Description: Failed to import tasks into project %s: %v", request.ProjectID, err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(results)
+}
+
+// CloneProjectTasksHandler kopira zadatke projekta u novi projekat; poziva ga
+// projects-service pri kloniranju projekta i vraća mapu originala na kopije.
+func (h *TaskHandler) CloneProjectTasksHandler(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["projectId"]
+
+	var request services.ProjectCloneRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	if !h.authorizeProject(w, r, request.TargetProjectID, projectroles.Maintainer, []string{"manager"}) {
+		return
+	}
+	// Izvorni projekat se samo čita, pa je dovoljna uloga viewer i sme biti arhiviran
+	if hasUserToken(r) {
+		if role, err := h.roles.Require(projectID, r.Header.Get("Authorization"), projectroles.Viewer); err != nil {
+			writeProjectAccessError(w, r, projectID, role, projectroles.Viewer, err)
+			return
+		}
+	}
+	if actor := actorFromRequest(r); actor != "" {
+		request.Actor = actor
+	}
+
+	cloned, err := h.service.CloneProjectTasks(r.Context(), projectID, request)
+	if err != nil {
+		logging.Logger.Errorf("Event ID: PROJECT_TASKS_CLONE_FAILED, Description: Failed to clone tasks of project %s: %v", projectID, err)
+		if strings.HasPrefix(err.Error(), "failed") {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		} else {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(cloned)
+}
+
+// DiscardClonedTasksHandler trajno briše kopije zadataka; poziva ga projects-service
+// kada kloniranje projekta ne uspe.
+func (h *TaskHandler) DiscardClonedTasksHandler(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["projectId"]
+	if !h.authorizeProject(w, r, projectID, projectroles.Owner, []string{"manager"}) {
+		return
+	}
+
+	deleted, err := h.service.DiscardClonedTasks(r.Context(), projectID)
+	if err != nil {
+		logging.Logger.Errorf("Event ID: CLONED_TASKS_DISCARD_FAILED, Description: Failed to discard cloned tasks of project %s: %v", projectID, err)
+		if strings.HasPrefix(err.Error(), "failed") {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		} else {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]int64{"deleted": deleted})
+}
+
+// exportFlushEvery određuje koliko redova se upisuje pre slanja klijentu
+const exportFlushEvery = 100
+
+// csvSafe sprečava da spreadsheet programi vrednost iz ćelije protumače kao formulu
+func csvSafe(record []string) []string {
+	for i, value := range record {
+		if value != "" && strings.ContainsRune("=+-@", rune(value[0])) {
+			record[i] = "'" + value
+		}
+	}
+	return record
+}
+
+// ExportProjectTasksHandler izvozi zadatke projekta kao CSV, JSON ili XLSX. Odgovor se
+// šalje u delovima dok se zadaci čitaju iz baze.
+func (h *TaskHandler) ExportProjectTasksHandler(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["projectId"]
+	if !h.authorizeProject(w, r, projectID, projectroles.Viewer, []string{"manager", "member"}) {
+		return
+	}
+	if _, err := primitive.ObjectIDFromHex(projectID); err != nil {
+		http.Error(w, "Invalid project ID format", http.StatusBadRequest)
+		return
+	}
+
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if format == "" {
+		format = "csv"
+	}
+
+	flusher, _ := w.(http.Flusher)
+	flush := func() {
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+	filename := fmt.Sprintf("project-%s-tasks.%s", projectID, format)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	var emit func(models.TaskExportRow) error
+	var finish func() error
+	rows := 0
+
+	switch format {
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		writer := csv.NewWriter(w)
+		if err := writer.Write(models.TaskExportColumns); err != nil {
+			return
+		}
+		emit = func(row models.TaskExportRow) error {
+			if err := writer.Write(csvSafe(row.Record())); err != nil {
+				return err
+			}
+			if rows++; rows%exportFlushEvery == 0 {
+				writer.Flush()
+				flush()
+			}
+			return writer.Error()
+		}
+		finish = func() error {
+			writer.Flush()
+			return writer.Error()
+		}
+	case "json":
+		w.Header().Set("Content-Type", "application/json")
+		encoder := json.NewEncoder(w)
+		if _, err := w.Write([]byte("[")); err != nil {
+			return
+		}
+		emit = func(row models.TaskExportRow) error {
+			if rows > 0 {
+				if _, err := w.Write([]byte(",")); err != nil {
+					return err
+				}
+			}
+			if rows++; rows%exportFlushEvery == 0 {
+				flush()
+			}
+			return encoder.Encode(row)
+		}
+		finish = func() error {
+			_, err := w.Write([]byte("]\n"))
+			return err
+		}
+	case "xlsx":
+		w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+		writer, err := utils.NewXLSXWriter(w, "Tasks")
+		if err != nil {
+			return
+		}
+		if err := writer.WriteRow(models.TaskExportColumns); err != nil {
+			return
+		}
+		emit = func(row models.TaskExportRow) error {
+			if err := writer.WriteRow(row.Record()); err != nil {
+				return err
+			}
+			if rows++; rows%exportFlushEvery == 0 {
+				if err := writer.Flush(); err != nil {
+					return err
+				}
+				flush()
+			}
+			return nil
+		}
+		finish = writer.Close
+	default:
+		w.Header().Del("Content-Disposition")
+		http.Error(w, "Unsupported export format; use csv, json or xlsx", http.StatusBadRequest)
+		return
+	}
+
+	// Zaglavlja su već poslata, pa se greške tokom izvoza samo loguju
+	if err := h.service.ExportProjectTasks(r.Context(), projectID, emit); err != nil {
+		logging.Logger.Errorf("Event ID: TASKS_EXPORT_FAILED, Description: Export of project %s aborted after %d rows: %v", projectID, rows, err)
+		return
+	}
+	if err := finish(); err != nil {
+		logging.Logger.Errorf("Event ID: TASKS_EXPORT_FAILED, Description: Failed to finish export of project %s: %v", projectID, err)
+		return
+	}
+	flush()
+}
+
+// ArchiveTaskHandler arhivira zadatak
+func (h *TaskHandler) ArchiveTaskHandler(w http.ResponseWriter, r *http.Request) {
+	taskID := mux.Vars(r)["taskID"]
+	if !h.authorizeTask(w, r, taskID, projectroles.Maintainer, []string{"manager"}) {
+		return
+	}
+
+	taskObjectID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		http.Error(w, "Invalid task ID format", http.StatusBadRequest)
+		return
+	}
+
+	task, err := h.service.ArchiveTask(r.Context(), taskObjectID, actorFromRequest(r))
+	if err != nil {
+		logging.Logger.Errorf("Event ID: TASK_ARCHIVE_SERVICE_ERROR, Description: Failed to archive task %s: %v", taskID, err)
+		writeArchiveError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(task)
+}
+
+// RestoreTaskHandler vraća arhiviran ili obrisan zadatak
+func (h *TaskHandler) RestoreTaskHandler(w http.ResponseWriter, r *http.Request) {
+	taskID := mux.Vars(r)["taskID"]
+	if !h.authorizeTask(w, r, taskID, projectroles.Maintainer, []string{"manager"}) {
+		return
+	}
+
+	taskObjectID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		http.Error(w, "Invalid task ID format", http.StatusBadRequest)
+		return
+	}
+
+	task, err := h.service.RestoreTask(r.Context(), taskObjectID, actorFromRequest(r))
+	if err != nil {
+		logging.Logger.Errorf("Event ID: TASK_RESTORE_SERVICE_ERROR, Description: Failed to restore task %s: %v", taskID, err)
+		writeArchiveError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(task)
+}
+
+// GetArchivedTasksHandler vraća arhivirane (i uz ?includeDeleted=true obrisane) zadatke projekta
+func (h *TaskHandler) GetArchivedTasksHandler(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["projectId"]
+	if !h.authorizeProject(w, r, projectID, projectroles.Maintainer, []string{"manager"}) {
+		return
+	}
+	includeDeleted := r.URL.Query().Get("includeDeleted") == "true"
+
+	tasks, err := h.service.GetArchivedTasks(r.Context(), projectID, includeDeleted)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tasks)
+}
+
+func writeArchiveError(w http.ResponseWriter, err error) {
+	switch {
+	case err.Error() == "task not found":
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case err.Error() == "task is archived", err.Error() == "task is deleted", err.Error() == "task is not archived or deleted",
+		strings.Contains(err.Error(), "WIP limit"):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// TransferTaskHandler premešta ili kopira zadatak u drugi projekat i vraća izveštaj
+func (h *TaskHandler) TransferTaskHandler(w http.ResponseWriter, r *http.Request) {
+	taskID := mux.Vars(r)["taskID"]
+	if !h.authorizeTask(w, r, taskID, projectroles.Maintainer, []string{"manager"}) {
+		return
+	}
+
+	taskObjectID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		http.Error(w, "Invalid task ID format", http.StatusBadRequest)
+		return
+	}
+
+	var request services.TaskTransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		logging.Logger.Errorf("Event ID: TASK_TRANSFER_DECODE_ERROR, Description: Invalid request payload for transferring task %s: %v", taskID, err)
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	// Zadatak se prenosi samo u projekat kojim pozivalac takođe upravlja
+	if !h.authorizeProject(w, r, request.TargetProjectID, projectroles.Maintainer, []string{"manager"}) {
+		return
+	}
+
+	report, err := h.service.TransferTask(r.Context(), taskObjectID, request, actorFromRequest(r))
+	if err != nil {
+		logging.Logger.Errorf("Event ID: TASK_TRANSFER_SERVICE_ERROR, Description: Failed to transfer task %s: %v", taskID, err)
+		switch {
+		case strings.HasPrefix(err.Error(), "invalid"), strings.HasPrefix(err.Error(), "unsupported"),
+			err.Error() == "task already belongs to the target project":
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			writeArchiveError(w, err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// ListTemplatesHandler vraća šablone projekta (?projectId=) i globalne šablone
+func (h *TaskHandler) ListTemplatesHandler(w http.ResponseWriter, r *http.Request) {
+	projectID := r.URL.Query().Get("projectId")
+	if projectID != "" {
+		if !h.authorizeProject(w, r, projectID, projectroles.Viewer, []string{"manager", "member"}) {
+			return
+		}
+	} else if err := checkRole(r, []string{"manager", "member"}); err != nil {
+		http.Error(w, "Access forbidden: insufficient permissions", http.StatusForbidden)
+		return
+	}
+
+	templates, err := h.service.ListTemplates(r.Context(), projectID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(templates)
+}
+
+// CreateTemplateHandler kreira šablon; bez projectId šablon je globalan
+func (h *TaskHandler) CreateTemplateHandler(w http.ResponseWriter, r *http.Request) {
+	var template models.TaskTemplate
+	if err := json.NewDecoder(r.Body).Decode(&template); err != nil {
+		logging.Logger.Errorf("Event ID: TASK_TEMPLATE_DECODE_ERROR, Description: Invalid request payload for template: %v", err)
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	if !h.authorizeTemplate(w, r, &template, projectroles.Maintainer, []string{"manager"}) {
+		return
+	}
+
+	created, err := h.service.CreateTemplate(r.Context(), template, actorFromRequest(r))
+	if err != nil {
+		writeTemplateError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+func (h *TaskHandler) GetTemplateHandler(w http.ResponseWriter, r *http.Request) {
+	templateID, err := primitive.ObjectIDFromHex(mux.Vars(r)["templateId"])
+	if err != nil {
+		http.Error(w, "Invalid template ID format", http.StatusBadRequest)
+		return
+	}
+
+	template, err := h.service.GetTemplate(r.Context(), templateID)
+	if err != nil {
+		writeTemplateError(w, err)
+		return
+	}
+	if !h.authorizeTemplate(w, r, template, projectroles.Viewer, []string{"manager", "member"}) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(template)
+}
+
+func (h *TaskHandler) UpdateTemplateHandler(w http.ResponseWriter, r *http.Request) {
+	templateID, err := primitive.ObjectIDFromHex(mux.Vars(r)["templateId"])
+	if err != nil {
+		http.Error(w, "Invalid template ID format", http.StatusBadRequest)
+		return
+	}
+	existing, err := h.service.GetTemplate(r.Context(), templateID)
+	if err != nil {
+		writeTemplateError(w, err)
+		return
+	}
+	if !h.authorizeTemplate(w, r, existing, projectroles.Maintainer, []string{"manager"}) {
+		return
+	}
+
+	var template models.TaskTemplate
+	if err := json.NewDecoder(r.Body).Decode(&template); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	updated, err := h.service.UpdateTemplate(r.Context(), templateID, template)
+	if err != nil {
+		writeTemplateError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+func (h *TaskHandler) DeleteTemplateHandler(w http.ResponseWriter, r *http.Request) {
+	templateID, err := primitive.ObjectIDFromHex(mux.Vars(r)["templateId"])
+	if err != nil {
+		http.Error(w, "Invalid template ID format", http.StatusBadRequest)
+		return
+	}
+	existing, err := h.service.GetTemplate(r.Context(), templateID)
+	if err != nil {
+		writeTemplateError(w, err)
+		return
+	}
+	if !h.authorizeTemplate(w, r, existing, projectroles.Maintainer, []string{"manager"}) {
+		return
+	}
+
+	if err := h.service.DeleteTemplate(r.Context(), templateID); err != nil {
+		writeTemplateError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"message": "Template deleted successfully"}`))
+}
+
+func writeTemplateError(w http.ResponseWriter, err error) {
+	switch {
+	case err.Error() == "template not found":
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case err.Error() == "template with this name already exists":
+		http.Error(w, err.Error(), http.StatusConflict)
+	case strings.HasPrefix(err.Error(), "failed to"), strings.HasPrefix(err.Error(), "task created but"):
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	default:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	}
+}
+
+// SetTaskSprintHandler dodeljuje zadatak sprintu i postavlja story poene
+func (h *TaskHandler) SetTaskSprintHandler(w http.ResponseWriter, r *http.Request) {
+	taskID := mux.Vars(r)["taskID"]
+	if !h.authorizeTask(w, r, taskID, projectroles.Maintainer, []string{"manager"}) {
+		return
+	}
+
+	taskObjectID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		http.Error(w, "Invalid task ID format", http.StatusBadRequest)
+		return
+	}
+
+	var request struct {
+		SprintID    *string `json:"sprintId"`
+		StoryPoints *int    `json:"storyPoints"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	task, err := h.service.SetTaskSprint(r.Context(), taskObjectID, request.SprintID, request.StoryPoints, actorFromRequest(r))
+	if err != nil {
+		logging.Logger.Errorf("Event ID: TASK_SPRINT_SERVICE_ERROR, Description: Failed to update sprint of task %s: %v", taskID, err)
+		switch {
+		case err.Error() == "sprint not found in project", err.Error() == "story points must not be negative":
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case err.Error() == "cannot assign task to a closed sprint":
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			writeArchiveError(w, err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(task)
+}
+
+// GetSprintTasksHandler vraća zadatke sprinta
+func (h *TaskHandler) GetSprintTasksHandler(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeSprint(w, r, mux.Vars(r)["sprintId"], projectroles.Viewer, []string{"manager", "member"}) {
+		return
+	}
+
+	tasks, err := h.service.GetSprintTasks(r.Context(), mux.Vars(r)["sprintId"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tasks)
+}
+
+// GetSprintSummaryHandler vraća broj zadataka i poena sprinta
+func (h *TaskHandler) GetSprintSummaryHandler(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeSprint(w, r, mux.Vars(r)["sprintId"], projectroles.Viewer, []string{"manager", "member"}) {
+		return
+	}
+
+	summary, err := h.service.GetSprintSummary(r.Context(), mux.Vars(r)["sprintId"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(summary)
+}
+
+// RolloverSprintHandler prebacuje nezavršene zadatke sprinta (poziva ga projects-service pri zatvaranju sprinta)
+func (h *TaskHandler) RolloverSprintHandler(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeSprint(w, r, mux.Vars(r)["sprintId"], projectroles.Maintainer, []string{"manager"}) {
+		return
+	}
+	sprintID := mux.Vars(r)["sprintId"]
+
+	var request struct {
+		TargetSprintID string `json:"targetSprintId"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	summary, err := h.service.RolloverSprint(r.Context(), sprintID, request.TargetSprintID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(summary)
+}
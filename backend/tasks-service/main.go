@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strconv"
 
 	// Ostavljamo ga zasad, ali nećemo ga koristiti za logovanje aplikacije
 	"net/http"
@@ -13,10 +14,13 @@ import (
 	"trello-project/microservices/tasks-service/services"
 
 	http_client "trello-project/backend/utils"
+	"trello-project/backend/utils/events"
+	"trello-project/backend/utils/projectroles"
 
 	"github.com/gorilla/mux"
 	"github.com/joho/godotenv"
 	"github.com/sony/gobreaker"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -35,6 +39,29 @@ func enableCORS(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
+func createHistoryIndex(collection *mongo.Collection) error {
+	indexModel := mongo.IndexModel{
+		Keys: bson.D{{Key: "taskId", Value: 1}, {Key: "timestamp", Value: 1}},
+	}
+	if _, err := collection.Indexes().CreateOne(context.TODO(), indexModel); err != nil {
+		return fmt.Errorf("failed to create index on task history: %v", err)
+	}
+	logging.Logger.Info("Event ID: DB_INDEX_CREATED, Description: Index on task history created successfully")
+	return nil
+}
+
+func createTemplateIndex(collection *mongo.Collection) error {
+	indexModel := mongo.IndexModel{
+		Keys:    bson.D{{Key: "projectId", Value: 1}, {Key: "name", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+	if _, err := collection.Indexes().CreateOne(context.TODO(), indexModel); err != nil {
+		return fmt.Errorf("failed to create index on task templates: %v", err)
+	}
+	logging.Logger.Info("Event ID: DB_INDEX_CREATED, Description: Unique index on task templates created successfully")
+	return nil
+}
+
 func main() {
 	logging.InitLogger() // Inicijalizacija logovanja
 
@@ -64,6 +91,15 @@ func main() {
 
 	tasksCollection := tasksClient.Database(mongoDBName).Collection(mongoCollectionName)
 	logging.Logger.Infof("Event ID: DB_COLLECTION_SET, Description: Using MongoDB collection: %s/%s", mongoDBName, mongoCollectionName)
+	settingsCollection := tasksClient.Database(mongoDBName).Collection("project_settings")
+	historyCollection := tasksClient.Database(mongoDBName).Collection("task_history")
+	if err := createHistoryIndex(historyCollection); err != nil {
+		logging.Logger.Fatalf("Event ID: DB_INDEX_ERROR, Description: %v", err)
+	}
+	templatesCollection := tasksClient.Database(mongoDBName).Collection("task_templates")
+	if err := createTemplateIndex(templatesCollection); err != nil {
+		logging.Logger.Fatalf("Event ID: DB_INDEX_ERROR, Description: %v", err)
+	}
 	httpClient := http_client.NewHTTPClient()
 
 	projectsBreaker := gobreaker.NewCircuitBreaker(gobreaker.Settings{
@@ -100,8 +136,76 @@ func main() {
 		},
 	})
 
-	taskService := services.NewTaskService(tasksCollection, httpClient, projectsBreaker, notificationsbreaker, workflowBreaker)
-	taskHandler := handlers.NewTaskHandler(taskService)
+	// Domenski događaji za analytics-service idu preko outbox-a na NATS JetStream
+	var eventOutbox *events.Outbox
+	if natsURL := os.Getenv("NATS_URL"); natsURL != "" {
+		nc, err := events.Connect(natsURL, "tasks-service")
+		if err != nil {
+			logging.Logger.Fatalf("Event ID: NATS_CONNECTION_FAILED, Description: Failed to connect to NATS at %s: %v", natsURL, err)
+		}
+		defer nc.Close()
+		publisher, err := events.NewPublisher(nc)
+		if err != nil {
+			logging.Logger.Fatalf("Event ID: NATS_CONNECTION_FAILED, Description: Failed to open JetStream context: %v", err)
+		}
+		eventOutbox = events.NewOutbox(tasksClient.Database(mongoDBName).Collection("event_outbox"), publisher)
+		if err := eventOutbox.EnsureIndexes(context.TODO()); err != nil {
+			logging.Logger.Fatalf("Event ID: DB_INDEX_ERROR, Description: %v", err)
+		}
+	} else {
+		logging.Logger.Warn("Event ID: DOMAIN_EVENTS_DISABLED, Description: NATS_URL is not set, domain events will not be published")
+	}
+
+	taskService := services.NewTaskService(tasksCollection, settingsCollection, historyCollection, templatesCollection, eventOutbox, httpClient, projectsBreaker, notificationsbreaker, workflowBreaker)
+	// Uloge korisnika na projektima razrešava projects-service
+	roleResolver := projectroles.NewResolver(os.Getenv("PROJECTS_SERVICE_URL"), httpClient, projectsBreaker)
+	taskHandler := handlers.NewTaskHandler(taskService, roleResolver)
+
+	// Jednokratna migracija starog `assignees` polja u `members`
+	if err := taskService.MigrateAssignees(context.Background()); err != nil {
+		logging.Logger.Errorf("Event ID: ASSIGNEES_MIGRATION_FAILED, Description: %v", err)
+	}
+
+	// Jednokratno objavljivanje postojeće istorije kao događaja, uključuje se sa TASK_EVENTS_BACKFILL=true
+	if os.Getenv("TASK_EVENTS_BACKFILL") == "true" {
+		count, err := taskService.BackfillEvents(context.Background())
+		if err != nil {
+			logging.Logger.Errorf("Event ID: DOMAIN_EVENTS_BACKFILL_FAILED, Description: Backfill stopped after %d events: %v", count, err)
+		} else {
+			logging.Logger.Infof("Event ID: DOMAIN_EVENTS_BACKFILLED, Description: Queued %d events from task history", count)
+		}
+	}
+	taskService.StartEventRelay(context.Background(), 15*time.Second)
+
+	// Scheduler za ponavljajuće zadatke
+	recurrenceInterval := time.Minute
+	if value := os.Getenv("RECURRENCE_SCAN_INTERVAL"); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil && parsed > 0 {
+			recurrenceInterval = parsed
+		}
+	}
+	taskService.StartRecurrenceScheduler(context.Background(), recurrenceInterval)
+
+	// Trajno brisanje obrisanih (i, ako je podešeno, arhiviranih) zadataka
+	deletedRetention := 30 * 24 * time.Hour
+	if value := os.Getenv("TASK_RETENTION_DAYS"); value != "" {
+		if days, err := strconv.Atoi(value); err == nil && days >= 0 {
+			deletedRetention = time.Duration(days) * 24 * time.Hour
+		}
+	}
+	var archivedRetention time.Duration
+	if value := os.Getenv("TASK_ARCHIVE_RETENTION_DAYS"); value != "" {
+		if days, err := strconv.Atoi(value); err == nil && days > 0 {
+			archivedRetention = time.Duration(days) * 24 * time.Hour
+		}
+	}
+	purgeInterval := time.Hour
+	if value := os.Getenv("TASK_PURGE_INTERVAL"); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil && parsed > 0 {
+			purgeInterval = parsed
+		}
+	}
+	taskService.StartTaskPurger(context.Background(), purgeInterval, deletedRetention, archivedRetention)
 
 	// Kreiranje mux routeraa
 	r := mux.NewRouter()
@@ -114,12 +218,48 @@ func main() {
 	r.HandleFunc("/api/tasks/all", taskHandler.GetAllTasks).Methods("GET")                         // Prikaz svih zadataka
 	r.HandleFunc("/api/tasks/create", taskHandler.CreateTask).Methods("POST")                      // Kreiranje novog zadatka
 	r.HandleFunc("/api/tasks/project/{projectId}", taskHandler.GetTasksByProjectID).Methods("GET") // Zadatke po ID-u projekta
+	r.HandleFunc("/api/project-tasks/{projectId}", taskHandler.GetTasksByProjectID).Methods(http.MethodGet)
 	r.HandleFunc("/api/tasks/status", taskHandler.ChangeTaskStatus).Methods("POST")
+	r.HandleFunc("/api/task-templates", taskHandler.ListTemplatesHandler).Methods(http.MethodGet)
+	r.HandleFunc("/api/task-templates", taskHandler.CreateTemplateHandler).Methods(http.MethodPost)
+	r.HandleFunc("/api/task-templates/{templateId}", taskHandler.GetTemplateHandler).Methods(http.MethodGet)
+	r.HandleFunc("/api/task-templates/{templateId}", taskHandler.UpdateTemplateHandler).Methods(http.MethodPut)
+	r.HandleFunc("/api/task-templates/{templateId}", taskHandler.DeleteTemplateHandler).Methods(http.MethodDelete)
+	r.HandleFunc("/api/sprints/{sprintId}/tasks", taskHandler.GetSprintTasksHandler).Methods(http.MethodGet)
+	r.HandleFunc("/api/sprints/{sprintId}/summary", taskHandler.GetSprintSummaryHandler).Methods(http.MethodGet)
+	r.HandleFunc("/api/sprints/{sprintId}/rollover", taskHandler.RolloverSprintHandler).Methods(http.MethodPost)
+	r.HandleFunc("/api/tasks/bulk", taskHandler.BulkTasksHandler).Methods(http.MethodPost)
+	r.HandleFunc("/api/tasks/import", taskHandler.ImportTasksHandler).Methods(http.MethodPost)
+	r.HandleFunc("/api/tasks/{taskID}/move", taskHandler.MoveTaskHandler).Methods(http.MethodPost)
+	r.HandleFunc("/api/tasks/{taskID}/history", taskHandler.GetTaskHistoryHandler).Methods(http.MethodGet)
+	r.HandleFunc("/api/tasks/{taskID}/watch", taskHandler.WatchTaskHandler).Methods(http.MethodPost)
+	r.HandleFunc("/api/tasks/{taskID}/watch", taskHandler.UnwatchTaskHandler).Methods(http.MethodDelete)
+	r.HandleFunc("/api/tasks/{taskID}/watchers", taskHandler.GetWatchersHandler).Methods(http.MethodGet)
+	r.HandleFunc("/api/tasks/{taskID}/assignment", taskHandler.GetTaskAssignmentHandler).Methods(http.MethodGet)
+	r.HandleFunc("/api/tasks/{taskID}/assignment", taskHandler.SetResponsibleMemberHandler).Methods(http.MethodPut)
+	r.HandleFunc("/api/tasks/{taskID}/recurrence", taskHandler.SetTaskRecurrenceHandler).Methods(http.MethodPut)
+	r.HandleFunc("/api/tasks/{taskID}/recurrence", taskHandler.StopTaskRecurrenceHandler).Methods(http.MethodDelete)
+	r.HandleFunc("/api/tasks/{taskID}/sprint", taskHandler.SetTaskSprintHandler).Methods(http.MethodPut)
+	r.HandleFunc("/api/tasks/{taskID}/transfer", taskHandler.TransferTaskHandler).Methods(http.MethodPost)
+	r.HandleFunc("/api/tasks/{taskID}/archive", taskHandler.ArchiveTaskHandler).Methods(http.MethodPost)
+	r.HandleFunc("/api/tasks/{taskID}/restore", taskHandler.RestoreTaskHandler).Methods(http.MethodPost)
+	r.HandleFunc("/api/project-tasks/{projectId}/archived", taskHandler.GetArchivedTasksHandler).Methods(http.MethodGet)
+	r.HandleFunc("/api/project-tasks/{projectId}/export", taskHandler.ExportProjectTasksHandler).Methods(http.MethodGet)
+	r.HandleFunc("/api/tasks/project/{projectId}/status-history", taskHandler.GetProjectStatusHistoryHandler).Methods(http.MethodGet)
+	r.HandleFunc("/api/project-tasks/{projectId}/wip-limits", taskHandler.GetWIPLimitsHandler).Methods(http.MethodGet)
+	r.HandleFunc("/api/project-tasks/{projectId}/wip-limits", taskHandler.SetWIPLimitsHandler).Methods(http.MethodPut)
 	r.HandleFunc("/api/tasks/project/{projectId}", taskHandler.DeleteTasksByProjectHandler).Methods(http.MethodDelete)
+	r.HandleFunc("/api/tasks/project/{projectId}/restore", taskHandler.RestoreTasksByProjectHandler).Methods(http.MethodPost)
+	r.HandleFunc("/api/tasks/project/{projectId}/clone", taskHandler.CloneProjectTasksHandler).Methods(http.MethodPost)
+	r.HandleFunc("/api/tasks/project/{projectId}/clone", taskHandler.DiscardClonedTasksHandler).Methods(http.MethodDelete)
 	r.HandleFunc("/api/tasks/has-active", taskHandler.HasActiveTasksHandler).Methods(http.MethodGet)
+	r.HandleFunc("/api/tasks/member/{username}/due", taskHandler.GetMemberDueTasksHandler).Methods(http.MethodGet)
 	r.HandleFunc("/api/tasks/project/{projectId}/has-unfinished", taskHandler.HasUnfinishedTasksHandler).Methods("GET")
 	r.HandleFunc("/api/tasks/remove-user/by-username/{username}", taskHandler.RemoveUserFromAllTasksByUsername).Methods("PATCH")
 
+	r.HandleFunc("/api/tasks/{taskID}", taskHandler.UpdateTaskHandler).Methods(http.MethodPut)
+	r.HandleFunc("/api/tasks/{taskID}", taskHandler.DeleteTaskHandler).Methods(http.MethodDelete)
+
 	corsRouter := enableCORS(r)
 
 	// Svi ostali taskovi .
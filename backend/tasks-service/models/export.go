@@ -0,0 +1,67 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TaskExportRow je jedan red izvoza zadataka projekta (CSV, JSON, XLSX).
+type TaskExportRow struct {
+	ID            string             `json:"id"`
+	Title         string             `json:"title"`
+	Description   string             `json:"description"`
+	Status        TaskStatus         `json:"status"`
+	Responsible   string             `json:"responsible,omitempty"`
+	Members       []string           `json:"members"`
+	Labels        []string           `json:"labels"`
+	DependsOn     []string           `json:"dependsOn"`
+	DueDate       *time.Time         `json:"dueDate,omitempty"`
+	CreatedAt     time.Time          `json:"createdAt"`
+	StartedAt     *time.Time         `json:"startedAt,omitempty"`
+	CompletedAt   *time.Time         `json:"completedAt,omitempty"`
+	HoursInStatus map[string]float64 `json:"hoursInStatus"`
+	SubtasksDone  int                `json:"subtasksDone"`
+	SubtasksTotal int                `json:"subtasksTotal"`
+}
+
+// TaskExportColumns su zaglavlja kolona za tabelarne formate, u redosledu Record().
+var TaskExportColumns = []string{
+	"ID", "Title", "Description", "Status", "Responsible", "Members", "Labels", "Depends on",
+	"Due date", "Created at", "Started at", "Completed at",
+	"Hours pending", "Hours in progress", "Hours completed", "Subtasks done", "Subtasks total",
+}
+
+// Record vraća red kao niz stringova za tabelarne formate.
+func (r TaskExportRow) Record() []string {
+	return []string{
+		r.ID,
+		r.Title,
+		r.Description,
+		string(r.Status),
+		r.Responsible,
+		strings.Join(r.Members, ", "),
+		strings.Join(r.Labels, ", "),
+		strings.Join(r.DependsOn, ", "),
+		formatExportTime(r.DueDate),
+		formatExportTime(&r.CreatedAt),
+		formatExportTime(r.StartedAt),
+		formatExportTime(r.CompletedAt),
+		formatExportHours(r.HoursInStatus[string(StatusPending)]),
+		formatExportHours(r.HoursInStatus[string(StatusInProgress)]),
+		formatExportHours(r.HoursInStatus[string(StatusCompleted)]),
+		fmt.Sprint(r.SubtasksDone),
+		fmt.Sprint(r.SubtasksTotal),
+	}
+}
+
+func formatExportTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func formatExportHours(hours float64) string {
+	return fmt.Sprintf("%.2f", hours)
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// HistoryAction koristi iste vrednosti kao ActivityType u analytics-service,
+// tako da se svaki zapis istorije može direktno preslikati u ProjectActivity.
+type HistoryAction string
+
+const (
+	HistoryCreateTask       HistoryAction = "CreateTask"
+	HistoryChangeTaskStatus HistoryAction = "ChangeTaskStatus"
+	HistoryAddMember        HistoryAction = "AddMember"
+	HistoryRemoveMember     HistoryAction = "RemoveMember"
+	HistoryUpdateTask       HistoryAction = "UpdateTask"
+	HistoryArchiveTask      HistoryAction = "ArchiveTask"
+	HistoryDeleteTask       HistoryAction = "DeleteTask"
+	HistoryRestoreTask      HistoryAction = "RestoreTask"
+)
+
+// TaskHistoryEntry je jedan zapis u istoriji izmena zadatka.
+type TaskHistoryEntry struct {
+	ID           primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	TaskID       primitive.ObjectID  `json:"taskId" bson:"taskId"`
+	ProjectID    string              `json:"projectId" bson:"projectId"`
+	ActivityType HistoryAction       `json:"activityType" bson:"activityType"`
+	MemberID     *primitive.ObjectID `json:"memberId,omitempty" bson:"memberId,omitempty"`
+	Actor        string              `json:"actor" bson:"actor"`
+	Field        string              `json:"field,omitempty" bson:"field,omitempty"`
+	OldValue     string              `json:"oldValue,omitempty" bson:"oldValue,omitempty"`
+	NewValue     string              `json:"newValue,omitempty" bson:"newValue,omitempty"`
+	Timestamp    time.Time           `json:"timestamp" bson:"timestamp"`
+	Details      string              `json:"details" bson:"details"`
+}
@@ -8,4 +8,14 @@ type Member struct {
 	LastName string             `bson:"lastName" json:"lastName"`
 	Username string             `bson:"username" json:"username"`
 	Role     string             `bson:"role" json:"role"`
+	// Assignment razlikuje odgovornog člana zadatka od ostalih saradnika.
+	// Prazna vrednost se tretira kao AssignmentContributor.
+	Assignment AssignmentType `bson:"assignment,omitempty" json:"assignment,omitempty"`
 }
+
+type AssignmentType string
+
+const (
+	AssignmentResponsible AssignmentType = "responsible"
+	AssignmentContributor AssignmentType = "contributor"
+)
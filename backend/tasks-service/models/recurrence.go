@@ -0,0 +1,200 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type RecurrenceFrequency string
+
+const (
+	RecurrenceDaily   RecurrenceFrequency = "daily"
+	RecurrenceWeekly  RecurrenceFrequency = "weekly"
+	RecurrenceMonthly RecurrenceFrequency = "monthly"
+)
+
+// Recurrence opisuje kako se zadatak ponavlja. Može se zadati poljima ili RRULE
+// stringom (podskup RFC 5545: FREQ, INTERVAL, BYDAY, BYMONTHDAY, COUNT, UNTIL).
+type Recurrence struct {
+	Frequency RecurrenceFrequency `json:"frequency" bson:"frequency"`
+	Interval  int                 `json:"interval,omitempty" bson:"interval,omitempty"`
+	Weekdays  []string            `json:"weekdays,omitempty" bson:"weekdays,omitempty"` // MO, TU, WE, TH, FR, SA, SU
+	MonthDay  int                 `json:"monthDay,omitempty" bson:"monthDay,omitempty"`
+	Count     int                 `json:"count,omitempty" bson:"count,omitempty"` // ukupan broj pojavljivanja, uključujući prvo
+	Until     *time.Time          `json:"until,omitempty" bson:"until,omitempty"`
+	RRule     string              `json:"rrule,omitempty" bson:"rrule,omitempty"`
+}
+
+var rruleWeekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// ParseRRule parsira podržani podskup RRULE formata, npr. "FREQ=WEEKLY;BYDAY=MO,TH".
+func ParseRRule(rule string) (*Recurrence, error) {
+	rule = strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:")
+	recurrence := &Recurrence{RRule: rule}
+
+	for _, part := range strings.Split(rule, ";") {
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid RRULE part: %s", part)
+		}
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			recurrence.Frequency = RecurrenceFrequency(strings.ToLower(value))
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid RRULE INTERVAL: %s", value)
+			}
+			recurrence.Interval = interval
+		case "BYDAY":
+			recurrence.Weekdays = strings.Split(strings.ToUpper(value), ",")
+		case "BYMONTHDAY":
+			day, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid RRULE BYMONTHDAY: %s", value)
+			}
+			recurrence.MonthDay = day
+		case "COUNT":
+			count, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid RRULE COUNT: %s", value)
+			}
+			recurrence.Count = count
+		case "UNTIL":
+			until, err := parseRRuleTime(value)
+			if err != nil {
+				return nil, err
+			}
+			recurrence.Until = &until
+		default:
+			return nil, fmt.Errorf("unsupported RRULE part: %s", key)
+		}
+	}
+	return recurrence, nil
+}
+
+func parseRRuleTime(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid RRULE UNTIL: %s", value)
+}
+
+// Normalize popunjava polja iz RRULE stringa (ako je zadat), postavlja podrazumevani
+// interval i proverava ispravnost pravila.
+func (r *Recurrence) Normalize() error {
+	if r.RRule != "" {
+		parsed, err := ParseRRule(r.RRule)
+		if err != nil {
+			return err
+		}
+		*r = *parsed
+	}
+
+	switch r.Frequency {
+	case RecurrenceDaily, RecurrenceWeekly, RecurrenceMonthly:
+	default:
+		return fmt.Errorf("unsupported recurrence frequency: %s", r.Frequency)
+	}
+	if r.Interval == 0 {
+		r.Interval = 1
+	}
+	if r.Interval < 0 {
+		return fmt.Errorf("recurrence interval must be positive")
+	}
+	for i, day := range r.Weekdays {
+		day = strings.ToUpper(strings.TrimSpace(day))
+		if _, ok := rruleWeekdays[day]; !ok {
+			return fmt.Errorf("invalid weekday: %s", day)
+		}
+		r.Weekdays[i] = day
+	}
+	if len(r.Weekdays) > 0 && r.Frequency != RecurrenceWeekly {
+		return fmt.Errorf("weekdays are only supported for weekly recurrence")
+	}
+	if r.MonthDay < 0 || r.MonthDay > 31 {
+		return fmt.Errorf("month day must be between 1 and 31")
+	}
+	if r.MonthDay != 0 && r.Frequency != RecurrenceMonthly {
+		return fmt.Errorf("month day is only supported for monthly recurrence")
+	}
+	if r.Count < 0 {
+		return fmt.Errorf("recurrence count must not be negative")
+	}
+	return nil
+}
+
+// Next vraća prvi termin posle from po ovom pravilu.
+func (r *Recurrence) Next(from time.Time) time.Time {
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	switch r.Frequency {
+	case RecurrenceWeekly:
+		if len(r.Weekdays) == 0 {
+			return from.AddDate(0, 0, 7*interval)
+		}
+		allowed := make(map[time.Weekday]bool)
+		for _, day := range r.Weekdays {
+			allowed[rruleWeekdays[day]] = true
+		}
+		startWeek := weekStart(from)
+		for offset := 1; offset <= 7*interval+7; offset++ {
+			candidate := from.AddDate(0, 0, offset)
+			weeks := int(weekStart(candidate).Sub(startWeek).Hours()/24+0.5) / 7
+			if weeks%interval == 0 && allowed[candidate.Weekday()] {
+				return candidate
+			}
+		}
+		return from.AddDate(0, 0, 7*interval)
+	case RecurrenceMonthly:
+		day := r.MonthDay
+		if day == 0 {
+			day = from.Day()
+		}
+		// Meseci bez traženog dana (npr. 31.) se preskaču
+		for months := interval; months <= 48*interval; months += interval {
+			firstOfMonth := time.Date(from.Year(), from.Month()+time.Month(months), 1, from.Hour(), from.Minute(), from.Second(), 0, from.Location())
+			if day <= daysIn(firstOfMonth) {
+				return firstOfMonth.AddDate(0, 0, day-1)
+			}
+		}
+		return from.AddDate(0, interval, 0)
+	default:
+		return from.AddDate(0, 0, interval)
+	}
+}
+
+// Exhausted vraća true ako pojavljivanje sa datim rednim brojom i terminom izlazi iz serije.
+func (r *Recurrence) Exhausted(occurrence int, due time.Time) bool {
+	if r.Count > 0 && occurrence > r.Count {
+		return true
+	}
+	return r.Until != nil && due.After(*r.Until)
+}
+
+func weekStart(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7 // ponedeljak je početak nedelje
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, t.Location())
+}
+
+func daysIn(firstOfMonth time.Time) int {
+	return firstOfMonth.AddDate(0, 1, -1).Day()
+}
@@ -0,0 +1,9 @@
+package models
+
+// ProjectSettings čuva podešavanja table (board) jednog projekta u tasks-service.
+type ProjectSettings struct {
+	ProjectID string `json:"projectId" bson:"projectId"`
+	// WIPLimits ograničava broj zadataka po statusu (ključ je vrednost TaskStatus).
+	// Vrednost 0 ili odsustvo ključa znači da ograničenje ne postoji.
+	WIPLimits map[string]int `json:"wipLimits" bson:"wipLimits"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Subtask je stavka checkliste unutar zadatka.
+type Subtask struct {
+	ID    primitive.ObjectID `json:"id" bson:"_id"`
+	Title string             `json:"title" bson:"title"`
+	Done  bool               `json:"done" bson:"done"`
+}
+
+// Comment je komentar na zadatku. Author je username autora.
+type Comment struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id"`
+	Author    string             `json:"author" bson:"author"`
+	Text      string             `json:"text" bson:"text"`
+	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
+}
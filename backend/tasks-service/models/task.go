@@ -1,6 +1,10 @@
 package models
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
 type TaskStatus string
 
@@ -17,5 +21,37 @@ type Task struct {
 	Description string             `json:"description" bson:"description"`
 	Status      TaskStatus         `json:"status" bson:"status"`
 	Members     []Member           `json:"members" bson:"members"`
-	Assignees   []Member           `bson:"assignees" json:"assignees"`
+	Watchers    []Member           `json:"watchers" bson:"watchers"`
+	Labels      []string           `json:"labels,omitempty" bson:"labels,omitempty"`
+	Subtasks    []Subtask          `json:"subtasks,omitempty" bson:"subtasks,omitempty"`
+	Comments    []Comment          `json:"comments,omitempty" bson:"comments,omitempty"`
+	Rank        float64            `json:"rank" bson:"rank"`
+	// SprintID je sprint iz projects-service-a; prazan znači da je zadatak u backlog-u.
+	SprintID    string      `json:"sprintId,omitempty" bson:"sprintId,omitempty"`
+	StoryPoints int         `json:"storyPoints,omitempty" bson:"storyPoints,omitempty"`
+	DueDate     *time.Time  `json:"dueDate,omitempty" bson:"dueDate,omitempty"`
+	Recurrence  *Recurrence `json:"recurrence,omitempty" bson:"recurrence,omitempty"`
+	// Polja serije ponavljajućih zadataka: ID prvog zadatka u seriji, redni broj
+	// pojavljivanja i da li je sledeće pojavljivanje već kreirano.
+	RecurrenceSeriesID *primitive.ObjectID `json:"recurrenceSeriesId,omitempty" bson:"recurrenceSeriesId,omitempty"`
+	Occurrence         int                 `json:"occurrence,omitempty" bson:"occurrence,omitempty"`
+	RecurrenceSpawned  bool                `json:"recurrenceSpawned,omitempty" bson:"recurrenceSpawned,omitempty"`
+	// Arhivirani i obrisani zadaci se ne prikazuju u podrazumevanim listama i ne mogu
+	// da se menjaju dok se ne vrate; trajno se brišu nakon perioda čuvanja.
+	ArchivedAt *time.Time `json:"archivedAt,omitempty" bson:"archivedAt,omitempty"`
+	DeletedAt  *time.Time `json:"deletedAt,omitempty" bson:"deletedAt,omitempty"`
+	RemovedBy  string     `json:"removedBy,omitempty" bson:"removedBy,omitempty"`
+}
+
+// IsActive vraća false za arhivirane i obrisane zadatke.
+func (t *Task) IsActive() bool {
+	return t.ArchivedAt == nil && t.DeletedAt == nil
+}
+
+func IsValidTaskStatus(status TaskStatus) bool {
+	switch status {
+	case StatusPending, StatusInProgress, StatusCompleted:
+		return true
+	}
+	return false
 }
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TaskTemplate je šablon za zadatke koji se često ponavljaju (npr. trijaža bagova).
+// Šablon bez ProjectID je globalan i dostupan u svim projektima.
+type TaskTemplate struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	ProjectID   string             `json:"projectId,omitempty" bson:"projectId,omitempty"`
+	Name        string             `json:"name" bson:"name"`
+	Title       string             `json:"title" bson:"title"`
+	Description string             `json:"description" bson:"description"`
+	Status      TaskStatus         `json:"status,omitempty" bson:"status,omitempty"`
+	Labels      []string           `json:"labels,omitempty" bson:"labels,omitempty"`
+	// Checklist su naslovi podzadataka koji se kreiraju uz svaki zadatak iz šablona.
+	Checklist []string `json:"checklist,omitempty" bson:"checklist,omitempty"`
+	// DueInDays, ako je veći od nule, postavlja rok zadatka relativno od kreiranja.
+	DueInDays int       `json:"dueInDays,omitempty" bson:"dueInDays,omitempty"`
+	CreatedBy string    `json:"createdBy,omitempty" bson:"createdBy,omitempty"`
+	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt" bson:"updatedAt"`
+}
+
+// TemplateOverrides su polja koja pri kreiranju zadatka iz šablona zamenjuju
+// vrednosti iz šablona. Nil polje znači da se koristi vrednost iz šablona.
+type TemplateOverrides struct {
+	Title       *string    `json:"title,omitempty"`
+	Description *string    `json:"description,omitempty"`
+	Status      TaskStatus `json:"status,omitempty"`
+	Labels      []string   `json:"labels,omitempty"`
+	Checklist   []string   `json:"checklist,omitempty"`
+	DueDate     *time.Time `json:"dueDate,omitempty"`
+}
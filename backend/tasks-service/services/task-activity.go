@@ -0,0 +1,34 @@
+package services
+
+import (
+	"net/http"
+
+	"trello-project/microservices/tasks-service/models"
+)
+
+// projectActivityTypes su zapisi istorije koji se prikazuju i u feed-u aktivnosti projekta.
+// AddDocumentToTask se ne šalje: zadaci za sada nemaju dokumente, pa ne postoji događaj
+// koji bi ga proizveo. Tip postoji u feed-u radi usklađenosti sa analytics-service-om.
+var projectActivityTypes = map[models.HistoryAction]bool{
+	models.HistoryCreateTask:       true,
+	models.HistoryDeleteTask:       true,
+	models.HistoryChangeTaskStatus: true,
+}
+
+// publishProjectActivity prosleđuje zapis istorije u feed aktivnosti projekta u
+// projects-service-u. Vrednosti HistoryAction su iste kao ActivityType, pa se zapis
+// preslikava bez konverzije.
+func (s *TaskService) publishProjectActivity(entry models.TaskHistoryEntry) {
+	if !projectActivityTypes[entry.ActivityType] || entry.ProjectID == "" {
+		return
+	}
+	taskID := entry.TaskID
+	s.postToProjectsAsync(entry.ProjectID, "activity", map[string]interface{}{
+		"activityType": entry.ActivityType,
+		"taskId":       &taskID,
+		"memberId":     entry.MemberID,
+		"actor":        entry.Actor,
+		"timestamp":    entry.Timestamp,
+		"details":      entry.Details,
+	}, http.StatusCreated)
+}
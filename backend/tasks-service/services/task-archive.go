@@ -0,0 +1,305 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"trello-project/microservices/tasks-service/logging"
+	"trello-project/microservices/tasks-service/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// activeTaskFilter dopunjuje filter tako da isključi arhivirane i obrisane zadatke.
+func activeTaskFilter(filter bson.M) bson.M {
+	filter["archivedAt"] = bson.M{"$exists": false}
+	filter["deletedAt"] = bson.M{"$exists": false}
+	return filter
+}
+
+// ensureTaskActive vraća grešku ako je zadatak arhiviran ili obrisan; takvi zadaci
+// moraju prvo da se vrate da bi se menjali.
+func ensureTaskActive(task *models.Task) error {
+	if task.DeletedAt != nil {
+		return fmt.Errorf("task is deleted")
+	}
+	if task.ArchivedAt != nil {
+		return fmt.Errorf("task is archived")
+	}
+	return nil
+}
+
+// ArchiveTask sklanja zadatak iz podrazumevanih listi. Čvor u workflow-service-u se
+// samo označava kao arhiviran, pa zavisnosti ostaju sačuvane.
+func (s *TaskService) ArchiveTask(ctx context.Context, taskID primitive.ObjectID, actor string) (*models.Task, error) {
+	var task models.Task
+	if err := s.tasksCollection.FindOne(ctx, bson.M{"_id": taskID}).Decode(&task); err != nil {
+		return nil, fmt.Errorf("task not found")
+	}
+	if err := ensureTaskActive(&task); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	update := bson.M{"$set": bson.M{"archivedAt": now, "removedBy": actor}}
+	err := s.inTransaction(ctx, func(ctx context.Context) error {
+		if _, err := s.tasksCollection.UpdateOne(ctx, bson.M{"_id": taskID}, update); err != nil {
+			return err
+		}
+		return s.recordHistory(ctx, models.TaskHistoryEntry{
+			TaskID:       task.ID,
+			ProjectID:    task.ProjectID,
+			ActivityType: models.HistoryArchiveTask,
+			Actor:        actor,
+			Details:      fmt.Sprintf("Task '%s' archived", task.Title),
+		})
+	})
+	if err != nil {
+		logging.Logger.Errorf("Event ID: TASK_ARCHIVE_FAILED, Description: Failed to archive task %s: %v", taskID.Hex(), err)
+		return nil, fmt.Errorf("failed to archive task: %v", err)
+	}
+	task.ArchivedAt = &now
+	task.RemovedBy = actor
+	logging.Logger.Infof("Event ID: TASK_ARCHIVED, Description: Task %s archived by %s.", taskID.Hex(), actor)
+
+	if err := s.setArchivedInWorkflow(taskID.Hex(), true); err != nil {
+		logging.Logger.Warnf("Event ID: WORKFLOW_ARCHIVE_FAILED, Description: Failed to mark task node %s as archived: %v", taskID.Hex(), err)
+	}
+
+	message := fmt.Sprintf("The task '%s' has been archived", task.Title)
+	for _, member := range taskRecipients(&task) {
+		s.notify(ctx, member, message)
+	}
+	return &task, nil
+}
+
+// softDeleteTask označava zadatak kao obrisan. Dokument ostaje u bazi do isteka
+// perioda čuvanja, tako da menadžer može da ga vrati.
+func (s *TaskService) softDeleteTask(ctx context.Context, task *models.Task, actor string) error {
+	now := time.Now().UTC()
+	update := bson.M{"$set": bson.M{"deletedAt": now, "removedBy": actor}}
+	err := s.inTransaction(ctx, func(ctx context.Context) error {
+		if _, err := s.tasksCollection.UpdateOne(ctx, bson.M{"_id": task.ID}, update); err != nil {
+			return err
+		}
+		return s.recordHistory(ctx, models.TaskHistoryEntry{
+			TaskID:       task.ID,
+			ProjectID:    task.ProjectID,
+			ActivityType: models.HistoryDeleteTask,
+			Actor:        actor,
+			Details:      fmt.Sprintf("Task '%s' deleted", task.Title),
+		})
+	})
+	if err != nil {
+		logging.Logger.Errorf("Event ID: TASK_DELETE_FAILED, Description: Failed to delete task %s: %v", task.ID.Hex(), err)
+		return fmt.Errorf("failed to delete task: %v", err)
+	}
+	task.DeletedAt = &now
+	task.RemovedBy = actor
+
+	if err := s.setArchivedInWorkflow(task.ID.Hex(), true); err != nil {
+		logging.Logger.Warnf("Event ID: WORKFLOW_ARCHIVE_FAILED, Description: Failed to mark task node %s as archived: %v", task.ID.Hex(), err)
+	}
+	return nil
+}
+
+// RestoreTask vraća arhiviran ili obrisan zadatak na kraj njegove kolone. Obrisan
+// zadatak se ponovo dodaje u listu zadataka projekta; ako projekat više ne postoji,
+// zadatak ostaje obrisan.
+func (s *TaskService) RestoreTask(ctx context.Context, taskID primitive.ObjectID, actor string) (*models.Task, error) {
+	var task models.Task
+	if err := s.tasksCollection.FindOne(ctx, bson.M{"_id": taskID}).Decode(&task); err != nil {
+		return nil, fmt.Errorf("task not found")
+	}
+	if task.IsActive() {
+		return nil, fmt.Errorf("task is not archived or deleted")
+	}
+
+	if err := s.checkWIPLimit(ctx, task.ProjectID, task.Status, false); err != nil {
+		return nil, err
+	}
+	if task.DeletedAt != nil {
+		if err := s.addTaskToProject(task.ProjectID, taskID.Hex()); err != nil {
+			logging.Logger.Errorf("Event ID: TASK_RESTORE_PROJECT_FAILED, Description: Failed to add task %s back to project %s: %v", taskID.Hex(), task.ProjectID, err)
+			return nil, fmt.Errorf("failed to restore task to project: %v", err)
+		}
+	}
+
+	rank, err := s.nextRankInColumn(ctx, task.ProjectID, task.Status)
+	if err != nil {
+		return nil, err
+	}
+	update := bson.M{
+		"$set":   bson.M{"rank": rank},
+		"$unset": bson.M{"archivedAt": "", "deletedAt": "", "removedBy": ""},
+	}
+	err = s.inTransaction(ctx, func(ctx context.Context) error {
+		if _, err := s.tasksCollection.UpdateOne(ctx, bson.M{"_id": taskID}, update); err != nil {
+			return err
+		}
+		return s.recordHistory(ctx, models.TaskHistoryEntry{
+			TaskID:       task.ID,
+			ProjectID:    task.ProjectID,
+			ActivityType: models.HistoryRestoreTask,
+			Actor:        actor,
+			Details:      fmt.Sprintf("Task '%s' restored", task.Title),
+		})
+	})
+	if err != nil {
+		logging.Logger.Errorf("Event ID: TASK_RESTORE_FAILED, Description: Failed to restore task %s: %v", taskID.Hex(), err)
+		return nil, fmt.Errorf("failed to restore task: %v", err)
+	}
+	logging.Logger.Infof("Event ID: TASK_RESTORED, Description: Task %s restored by %s.", taskID.Hex(), actor)
+
+	if err := s.setArchivedInWorkflow(taskID.Hex(), false); err != nil {
+		logging.Logger.Warnf("Event ID: WORKFLOW_ARCHIVE_FAILED, Description: Failed to mark task node %s as active: %v", taskID.Hex(), err)
+	}
+
+	if err := s.tasksCollection.FindOne(ctx, bson.M{"_id": taskID}).Decode(&task); err != nil {
+		return nil, fmt.Errorf("failed to fetch restored task: %v", err)
+	}
+	message := fmt.Sprintf("The task '%s' has been restored", task.Title)
+	for _, member := range taskRecipients(&task) {
+		s.notify(ctx, member, message)
+	}
+	return &task, nil
+}
+
+// GetArchivedTasks vraća arhivirane zadatke projekta, a uz includeDeleted i obrisane
+// koji još nisu trajno uklonjeni. Najskorije uklonjeni su prvi.
+func (s *TaskService) GetArchivedTasks(ctx context.Context, projectID string, includeDeleted bool) ([]models.Task, error) {
+	filter := bson.M{"projectId": projectID, "archivedAt": bson.M{"$exists": true}, "deletedAt": bson.M{"$exists": false}}
+	if includeDeleted {
+		filter = bson.M{
+			"projectId": projectID,
+			"$or": []bson.M{
+				{"archivedAt": bson.M{"$exists": true}},
+				{"deletedAt": bson.M{"$exists": true}},
+			},
+		}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "deletedAt", Value: -1}, {Key: "archivedAt", Value: -1}})
+	cursor, err := s.tasksCollection.Find(ctx, filter, opts)
+	if err != nil {
+		logging.Logger.Errorf("Event ID: ARCHIVED_TASKS_FETCH_FAILED, Description: Failed to find archived tasks for project %s: %v", projectID, err)
+		return nil, fmt.Errorf("failed to find archived tasks: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	tasks := []models.Task{}
+	if err := cursor.All(ctx, &tasks); err != nil {
+		return nil, fmt.Errorf("failed to decode archived tasks: %v", err)
+	}
+	return tasks, nil
+}
+
+// StartTaskPurger periodično trajno briše obrisane zadatke starije od deletedRetention
+// i arhivirane starije od archivedRetention. Arhivirani zadaci se čuvaju zauvek ako je
+// archivedRetention nula. Čvorovi u workflow-service-u se ne brišu.
+func (s *TaskService) StartTaskPurger(ctx context.Context, interval, deletedRetention, archivedRetention time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			s.purgeExpiredTasks(ctx, deletedRetention, archivedRetention)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	logging.Logger.Infof("Event ID: TASK_PURGER_STARTED, Description: Task purger running every %s (deleted retention %s, archived retention %s).", interval, deletedRetention, archivedRetention)
+}
+
+func (s *TaskService) purgeExpiredTasks(ctx context.Context, deletedRetention, archivedRetention time.Duration) {
+	now := time.Now().UTC()
+	conditions := []bson.M{{"deletedAt": bson.M{"$lte": now.Add(-deletedRetention)}}}
+	if archivedRetention > 0 {
+		conditions = append(conditions, bson.M{"archivedAt": bson.M{"$lte": now.Add(-archivedRetention)}})
+	}
+
+	cursor, err := s.tasksCollection.Find(ctx, bson.M{"$or": conditions}, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		logging.Logger.Errorf("Event ID: TASK_PURGE_SCAN_FAILED, Description: Failed to find expired tasks: %v", err)
+		return
+	}
+	var expired []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &expired); err != nil {
+		logging.Logger.Errorf("Event ID: TASK_PURGE_SCAN_FAILED, Description: Failed to decode expired tasks: %v", err)
+		return
+	}
+	if len(expired) == 0 {
+		return
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(expired))
+	for _, task := range expired {
+		ids = append(ids, task.ID)
+	}
+	result, err := s.tasksCollection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		logging.Logger.Errorf("Event ID: TASK_PURGE_FAILED, Description: Failed to purge expired tasks: %v", err)
+		return
+	}
+	if _, err := s.historyCollection.DeleteMany(ctx, bson.M{"taskId": bson.M{"$in": ids}}); err != nil {
+		logging.Logger.Warnf("Event ID: TASK_HISTORY_PURGE_FAILED, Description: Failed to purge history of expired tasks: %v", err)
+	}
+	logging.Logger.Infof("Event ID: TASKS_PURGED, Description: Permanently deleted %d expired tasks.", result.DeletedCount)
+}
+
+// addTaskToProject vraća zadatak u listu zadataka projekta u projects-service-u.
+func (s *TaskService) addTaskToProject(projectID, taskID string) error {
+	projectsURL := os.Getenv("PROJECTS_SERVICE_URL")
+	if projectsURL == "" {
+		return fmt.Errorf("PROJECTS_SERVICE_URL not set")
+	}
+
+	body, _ := json.Marshal(map[string]string{"taskID": taskID})
+	url := fmt.Sprintf("%s/api/projects/%s/add-task", strings.TrimRight(projectsURL, "/"), projectID)
+	_, err := s.ProjectsBreaker.Execute(func() (interface{}, error) {
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Role", "manager")
+		resp, err := s.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			respBody, _ := io.ReadAll(resp.Body)
+			return nil, fmt.Errorf("projects-service error: %s", string(respBody))
+		}
+		return nil, nil
+	})
+	return err
+}
+
+// setArchivedInWorkflow označava čvor zadatka u workflow-service-u kao arhiviran ili aktivan.
+func (s *TaskService) setArchivedInWorkflow(taskID string, archived bool) error {
+	return s.putArchivedInWorkflow(fmt.Sprintf("/api/workflow/task-node/%s/archived", taskID), archived)
+}
+
+// setProjectArchivedInWorkflow označava sve čvorove projekta kao arhivirane ili aktivne.
+func (s *TaskService) setProjectArchivedInWorkflow(projectID string, archived bool) error {
+	return s.putArchivedInWorkflow(fmt.Sprintf("/api/workflow/project/%s/archived", projectID), archived)
+}
+
+func (s *TaskService) putArchivedInWorkflow(path string, archived bool) error {
+	body, _ := json.Marshal(map[string]bool{"archived": archived})
+	return s.sendToWorkflow(http.MethodPut, path, body, http.StatusOK)
+}
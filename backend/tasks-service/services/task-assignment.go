@@ -0,0 +1,246 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"trello-project/microservices/tasks-service/logging"
+	"trello-project/microservices/tasks-service/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TaskAssignment razdvaja odgovornog člana zadatka od saradnika.
+type TaskAssignment struct {
+	Responsible  *models.Member  `json:"responsible"`
+	Contributors []models.Member `json:"contributors"`
+}
+
+// GetTaskAssignment vraća odgovornog člana i saradnike na zadatku.
+func (s *TaskService) GetTaskAssignment(ctx context.Context, taskID primitive.ObjectID) (*TaskAssignment, error) {
+	var task models.Task
+	if err := s.tasksCollection.FindOne(ctx, bson.M{"_id": taskID}).Decode(&task); err != nil {
+		logging.Logger.Warnf("Event ID: TASK_NOT_FOUND, Description: Task %s not found for assignment: %v", taskID.Hex(), err)
+		return nil, fmt.Errorf("task not found")
+	}
+
+	assignment := &TaskAssignment{Contributors: []models.Member{}}
+	for _, member := range task.Members {
+		if member.Assignment == models.AssignmentResponsible && assignment.Responsible == nil {
+			responsible := member
+			assignment.Responsible = &responsible
+			continue
+		}
+		assignment.Contributors = append(assignment.Contributors, member)
+	}
+	return assignment, nil
+}
+
+// SetResponsibleMember postavlja člana zadatka kao odgovornog; svi ostali postaju saradnici.
+func (s *TaskService) SetResponsibleMember(ctx context.Context, taskID, memberID primitive.ObjectID, actor string) (*TaskAssignment, error) {
+	var task models.Task
+	if err := s.tasksCollection.FindOne(ctx, bson.M{"_id": taskID}).Decode(&task); err != nil {
+		logging.Logger.Warnf("Event ID: TASK_NOT_FOUND, Description: Task %s not found for assignment: %v", taskID.Hex(), err)
+		return nil, fmt.Errorf("task not found")
+	}
+	if err := ensureTaskActive(&task); err != nil {
+		return nil, err
+	}
+
+	var previous, responsible *models.Member
+	for i := range task.Members {
+		if task.Members[i].Assignment == models.AssignmentResponsible {
+			prev := task.Members[i]
+			previous = &prev
+		}
+		if task.Members[i].ID == memberID {
+			task.Members[i].Assignment = models.AssignmentResponsible
+			responsible = &task.Members[i]
+		} else {
+			task.Members[i].Assignment = models.AssignmentContributor
+		}
+	}
+	if responsible == nil {
+		return nil, fmt.Errorf("member not found in the task")
+	}
+
+	if _, err := s.tasksCollection.UpdateOne(ctx, bson.M{"_id": taskID}, bson.M{"$set": bson.M{"members": task.Members}}); err != nil {
+		logging.Logger.Errorf("Event ID: TASK_ASSIGNMENT_UPDATE_FAILED, Description: Failed to set responsible member for task %s: %v", taskID.Hex(), err)
+		return nil, fmt.Errorf("failed to update task assignment: %v", err)
+	}
+
+	oldValue := ""
+	if previous != nil {
+		oldValue = previous.Username
+	}
+	if oldValue != responsible.Username {
+		memberObjectID := responsible.ID
+		s.recordHistory(ctx, models.TaskHistoryEntry{
+			TaskID:       task.ID,
+			ProjectID:    task.ProjectID,
+			ActivityType: models.HistoryUpdateTask,
+			MemberID:     &memberObjectID,
+			Actor:        actor,
+			Field:        "responsible",
+			OldValue:     oldValue,
+			NewValue:     responsible.Username,
+			Details:      fmt.Sprintf("%s is now responsible for task '%s'", responsible.Username, task.Title),
+		})
+		s.notify(ctx, *responsible, fmt.Sprintf("You are now responsible for the task: %s", task.Title))
+		s.notifyWatchers(ctx, &task, fmt.Sprintf("%s is now responsible for the task: %s", responsible.Username, task.Title), *responsible)
+	}
+
+	logging.Logger.Infof("Event ID: TASK_RESPONSIBLE_SET, Description: Member %s set as responsible for task %s.", responsible.Username, taskID.Hex())
+	return s.GetTaskAssignment(ctx, taskID)
+}
+
+// errUserNotFound znači da users-service ne poznaje korisnika.
+var errUserNotFound = errors.New("user not found")
+
+// MigrateAssignees spaja stari `assignees` niz u `members` i uklanja polje.
+// Stari zapisi mogu da sadrže i cele Member objekte i samo username stringove; ID člana
+// za njih se dobija iz users-service-a. Članovi čiji ID nije mogao da se utvrdi ostaju u
+// `assignees` i pokušavaju se ponovo pri sledećem pokretanju.
+func (s *TaskService) MigrateAssignees(ctx context.Context) error {
+	cursor, err := s.tasksCollection.Find(ctx, bson.M{"assignees": bson.M{"$exists": true}})
+	if err != nil {
+		return fmt.Errorf("failed to find tasks with assignees: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	resolved := make(map[string]*models.Member)
+	migrated, pending := 0, 0
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID        primitive.ObjectID `bson:"_id"`
+			Members   []models.Member    `bson:"members"`
+			Assignees bson.RawValue      `bson:"assignees"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			logging.Logger.Errorf("Event ID: ASSIGNEES_MIGRATION_DECODE_FAILED, Description: Failed to decode task during migration: %v", err)
+			continue
+		}
+
+		members := doc.Members
+		if members == nil {
+			members = []models.Member{}
+		}
+		existing := make(map[string]bool)
+		for _, member := range members {
+			existing[memberKey(member)] = true
+			existing["username:"+member.Username] = true
+		}
+
+		var legacy []bson.RawValue
+		if arr, ok := doc.Assignees.ArrayOK(); ok {
+			values, _ := arr.Values()
+			legacy = values
+		}
+		unresolved := []bson.RawValue{}
+		for _, value := range legacy {
+			var member models.Member
+			if username, ok := value.StringValueOK(); ok {
+				member = models.Member{Username: username}
+			} else if err := value.Unmarshal(&member); err != nil {
+				logging.Logger.Warnf("Event ID: ASSIGNEES_MIGRATION_SKIPPED, Description: Unrecognised assignee in task %s: %v", doc.ID.Hex(), err)
+				continue
+			}
+			if member.Username == "" || existing[memberKey(member)] || existing["username:"+member.Username] {
+				continue
+			}
+			if member.ID.IsZero() {
+				user, ok := resolved[member.Username]
+				if !ok {
+					user, err = s.lookupMember(ctx, member.Username)
+					if err != nil {
+						logging.Logger.Warnf("Event ID: ASSIGNEES_MIGRATION_UNRESOLVED, Description: Assignee '%s' of task %s kept in assignees: %v", member.Username, doc.ID.Hex(), err)
+						unresolved = append(unresolved, value)
+						continue
+					}
+					resolved[member.Username] = user
+				}
+				member.ID = user.ID
+				if member.Name == "" && member.LastName == "" {
+					member.Name, member.LastName = user.Name, user.LastName
+				}
+				if member.Role == "" {
+					member.Role = user.Role
+				}
+				if existing[memberKey(member)] {
+					continue
+				}
+			}
+			member.Assignment = models.AssignmentContributor
+			members = append(members, member)
+			existing[memberKey(member)] = true
+			existing["username:"+member.Username] = true
+		}
+
+		update := bson.M{"$set": bson.M{"members": members}, "$unset": bson.M{"assignees": ""}}
+		if len(unresolved) > 0 {
+			update = bson.M{"$set": bson.M{"members": members, "assignees": unresolved}}
+		}
+		if _, err := s.tasksCollection.UpdateOne(ctx, bson.M{"_id": doc.ID}, update); err != nil {
+			logging.Logger.Errorf("Event ID: ASSIGNEES_MIGRATION_UPDATE_FAILED, Description: Failed to migrate task %s: %v", doc.ID.Hex(), err)
+			continue
+		}
+		if len(unresolved) > 0 {
+			pending++
+			continue
+		}
+		migrated++
+	}
+
+	logging.Logger.Infof("Event ID: ASSIGNEES_MIGRATED, Description: Migrated assignees into members for %d tasks, %d tasks still have unresolved assignees.", migrated, pending)
+	return cursor.Err()
+}
+
+// lookupMember dohvata korisnika iz users-service-a po korisničkom imenu.
+func (s *TaskService) lookupMember(ctx context.Context, username string) (*models.Member, error) {
+	usersURL := os.Getenv("USERS_SERVICE_URL")
+	if usersURL == "" {
+		return nil, fmt.Errorf("USERS_SERVICE_URL not set")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/users/member/%s", strings.TrimRight(usersURL, "/"), url.PathEscape(username)), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Role", "manager")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to contact users-service: %v", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, errUserNotFound
+	default:
+		return nil, fmt.Errorf("users-service returned status %d", resp.StatusCode)
+	}
+
+	var user struct {
+		ID       string `json:"id"`
+		Name     string `json:"name"`
+		LastName string `json:"lastName"`
+		Username string `json:"username"`
+		Role     string `json:"role"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		return nil, fmt.Errorf("failed to decode user: %v", err)
+	}
+	userID, err := primitive.ObjectIDFromHex(user.ID)
+	if err != nil || userID.IsZero() {
+		return nil, fmt.Errorf("users-service returned invalid ID %q", user.ID)
+	}
+	return &models.Member{ID: userID, Name: user.Name, LastName: user.LastName, Username: username, Role: user.Role}, nil
+}
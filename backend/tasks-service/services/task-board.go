@@ -0,0 +1,241 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"trello-project/microservices/tasks-service/logging"
+	"trello-project/microservices/tasks-service/models"
+
+	"trello-project/backend/utils/projectroles"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// rankStep je razmak između susednih zadataka kada se rank dodeljuje na kraj kolone.
+	rankStep = 1024.0
+	// minRankGap je najmanji razmak ispod kog se kolona prenumeriše da bi se izbegla
+	// greška preciznosti float64 vrednosti.
+	minRankGap = 1e-6
+)
+
+// nextRankInColumn vraća rank za zadatak koji se dodaje na kraj kolone (projekat + status).
+func (s *TaskService) nextRankInColumn(ctx context.Context, projectID string, status models.TaskStatus) (float64, error) {
+	opts := options.FindOne().SetSort(bson.D{{Key: "rank", Value: -1}})
+	var last models.Task
+	err := s.tasksCollection.FindOne(ctx, activeTaskFilter(bson.M{"projectId": projectID, "status": status}), opts).Decode(&last)
+	if err == mongo.ErrNoDocuments {
+		return rankStep, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read column rank: %v", err)
+	}
+	return last.Rank + rankStep, nil
+}
+
+// rankBetween računa rank između dva suseda. Nil sused znači početak ili kraj kolone.
+func rankBetween(previous, next *models.Task) (float64, bool) {
+	switch {
+	case previous != nil && next != nil:
+		if next.Rank-previous.Rank < minRankGap {
+			return 0, false
+		}
+		return (previous.Rank + next.Rank) / 2, true
+	case previous != nil:
+		return previous.Rank + rankStep, true
+	case next != nil:
+		return next.Rank - rankStep, true
+	}
+	return rankStep, true
+}
+
+// rebalanceColumn ponovo raspoređuje rankove u jednoj koloni na ravnomerne razmake.
+// Poziva se samo kada se razmak između dva suseda istroši.
+func (s *TaskService) rebalanceColumn(ctx context.Context, projectID string, status models.TaskStatus) error {
+	opts := options.Find().SetSort(bson.D{{Key: "rank", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := s.tasksCollection.Find(ctx, activeTaskFilter(bson.M{"projectId": projectID, "status": status}), opts)
+	if err != nil {
+		return fmt.Errorf("failed to load column: %v", err)
+	}
+	var column []models.Task
+	if err := cursor.All(ctx, &column); err != nil {
+		return fmt.Errorf("failed to decode column: %v", err)
+	}
+
+	for i, task := range column {
+		rank := float64(i+1) * rankStep
+		if _, err := s.tasksCollection.UpdateOne(ctx, bson.M{"_id": task.ID}, bson.M{"$set": bson.M{"rank": rank}}); err != nil {
+			return fmt.Errorf("failed to rebalance task %s: %v", task.ID.Hex(), err)
+		}
+	}
+	logging.Logger.Infof("Event ID: COLUMN_REBALANCED, Description: Rebalanced %d tasks in column '%s' of project %s.", len(column), status, projectID)
+	return nil
+}
+
+// loadNeighbour učitava susedni zadatak i proverava da pripada istoj koloni.
+func (s *TaskService) loadNeighbour(ctx context.Context, neighbourID string, projectID string, status models.TaskStatus) (*models.Task, error) {
+	if neighbourID == "" {
+		return nil, nil
+	}
+	objectID, err := primitive.ObjectIDFromHex(neighbourID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid neighbour task ID format")
+	}
+	var neighbour models.Task
+	if err := s.tasksCollection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&neighbour); err != nil {
+		return nil, fmt.Errorf("neighbour task not found: %v", err)
+	}
+	if neighbour.ProjectID != projectID || neighbour.Status != status || !neighbour.IsActive() {
+		return nil, fmt.Errorf("neighbour task '%s' is not in column '%s'", neighbour.Title, status)
+	}
+	return &neighbour, nil
+}
+
+// MoveTask premešta zadatak unutar kolone ili u drugu kolonu. previousTaskID je zadatak
+// iznad novog mesta, a nextTaskID zadatak ispod njega; oba mogu biti prazna.
+// Promena statusa prolazi kroz ista pravila kao ChangeTaskStatus (zavisnosti i WIP limiti).
+func (s *TaskService) MoveTask(taskID primitive.ObjectID, status models.TaskStatus, previousTaskID, nextTaskID, username string, role projectroles.Role, overrideWIP bool) (*models.Task, error) {
+	ctx := context.Background()
+
+	var task models.Task
+	if err := s.tasksCollection.FindOne(ctx, bson.M{"_id": taskID}).Decode(&task); err != nil {
+		return nil, fmt.Errorf("task not found: %v", err)
+	}
+	if err := ensureTaskActive(&task); err != nil {
+		return nil, err
+	}
+
+	if status == "" {
+		status = task.Status
+	}
+	if !models.IsValidTaskStatus(status) {
+		return nil, fmt.Errorf("invalid task status: %s", status)
+	}
+
+	previous, err := s.loadNeighbour(ctx, previousTaskID, task.ProjectID, status)
+	if err != nil {
+		return nil, err
+	}
+	next, err := s.loadNeighbour(ctx, nextTaskID, task.ProjectID, status)
+	if err != nil {
+		return nil, err
+	}
+	if previous != nil && next != nil && previous.Rank > next.Rank {
+		return nil, fmt.Errorf("previous task must be ranked above next task")
+	}
+
+	if status != task.Status {
+		if _, err := s.ChangeTaskStatus(taskID, status, username, role, overrideWIP); err != nil {
+			return nil, err
+		}
+	}
+
+	var rank float64
+	if previous == nil && next == nil {
+		// Bez suseda zadatak ide na kraj kolone
+		rank, err = s.nextRankInColumn(ctx, task.ProjectID, status)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		var ok bool
+		rank, ok = rankBetween(previous, next)
+		if !ok {
+			if err := s.rebalanceColumn(ctx, task.ProjectID, status); err != nil {
+				return nil, err
+			}
+			if previous, err = s.loadNeighbour(ctx, previousTaskID, task.ProjectID, status); err != nil {
+				return nil, err
+			}
+			if next, err = s.loadNeighbour(ctx, nextTaskID, task.ProjectID, status); err != nil {
+				return nil, err
+			}
+			rank, _ = rankBetween(previous, next)
+		}
+	}
+
+	if _, err := s.tasksCollection.UpdateOne(ctx, bson.M{"_id": taskID}, bson.M{"$set": bson.M{"rank": rank}}); err != nil {
+		logging.Logger.Errorf("Event ID: TASK_RANK_UPDATE_FAILED, Description: Failed to update rank for task %s: %v", taskID.Hex(), err)
+		return nil, fmt.Errorf("failed to update task rank: %v", err)
+	}
+
+	if err := s.tasksCollection.FindOne(ctx, bson.M{"_id": taskID}).Decode(&task); err != nil {
+		return nil, fmt.Errorf("failed to fetch moved task: %v", err)
+	}
+
+	logging.Logger.Infof("Event ID: TASK_MOVED, Description: Task %s moved to column '%s' with rank %f.", taskID.Hex(), task.Status, task.Rank)
+	return &task, nil
+}
+
+// GetProjectSettings vraća podešavanja table za projekat (prazna ako nisu sačuvana).
+func (s *TaskService) GetProjectSettings(ctx context.Context, projectID string) (*models.ProjectSettings, error) {
+	settings := models.ProjectSettings{ProjectID: projectID}
+	err := s.settingsCollection.FindOne(ctx, bson.M{"projectId": projectID}).Decode(&settings)
+	if err != nil && err != mongo.ErrNoDocuments {
+		logging.Logger.Errorf("Event ID: PROJECT_SETTINGS_FETCH_FAILED, Description: Failed to fetch settings for project %s: %v", projectID, err)
+		return nil, fmt.Errorf("failed to fetch project settings: %v", err)
+	}
+	if settings.WIPLimits == nil {
+		settings.WIPLimits = map[string]int{}
+	}
+	return &settings, nil
+}
+
+// SetWIPLimits zamenjuje WIP limite za projekat.
+func (s *TaskService) SetWIPLimits(ctx context.Context, projectID string, limits map[string]int) (*models.ProjectSettings, error) {
+	if _, err := primitive.ObjectIDFromHex(projectID); err != nil {
+		return nil, fmt.Errorf("invalid project ID format")
+	}
+	for status, limit := range limits {
+		if !models.IsValidTaskStatus(models.TaskStatus(status)) {
+			return nil, fmt.Errorf("invalid task status: %s", status)
+		}
+		if limit < 0 {
+			return nil, fmt.Errorf("WIP limit for '%s' must not be negative", status)
+		}
+	}
+
+	opts := options.Update().SetUpsert(true)
+	_, err := s.settingsCollection.UpdateOne(ctx,
+		bson.M{"projectId": projectID},
+		bson.M{"$set": bson.M{"projectId": projectID, "wipLimits": limits}},
+		opts,
+	)
+	if err != nil {
+		logging.Logger.Errorf("Event ID: WIP_LIMITS_UPDATE_FAILED, Description: Failed to update WIP limits for project %s: %v", projectID, err)
+		return nil, fmt.Errorf("failed to update WIP limits: %v", err)
+	}
+
+	logging.Logger.Infof("Event ID: WIP_LIMITS_UPDATED, Description: WIP limits for project %s set to %v", projectID, limits)
+	return s.GetProjectSettings(ctx, projectID)
+}
+
+// checkWIPLimit proverava da li bi ulazak još jednog zadatka u kolonu prešao WIP limit.
+func (s *TaskService) checkWIPLimit(ctx context.Context, projectID string, status models.TaskStatus, overrideWIP bool) error {
+	settings, err := s.GetProjectSettings(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	limit := settings.WIPLimits[string(status)]
+	if limit <= 0 {
+		return nil
+	}
+
+	count, err := s.tasksCollection.CountDocuments(ctx, activeTaskFilter(bson.M{"projectId": projectID, "status": status}))
+	if err != nil {
+		return fmt.Errorf("failed to count tasks in column: %v", err)
+	}
+	if count < int64(limit) {
+		return nil
+	}
+
+	if overrideWIP {
+		logging.Logger.Warnf("Event ID: WIP_LIMIT_OVERRIDDEN, Description: WIP limit %d for '%s' in project %s overridden by manager.", limit, status, projectID)
+		return nil
+	}
+	return fmt.Errorf("WIP limit reached: column '%s' already has %d of %d tasks", status, count, limit)
+}
@@ -0,0 +1,345 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"trello-project/microservices/tasks-service/logging"
+	"trello-project/microservices/tasks-service/models"
+
+	"trello-project/backend/utils/projectroles"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxBulkTasks ograničava broj zadataka u jednom bulk zahtevu.
+const maxBulkTasks = 200
+
+type BulkAction string
+
+const (
+	BulkActionStatus        BulkAction = "status"
+	BulkActionAddMembers    BulkAction = "add-members"
+	BulkActionRemoveMembers BulkAction = "remove-members"
+	BulkActionLabel         BulkAction = "label"
+	BulkActionDelete        BulkAction = "delete"
+)
+
+// BulkTaskRequest opisuje jednu akciju koja se primenjuje na listu zadataka.
+type BulkTaskRequest struct {
+	TaskIDs      []string          `json:"taskIds"`
+	Action       BulkAction        `json:"action"`
+	Status       models.TaskStatus `json:"status,omitempty"`
+	OverrideWIP  bool              `json:"overrideWip,omitempty"`
+	Members      []models.Member   `json:"members,omitempty"`
+	MemberIDs    []string          `json:"memberIds,omitempty"`
+	AddLabels    []string          `json:"addLabels,omitempty"`
+	RemoveLabels []string          `json:"removeLabels,omitempty"`
+}
+
+// BulkTaskResult je ishod bulk akcije za jedan zadatak.
+type BulkTaskResult struct {
+	TaskID  string       `json:"taskId"`
+	Success bool         `json:"success"`
+	Error   string       `json:"error,omitempty"`
+	Task    *models.Task `json:"task,omitempty"`
+}
+
+// notificationBatch skuplja poruke po korisniku tokom bulk operacije, tako da
+// korisnik dobije jednu notifikaciju umesto po jednu za svaki zadatak.
+type notificationBatch struct {
+	mu       sync.Mutex
+	order    []string
+	members  map[string]models.Member
+	messages map[string][]string
+}
+
+type notificationBatchKey struct{}
+
+func newNotificationBatch() *notificationBatch {
+	return &notificationBatch{
+		members:  make(map[string]models.Member),
+		messages: make(map[string][]string),
+	}
+}
+
+func withNotificationBatch(ctx context.Context, batch *notificationBatch) context.Context {
+	return context.WithValue(ctx, notificationBatchKey{}, batch)
+}
+
+func notificationBatchFrom(ctx context.Context) *notificationBatch {
+	batch, _ := ctx.Value(notificationBatchKey{}).(*notificationBatch)
+	return batch
+}
+
+func (b *notificationBatch) add(member models.Member, message string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	key := memberKey(member)
+	if _, ok := b.members[key]; !ok {
+		b.order = append(b.order, key)
+		b.members[key] = member
+	}
+	b.messages[key] = append(b.messages[key], message)
+}
+
+// flushNotifications šalje po jednu (zbirnu) notifikaciju svakom korisniku iz paketa.
+func (s *TaskService) flushNotifications(batch *notificationBatch) {
+	batch.mu.Lock()
+	defer batch.mu.Unlock()
+	for _, key := range batch.order {
+		messages := batch.messages[key]
+		message := messages[0]
+		if len(messages) > 1 {
+			message = fmt.Sprintf("%d task updates:\n- %s", len(messages), strings.Join(messages, "\n- "))
+		}
+		s.notifyAsync(batch.members[key], message)
+	}
+}
+
+func (r *BulkTaskRequest) validate() error {
+	if len(r.TaskIDs) == 0 {
+		return fmt.Errorf("taskIds must not be empty")
+	}
+	if len(r.TaskIDs) > maxBulkTasks {
+		return fmt.Errorf("at most %d tasks can be updated at once", maxBulkTasks)
+	}
+	switch r.Action {
+	case BulkActionStatus:
+		if !models.IsValidTaskStatus(r.Status) {
+			return fmt.Errorf("invalid task status: %s", r.Status)
+		}
+	case BulkActionAddMembers:
+		if len(r.Members) == 0 {
+			return fmt.Errorf("members must not be empty")
+		}
+	case BulkActionRemoveMembers:
+		if len(r.MemberIDs) == 0 {
+			return fmt.Errorf("memberIds must not be empty")
+		}
+		for _, memberID := range r.MemberIDs {
+			if _, err := primitive.ObjectIDFromHex(memberID); err != nil {
+				return fmt.Errorf("invalid member ID format: %s", memberID)
+			}
+		}
+	case BulkActionLabel:
+		if len(r.AddLabels) == 0 && len(r.RemoveLabels) == 0 {
+			return fmt.Errorf("addLabels or removeLabels is required")
+		}
+	case BulkActionDelete:
+	default:
+		return fmt.Errorf("unsupported bulk action: %s", r.Action)
+	}
+	return nil
+}
+
+// BulkUpdateTasks primenjuje istu akciju na svaki zadatak iz zahteva. Svaki zadatak
+// prolazi kroz istu putanju kao pojedinačni zahtev (zavisnosti, WIP limiti, zaštita
+// završenih zadataka), a greška na jednom zadatku ne prekida ostale.
+func (s *TaskService) BulkUpdateTasks(ctx context.Context, request BulkTaskRequest, username string, role projectroles.Role) ([]BulkTaskResult, error) {
+	if err := request.validate(); err != nil {
+		return nil, err
+	}
+
+	batch := newNotificationBatch()
+	ctx = withNotificationBatch(ctx, batch)
+	defer s.flushNotifications(batch)
+
+	results := make([]BulkTaskResult, 0, len(request.TaskIDs))
+	seen := make(map[string]bool)
+	for _, taskID := range request.TaskIDs {
+		if seen[taskID] {
+			continue
+		}
+		seen[taskID] = true
+
+		result := BulkTaskResult{TaskID: taskID}
+		task, err := s.applyBulkAction(ctx, taskID, request, username, role)
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Success = true
+			result.Task = task
+		}
+		results = append(results, result)
+	}
+
+	logging.Logger.Infof("Event ID: TASKS_BULK_UPDATED, Description: Bulk action '%s' applied to %d tasks by %s.", request.Action, len(results), username)
+	return results, nil
+}
+
+func (s *TaskService) applyBulkAction(ctx context.Context, taskID string, request BulkTaskRequest, username string, role projectroles.Role) (*models.Task, error) {
+	taskObjectID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid task ID format")
+	}
+
+	switch request.Action {
+	case BulkActionStatus:
+		return s.changeTaskStatus(ctx, taskObjectID, request.Status, username, role, request.OverrideWIP)
+	case BulkActionAddMembers:
+		if err := s.addMembersToTask(ctx, taskID, request.Members, username); err != nil {
+			return nil, err
+		}
+	case BulkActionRemoveMembers:
+		for _, memberID := range request.MemberIDs {
+			memberObjectID, _ := primitive.ObjectIDFromHex(memberID)
+			err := s.removeMemberFromTask(ctx, taskID, memberObjectID, username)
+			// Član koji nije na zadatku se preskače
+			if err != nil && err.Error() != "member not found in the task" {
+				return nil, err
+			}
+		}
+	case BulkActionLabel:
+		return s.UpdateTaskLabels(ctx, taskObjectID, request.AddLabels, request.RemoveLabels, username)
+	case BulkActionDelete:
+		return nil, s.deleteTask(ctx, taskObjectID, username)
+	}
+
+	return s.GetTaskByID(taskObjectID)
+}
+
+func normalizeLabels(labels []string) []string {
+	normalized := []string{}
+	for _, label := range labels {
+		label = strings.TrimSpace(label)
+		if label != "" {
+			normalized = append(normalized, html.EscapeString(label))
+		}
+	}
+	return normalized
+}
+
+// UpdateTaskLabels dodaje i uklanja oznake (labels) na zadatku.
+func (s *TaskService) UpdateTaskLabels(ctx context.Context, taskID primitive.ObjectID, add, remove []string, actor string) (*models.Task, error) {
+	var task models.Task
+	if err := s.tasksCollection.FindOne(ctx, bson.M{"_id": taskID}).Decode(&task); err != nil {
+		return nil, fmt.Errorf("task not found")
+	}
+	if err := ensureTaskActive(&task); err != nil {
+		return nil, err
+	}
+
+	add = normalizeLabels(add)
+	remove = normalizeLabels(remove)
+	if len(add) > 0 {
+		if _, err := s.tasksCollection.UpdateOne(ctx, bson.M{"_id": taskID}, bson.M{"$addToSet": bson.M{"labels": bson.M{"$each": add}}}); err != nil {
+			return nil, fmt.Errorf("failed to add labels: %v", err)
+		}
+	}
+	if len(remove) > 0 {
+		if _, err := s.tasksCollection.UpdateOne(ctx, bson.M{"_id": taskID}, bson.M{"$pull": bson.M{"labels": bson.M{"$in": remove}}}); err != nil {
+			return nil, fmt.Errorf("failed to remove labels: %v", err)
+		}
+	}
+
+	previous := strings.Join(task.Labels, ",")
+	if err := s.tasksCollection.FindOne(ctx, bson.M{"_id": taskID}).Decode(&task); err != nil {
+		return nil, fmt.Errorf("failed to fetch updated task: %v", err)
+	}
+	if current := strings.Join(task.Labels, ","); current != previous {
+		s.recordHistory(ctx, models.TaskHistoryEntry{
+			TaskID:       task.ID,
+			ProjectID:    task.ProjectID,
+			ActivityType: models.HistoryUpdateTask,
+			Actor:        actor,
+			Field:        "labels",
+			OldValue:     previous,
+			NewValue:     current,
+			Details:      fmt.Sprintf("Labels of task '%s' changed", task.Title),
+		})
+	}
+	return &task, nil
+}
+
+// deleteTask označava zadatak kao obrisan i uklanja ga iz liste zadataka projekta.
+// Do isteka perioda čuvanja menadžer može da ga vrati kroz RestoreTask.
+func (s *TaskService) deleteTask(ctx context.Context, taskID primitive.ObjectID, actor string) error {
+	var task models.Task
+	if err := s.tasksCollection.FindOne(ctx, bson.M{"_id": taskID}).Decode(&task); err != nil {
+		return fmt.Errorf("task not found")
+	}
+	if task.DeletedAt != nil {
+		return fmt.Errorf("task not found")
+	}
+
+	if err := s.softDeleteTask(ctx, &task, actor); err != nil {
+		return err
+	}
+	logging.Logger.Infof("Event ID: TASK_DELETED, Description: Task %s deleted by %s.", taskID.Hex(), actor)
+
+	if err := s.removeTaskFromProject(task.ProjectID, taskID.Hex()); err != nil {
+		logging.Logger.Warnf("Event ID: TASK_PROJECT_UNLINK_FAILED, Description: Failed to remove task %s from project %s: %v", taskID.Hex(), task.ProjectID, err)
+	}
+
+	message := fmt.Sprintf("The task '%s' has been deleted", task.Title)
+	for _, member := range taskRecipients(&task) {
+		s.notify(ctx, member, message)
+	}
+	return nil
+}
+
+// DeleteTask briše jedan zadatak.
+func (s *TaskService) DeleteTask(ctx context.Context, taskID primitive.ObjectID, actor string) error {
+	return s.deleteTask(ctx, taskID, actor)
+}
+
+func (s *TaskService) removeTaskFromProject(projectID, taskID string) error {
+	projectsURL := os.Getenv("PROJECTS_SERVICE_URL")
+	if projectsURL == "" {
+		return fmt.Errorf("PROJECTS_SERVICE_URL not set")
+	}
+
+	url := fmt.Sprintf("%s/api/projects/%s/tasks/%s", strings.TrimRight(projectsURL, "/"), projectID, taskID)
+	_, err := s.ProjectsBreaker.Execute(func() (interface{}, error) {
+		req, err := http.NewRequest(http.MethodDelete, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Role", "manager")
+		resp, err := s.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			return nil, fmt.Errorf("projects-service error: %s", string(body))
+		}
+		return nil, nil
+	})
+	return err
+}
+
+// ProjectIDsOfTasks vraća projekte kojima zadaci pripadaju, radi provere uloge pre bulk
+// akcije. Nepostojeći i neispravni ID-jevi se preskaču; za njih bulk vraća grešku po zadatku.
+func (s *TaskService) ProjectIDsOfTasks(ctx context.Context, taskIDs []string) ([]string, error) {
+	objectIDs := make([]primitive.ObjectID, 0, len(taskIDs))
+	for _, taskID := range taskIDs {
+		if objectID, err := primitive.ObjectIDFromHex(taskID); err == nil {
+			objectIDs = append(objectIDs, objectID)
+		}
+	}
+	if len(objectIDs) == 0 {
+		return []string{}, nil
+	}
+
+	values, err := s.tasksCollection.Distinct(ctx, "projectId", bson.M{"_id": bson.M{"$in": objectIDs}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve task projects: %v", err)
+	}
+	projectIDs := make([]string, 0, len(values))
+	for _, value := range values {
+		if projectID, ok := value.(string); ok {
+			projectIDs = append(projectIDs, projectID)
+		}
+	}
+	return projectIDs, nil
+}
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"trello-project/microservices/tasks-service/logging"
+	"trello-project/microservices/tasks-service/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GetMemberDueTasks vraća aktivne zadatke sa rokom kojima je korisnik dodeljen; koristi
+// ga kalendarski feed u projects-service-u.
+func (s *TaskService) GetMemberDueTasks(ctx context.Context, username string) ([]models.Task, error) {
+	filter := activeTaskFilter(bson.M{
+		"members.username": username,
+		"dueDate":          bson.M{"$exists": true},
+	})
+	opts := options.Find().SetSort(bson.D{{Key: "dueDate", Value: 1}})
+	cursor, err := s.tasksCollection.Find(ctx, filter, opts)
+	if err != nil {
+		logging.Logger.Errorf("Event ID: MEMBER_DUE_TASKS_FETCH_FAILED, Description: Failed to find due tasks for %s: %v", username, err)
+		return nil, fmt.Errorf("failed to find tasks: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	tasks := []models.Task{}
+	if err := cursor.All(ctx, &tasks); err != nil {
+		logging.Logger.Errorf("Event ID: MEMBER_DUE_TASKS_DECODE_FAILED, Description: Failed to decode due tasks for %s: %v", username, err)
+		return nil, fmt.Errorf("failed to decode tasks: %w", err)
+	}
+	return tasks, nil
+}
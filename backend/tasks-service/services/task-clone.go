@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"time"
+
+	"trello-project/microservices/tasks-service/logging"
+	"trello-project/microservices/tasks-service/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CloneRollbackActor označava događaje objavljene pri poništavanju kloniranja.
+const CloneRollbackActor = "clone-rollback"
+
+// ProjectCloneRequest opisuje kopiranje zadataka jednog projekta u drugi.
+type ProjectCloneRequest struct {
+	TargetProjectID string `json:"targetProjectId"`
+	CopyMembers     bool   `json:"copyMembers"`
+	// DueDateShift pomera rokove kopija za isti pomak za koji je pomeren rok projekta.
+	DueDateShift time.Duration `json:"dueDateShift"`
+	Actor        string        `json:"actor"`
+}
+
+// ClonedTask povezuje zadatak sa njegovom kopijom, radi kopiranja zavisnosti.
+type ClonedTask struct {
+	SourceTaskID string `json:"sourceTaskId"`
+	TaskID       string `json:"taskId"`
+}
+
+// CloneProjectTasks kopira aktivne zadatke projekta u ciljni projekat kroz CreateTask,
+// pa kopije dobijaju i čvor u workflow-service-u. Kopije kreću od statusa Pending sa
+// neodrađenim podzadacima; komentari, posmatrači, sprint i ponavljanje se ne kopiraju.
+// Ako kopiranje ne uspe, već kreirane kopije ostaju u ciljnom projektu, a projects-service
+// ih uklanja kroz DiscardClonedTasks.
+func (s *TaskService) CloneProjectTasks(ctx context.Context, sourceProjectID string, request ProjectCloneRequest) ([]ClonedTask, error) {
+	if _, err := primitive.ObjectIDFromHex(request.TargetProjectID); err != nil {
+		return nil, fmt.Errorf("invalid target project ID format")
+	}
+	if request.TargetProjectID == sourceProjectID {
+		return nil, fmt.Errorf("target project must differ from the source project")
+	}
+
+	tasks, err := s.GetTasksByProjectID(sourceProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	cloned := make([]ClonedTask, 0, len(tasks))
+	for _, task := range tasks {
+		copied, err := s.CreateTask(request.TargetProjectID, html.UnescapeString(task.Title), html.UnescapeString(task.Description), models.StatusPending, request.Actor)
+		if err != nil {
+			return cloned, fmt.Errorf("failed to copy task %s: %v", task.ID.Hex(), err)
+		}
+		cloned = append(cloned, ClonedTask{SourceTaskID: task.ID.Hex(), TaskID: copied.ID.Hex()})
+
+		set := bson.M{"members": []models.Member{}}
+		if request.CopyMembers && len(task.Members) > 0 {
+			set["members"] = task.Members
+		}
+		if len(task.Labels) > 0 {
+			set["labels"] = task.Labels
+		}
+		if task.StoryPoints > 0 {
+			set["storyPoints"] = task.StoryPoints
+		}
+		if task.DueDate != nil {
+			set["dueDate"] = task.DueDate.Add(request.DueDateShift).UTC()
+		}
+		if len(task.Subtasks) > 0 {
+			subtasks := make([]models.Subtask, 0, len(task.Subtasks))
+			for _, subtask := range task.Subtasks {
+				subtasks = append(subtasks, models.Subtask{ID: primitive.NewObjectID(), Title: subtask.Title})
+			}
+			set["subtasks"] = subtasks
+		}
+		if _, err := s.tasksCollection.UpdateOne(ctx, bson.M{"_id": copied.ID}, bson.M{"$set": set}); err != nil {
+			logging.Logger.Errorf("Event ID: TASK_CLONE_UPDATE_FAILED, Description: Failed to set cloned details on task %s: %v", copied.ID.Hex(), err)
+			return cloned, fmt.Errorf("failed to copy details of task %s: %v", task.ID.Hex(), err)
+		}
+	}
+
+	logging.Logger.Infof("Event ID: PROJECT_TASKS_CLONED, Description: Cloned %d tasks from project %s into %s.", len(cloned), sourceProjectID, request.TargetProjectID)
+	return cloned, nil
+}
+
+// DiscardClonedTasks trajno briše zadatke i istoriju projekta čije kloniranje nije uspelo.
+// Za kopije su task.created događaji već objavljeni, pa se za svaku objavljuje
+// kompenzujući task.deleted, bez zapisa u istoriji i bez aktivnosti projekta.
+func (s *TaskService) DiscardClonedTasks(ctx context.Context, projectID string) (int64, error) {
+	if _, err := primitive.ObjectIDFromHex(projectID); err != nil {
+		return 0, fmt.Errorf("invalid project ID format")
+	}
+
+	cursor, err := s.tasksCollection.Find(ctx, bson.M{"projectId": projectID}, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return 0, fmt.Errorf("failed to find cloned tasks: %v", err)
+	}
+	var copies []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &copies); err != nil {
+		return 0, fmt.Errorf("failed to find cloned tasks: %v", err)
+	}
+	if len(copies) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(copies))
+	for _, task := range copies {
+		ids = append(ids, task.ID)
+	}
+	var deleted int64
+	err = s.inTransaction(ctx, func(ctx context.Context) error {
+		result, err := s.tasksCollection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+		if err != nil {
+			return err
+		}
+		deleted = result.DeletedCount
+		if _, err := s.historyCollection.DeleteMany(ctx, bson.M{"taskId": bson.M{"$in": ids}}); err != nil {
+			return err
+		}
+		for _, id := range ids {
+			err := s.publishHistoryEvent(ctx, models.TaskHistoryEntry{
+				ID:           primitive.NewObjectID(),
+				TaskID:       id,
+				ProjectID:    projectID,
+				ActivityType: models.HistoryDeleteTask,
+				Actor:        CloneRollbackActor,
+				Timestamp:    time.Now().UTC(),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logging.Logger.Errorf("Event ID: CLONED_TASKS_DISCARD_FAILED, Description: Failed to delete cloned tasks of project %s: %v", projectID, err)
+		return 0, fmt.Errorf("failed to delete cloned tasks: %v", err)
+	}
+
+	logging.Logger.Infof("Event ID: CLONED_TASKS_DISCARDED, Description: Permanently deleted %d cloned tasks of project %s.", deleted, projectID)
+	return deleted, nil
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"trello-project/microservices/tasks-service/logging"
+	"trello-project/microservices/tasks-service/models"
+
+	"trello-project/backend/utils/events"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// historyEventTypes preslikava zapise istorije u domenske događaje. ID događaja je ID
+// zapisa istorije, pa isti zapis uvek daje isti događaj.
+var historyEventTypes = map[models.HistoryAction]events.Type{
+	models.HistoryCreateTask:       events.TaskCreated,
+	models.HistoryChangeTaskStatus: events.TaskStatusChanged,
+	models.HistoryAddMember:        events.TaskMemberAdded,
+	models.HistoryRemoveMember:     events.TaskMemberRemoved,
+	models.HistoryArchiveTask:      events.TaskArchived,
+	models.HistoryDeleteTask:       events.TaskDeleted,
+	models.HistoryRestoreTask:      events.TaskRestored,
+}
+
+func historyEvent(entry models.TaskHistoryEntry) (events.Event, bool) {
+	eventType, ok := historyEventTypes[entry.ActivityType]
+	if entry.ActivityType == models.HistoryUpdateTask && entry.Field == "projectId" {
+		eventType, ok = events.TaskMoved, true
+	}
+	if !ok {
+		return events.Event{}, false
+	}
+
+	event := events.Event{
+		ID:         entry.ID.Hex(),
+		Type:       eventType,
+		ProjectID:  entry.ProjectID,
+		TaskID:     entry.TaskID.Hex(),
+		Actor:      entry.Actor,
+		OccurredAt: entry.Timestamp,
+	}
+	if entry.MemberID != nil {
+		event.MemberID = entry.MemberID.Hex()
+	}
+	if entry.Field == "status" {
+		event.OldStatus = entry.OldValue
+		event.NewStatus = entry.NewValue
+	}
+	return event, true
+}
+
+// publishHistoryEvent upisuje događaj za zapis istorije u outbox i, kada se izmena
+// potvrdi, pokušava odmah da ga objavi; ako broker nije dostupan, objaviće ga
+// StartEventRelay.
+func (s *TaskService) publishHistoryEvent(ctx context.Context, entry models.TaskHistoryEntry) error {
+	if s.eventOutbox == nil {
+		return nil
+	}
+	event, ok := historyEvent(entry)
+	if !ok {
+		return nil
+	}
+	if err := s.eventOutbox.Add(ctx, event); err != nil {
+		logging.Logger.Errorf("Event ID: DOMAIN_EVENT_STORE_FAILED, Description: Failed to store %s event for task %s: %v", event.Type, event.TaskID, err)
+		return fmt.Errorf("failed to store %s event: %v", event.Type, err)
+	}
+	afterCommit(ctx, func() {
+		go func() {
+			if err := s.eventOutbox.Send(context.Background(), event); err != nil {
+				logging.Logger.Warnf("Event ID: DOMAIN_EVENT_PUBLISH_DEFERRED, Description: Event %s will be published by the relay: %v", event.ID, err)
+			}
+		}()
+	})
+	return nil
+}
+
+type afterCommitKey struct{}
+
+// inTransaction izvršava write tako da izmena zadatka, zapisi istorije i domenski
+// događaji budu sačuvani u istoj transakciji: pad servisa posle izmene ne može da izgubi
+// događaj. Objava događaja i aktivnosti projekta čekaju potvrdu transakcije. write može
+// biti ponovljen, pa pozivi drugih servisa i obaveštenja idu posle inTransaction. Bez
+// outbox-a (NATS nije podešen) write se izvršava bez transakcije.
+func (s *TaskService) inTransaction(ctx context.Context, write func(ctx context.Context) error) error {
+	if s.eventOutbox == nil {
+		return write(ctx)
+	}
+	var pending *[]func()
+	err := s.eventOutbox.Transaction(ctx, func(txCtx mongo.SessionContext) error {
+		actions := []func(){}
+		pending = &actions
+		return write(context.WithValue(txCtx, afterCommitKey{}, pending))
+	})
+	if err != nil {
+		return err
+	}
+	for _, action := range *pending {
+		action()
+	}
+	return nil
+}
+
+// afterCommit izvršava action odmah, odnosno posle potvrde ako je ctx u inTransaction.
+func afterCommit(ctx context.Context, action func()) {
+	if pending, ok := ctx.Value(afterCommitKey{}).(*[]func()); ok {
+		*pending = append(*pending, action)
+		return
+	}
+	action()
+}
+
+// StartEventRelay periodično objavljuje događaje iz outbox-a koji nisu objavljeni odmah.
+func (s *TaskService) StartEventRelay(ctx context.Context, interval time.Duration) {
+	if s.eventOutbox == nil {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				published, err := s.eventOutbox.Relay(ctx, interval, 500)
+				if published > 0 {
+					logging.Logger.Infof("Event ID: DOMAIN_EVENTS_RELAYED, Description: Published %d pending domain events", published)
+				}
+				if err != nil {
+					logging.Logger.Warnf("Event ID: DOMAIN_EVENT_RELAY_FAILED, Description: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// BackfillEvents upisuje u outbox događaje za celu postojeću istoriju zadataka, da bi
+// analytics-service imao i podatke nastale pre uvođenja događaja. Ponovno pokretanje je
+// bezbedno jer se događaji sa istim ID-jem ne dupliraju.
+func (s *TaskService) BackfillEvents(ctx context.Context) (int, error) {
+	if s.eventOutbox == nil {
+		return 0, nil
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := s.historyCollection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	count := 0
+	for cursor.Next(ctx) {
+		var entry models.TaskHistoryEntry
+		if err := cursor.Decode(&entry); err != nil {
+			return count, err
+		}
+		event, ok := historyEvent(entry)
+		if !ok {
+			continue
+		}
+		if err := s.eventOutbox.Add(ctx, event); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, cursor.Err()
+}
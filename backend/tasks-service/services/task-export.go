@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"trello-project/microservices/tasks-service/logging"
+	"trello-project/microservices/tasks-service/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ExportProjectTasks prolazi kroz zadatke projekta istim redom kao GetTasksByProjectID
+// i za svaki poziva emit, tako da handler upisuje red po red bez baferisanja izvoza.
+func (s *TaskService) ExportProjectTasks(ctx context.Context, projectID string, emit func(models.TaskExportRow) error) error {
+	dependencies := s.getProjectDependencies(projectID)
+
+	statusHistory, err := s.loadStatusHistory(ctx, projectID)
+	if err != nil {
+		return err
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "rank", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := s.tasksCollection.Find(ctx, activeTaskFilter(bson.M{"projectId": projectID}), opts)
+	if err != nil {
+		logging.Logger.Errorf("Event ID: TASKS_EXPORT_FETCH_FAILED, Description: Failed to find tasks for export of project %s: %v", projectID, err)
+		return fmt.Errorf("failed to find tasks: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	exported := 0
+	now := time.Now().UTC()
+	for cursor.Next(ctx) {
+		var task models.Task
+		if err := cursor.Decode(&task); err != nil {
+			return fmt.Errorf("failed to decode task: %v", err)
+		}
+		row := buildExportRow(&task, dependencies[task.ID.Hex()], statusHistory[task.ID.Hex()], now)
+		if err := emit(row); err != nil {
+			return err
+		}
+		exported++
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("cursor error: %v", err)
+	}
+
+	logging.Logger.Infof("Event ID: TASKS_EXPORTED, Description: Exported %d tasks of project %s.", exported, projectID)
+	return nil
+}
+
+func buildExportRow(task *models.Task, dependsOn []string, history []models.TaskHistoryEntry, now time.Time) models.TaskExportRow {
+	row := models.TaskExportRow{
+		ID:            task.ID.Hex(),
+		Title:         html.UnescapeString(task.Title),
+		Description:   html.UnescapeString(task.Description),
+		Status:        task.Status,
+		Members:       []string{},
+		Labels:        []string{},
+		DependsOn:     dependsOn,
+		DueDate:       task.DueDate,
+		CreatedAt:     task.ID.Timestamp().UTC(),
+		HoursInStatus: map[string]float64{},
+		SubtasksTotal: len(task.Subtasks),
+	}
+	if row.DependsOn == nil {
+		row.DependsOn = []string{}
+	}
+	for _, member := range task.Members {
+		row.Members = append(row.Members, member.Username)
+		if member.Assignment == models.AssignmentResponsible {
+			row.Responsible = member.Username
+		}
+	}
+	for _, label := range task.Labels {
+		row.Labels = append(row.Labels, html.UnescapeString(label))
+	}
+	for _, subtask := range task.Subtasks {
+		if subtask.Done {
+			row.SubtasksDone++
+		}
+	}
+
+	// Vreme po statusu se računa iz istorije promena statusa; stariji zadaci bez
+	// istorije se vode kao da su od kreiranja u trenutnom statusu.
+	status := string(task.Status)
+	since := row.CreatedAt
+	if len(history) > 0 {
+		first := history[0]
+		if first.ActivityType == models.HistoryCreateTask {
+			status = first.NewValue
+			since = first.Timestamp
+			history = history[1:]
+		} else {
+			status = first.OldValue
+		}
+	}
+	for _, entry := range history {
+		row.HoursInStatus[status] += entry.Timestamp.Sub(since).Hours()
+		status = entry.NewValue
+		since = entry.Timestamp
+
+		at := entry.Timestamp.UTC()
+		if entry.NewValue == string(models.StatusInProgress) && row.StartedAt == nil {
+			row.StartedAt = &at
+		}
+		if entry.NewValue == string(models.StatusCompleted) {
+			row.CompletedAt = &at
+		}
+	}
+	if now.After(since) {
+		row.HoursInStatus[status] += now.Sub(since).Hours()
+	}
+	if task.Status != models.StatusCompleted {
+		row.CompletedAt = nil
+	}
+	return row
+}
+
+// loadStatusHistory učitava kreiranja i promene statusa svih zadataka projekta,
+// grupisane po zadatku i sortirane hronološki.
+func (s *TaskService) loadStatusHistory(ctx context.Context, projectID string) (map[string][]models.TaskHistoryEntry, error) {
+	filter := bson.M{
+		"projectId":    projectID,
+		"activityType": bson.M{"$in": []models.HistoryAction{models.HistoryCreateTask, models.HistoryChangeTaskStatus}},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := s.historyCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch task history: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	byTask := make(map[string][]models.TaskHistoryEntry)
+	for cursor.Next(ctx) {
+		var entry models.TaskHistoryEntry
+		if err := cursor.Decode(&entry); err != nil {
+			return nil, fmt.Errorf("failed to decode task history: %v", err)
+		}
+		byTask[entry.TaskID.Hex()] = append(byTask[entry.TaskID.Hex()], entry)
+	}
+	return byTask, cursor.Err()
+}
+
+// dependencyRelation je veza iz workflow-service-a: ToTaskID zavisi od FromTaskID.
+type dependencyRelation struct {
+	FromTaskID string `json:"fromTaskId"`
+	ToTaskID   string `json:"toTaskId"`
+}
+
+// getProjectDependencies vraća mapu zadatak -> zadaci od kojih zavisi. Ako
+// workflow-service nije dostupan, izvoz se nastavlja bez zavisnosti.
+func (s *TaskService) getProjectDependencies(projectID string) map[string][]string {
+	dependencies := make(map[string][]string)
+
+	result, err := s.WorkflowBreaker.Execute(func() (interface{}, error) {
+		baseURL := os.Getenv("WORKFLOW_SERVICE_URL")
+		if baseURL == "" {
+			return nil, fmt.Errorf("WORKFLOW_SERVICE_URL not set in environment")
+		}
+
+		url := fmt.Sprintf("%s/api/workflow/project/%s/dependencies", strings.TrimRight(baseURL, "/"), projectID)
+		resp, err := s.httpClient.Get(url)
+		if err != nil {
+			return nil, fmt.Errorf("failed to contact workflow-service: %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("workflow-service returned status: %d", resp.StatusCode)
+		}
+
+		var relations []dependencyRelation
+		if err := json.NewDecoder(resp.Body).Decode(&relations); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %v", err)
+		}
+		return relations, nil
+	})
+	if err != nil {
+		logging.Logger.Warnf("Event ID: TASKS_EXPORT_DEPENDENCIES_UNAVAILABLE, Description: Exporting project %s without dependencies: %v", projectID, err)
+		return dependencies
+	}
+
+	for _, relation := range result.([]dependencyRelation) {
+		dependencies[relation.ToTaskID] = append(dependencies[relation.ToTaskID], relation.FromTaskID)
+	}
+	return dependencies
+}
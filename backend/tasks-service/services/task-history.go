@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"time"
+
+	"trello-project/microservices/tasks-service/logging"
+	"trello-project/microservices/tasks-service/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// recordHistory upisuje zapis u istoriju zadatka i domenski događaj za njega. Van
+// transakcije se greška samo loguje, jer istorija ne sme da obori samu izmenu zadatka; u
+// transakciji (inTransaction) greška poništava i izmenu, da događaj ne bi bio izgubljen.
+func (s *TaskService) recordHistory(ctx context.Context, entry models.TaskHistoryEntry) error {
+	entry.ID = primitive.NewObjectID()
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now().UTC()
+	}
+	if entry.Actor == "" {
+		entry.Actor = "system"
+	}
+
+	if _, err := s.historyCollection.InsertOne(ctx, entry); err != nil {
+		logging.Logger.Errorf("Event ID: TASK_HISTORY_WRITE_FAILED, Description: Failed to record %s for task %s: %v", entry.ActivityType, entry.TaskID.Hex(), err)
+		return fmt.Errorf("failed to record task history: %v", err)
+	}
+	logging.Logger.Debugf("Event ID: TASK_HISTORY_RECORDED, Description: Recorded %s for task %s by %s", entry.ActivityType, entry.TaskID.Hex(), entry.Actor)
+	afterCommit(ctx, func() { s.publishProjectActivity(entry) })
+	return s.publishHistoryEvent(ctx, entry)
+}
+
+// recordMemberHistory beleži dodavanje ili uklanjanje člana sa zadatka.
+func (s *TaskService) recordMemberHistory(ctx context.Context, task *models.Task, action models.HistoryAction, member models.Member, actor string) error {
+	memberID := member.ID
+	details := fmt.Sprintf("Member %s added to task '%s'", member.Username, task.Title)
+	if action == models.HistoryRemoveMember {
+		details = fmt.Sprintf("Member %s removed from task '%s'", member.Username, task.Title)
+	}
+	return s.recordHistory(ctx, models.TaskHistoryEntry{
+		TaskID:       task.ID,
+		ProjectID:    task.ProjectID,
+		ActivityType: action,
+		MemberID:     &memberID,
+		Actor:        actor,
+		Field:        "members",
+		Details:      details,
+	})
+}
+
+// GetProjectStatusHistory vraća kreiranja i promene statusa zadataka projekta, grupisane po
+// zadatku i sortirane hronološki; koristi ga analytics-service.
+func (s *TaskService) GetProjectStatusHistory(ctx context.Context, projectID string) (map[string][]models.TaskHistoryEntry, error) {
+	return s.loadStatusHistory(ctx, projectID)
+}
+
+// GetTaskHistory vraća istoriju zadatka hronološki.
+func (s *TaskService) GetTaskHistory(ctx context.Context, taskID primitive.ObjectID) ([]models.TaskHistoryEntry, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := s.historyCollection.Find(ctx, bson.M{"taskId": taskID}, opts)
+	if err != nil {
+		logging.Logger.Errorf("Event ID: TASK_HISTORY_FETCH_FAILED, Description: Failed to fetch history for task %s: %v", taskID.Hex(), err)
+		return nil, fmt.Errorf("failed to fetch task history: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	history := []models.TaskHistoryEntry{}
+	if err := cursor.All(ctx, &history); err != nil {
+		logging.Logger.Errorf("Event ID: TASK_HISTORY_DECODE_FAILED, Description: Failed to decode history for task %s: %v", taskID.Hex(), err)
+		return nil, fmt.Errorf("failed to decode task history: %v", err)
+	}
+
+	logging.Logger.Infof("Event ID: TASK_HISTORY_RETRIEVED, Description: Retrieved %d history entries for task %s", len(history), taskID.Hex())
+	return history, nil
+}
+
+// UpdateTask menja naslov i/ili opis zadatka i beleži svaku izmenjenu vrednost u istoriji.
+func (s *TaskService) UpdateTask(ctx context.Context, taskID primitive.ObjectID, title, description *string, actor string) (*models.Task, error) {
+	var task models.Task
+	if err := s.tasksCollection.FindOne(ctx, bson.M{"_id": taskID}).Decode(&task); err != nil {
+		logging.Logger.Warnf("Event ID: TASK_NOT_FOUND, Description: Task %s not found for update: %v", taskID.Hex(), err)
+		return nil, fmt.Errorf("task not found")
+	}
+	if err := ensureTaskActive(&task); err != nil {
+		return nil, err
+	}
+
+	set := bson.M{}
+	var changes []models.TaskHistoryEntry
+	if title != nil {
+		sanitized := html.EscapeString(*title)
+		if sanitized == "" {
+			return nil, fmt.Errorf("task title must not be empty")
+		}
+		if sanitized != task.Title {
+			set["title"] = sanitized
+			changes = append(changes, models.TaskHistoryEntry{Field: "title", OldValue: task.Title, NewValue: sanitized})
+		}
+	}
+	if description != nil {
+		sanitized := html.EscapeString(*description)
+		if sanitized != task.Description {
+			set["description"] = sanitized
+			changes = append(changes, models.TaskHistoryEntry{Field: "description", OldValue: task.Description, NewValue: sanitized})
+		}
+	}
+
+	if len(set) == 0 {
+		return &task, nil
+	}
+
+	if _, err := s.tasksCollection.UpdateOne(ctx, bson.M{"_id": taskID}, bson.M{"$set": set}); err != nil {
+		logging.Logger.Errorf("Event ID: TASK_UPDATE_FAILED, Description: Failed to update task %s: %v", taskID.Hex(), err)
+		return nil, fmt.Errorf("failed to update task: %v", err)
+	}
+
+	for _, change := range changes {
+		change.TaskID = task.ID
+		change.ProjectID = task.ProjectID
+		change.ActivityType = models.HistoryUpdateTask
+		change.Actor = actor
+		change.Details = fmt.Sprintf("Field '%s' of task '%s' changed", change.Field, task.Title)
+		s.recordHistory(ctx, change)
+	}
+
+	if err := s.tasksCollection.FindOne(ctx, bson.M{"_id": taskID}).Decode(&task); err != nil {
+		return nil, fmt.Errorf("failed to fetch updated task: %v", err)
+	}
+	logging.Logger.Infof("Event ID: TASK_UPDATED, Description: Task %s updated by %s (%d fields changed).", taskID.Hex(), actor, len(changes))
+	return &task, nil
+}
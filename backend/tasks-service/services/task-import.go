@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"time"
+
+	"trello-project/microservices/tasks-service/logging"
+	"trello-project/microservices/tasks-service/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ImportedTask je zadatak pripremljen za uvoz iz spoljnog alata (npr. Trello).
+// SourceID je ID u izvornom alatu i vraća se u rezultatu radi mapiranja.
+type ImportedTask struct {
+	SourceID    string            `json:"sourceId"`
+	Title       string            `json:"title"`
+	Description string            `json:"description"`
+	Status      models.TaskStatus `json:"status"`
+	Members     []models.Member   `json:"members"`
+	Labels      []string          `json:"labels"`
+	DueDate     *time.Time        `json:"dueDate"`
+	Subtasks    []models.Subtask  `json:"subtasks"`
+	Comments    []models.Comment  `json:"comments"`
+}
+
+type ImportedTaskResult struct {
+	SourceID string `json:"sourceId"`
+	TaskID   string `json:"taskId,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// ImportTasks kreira zadatke u projektu kroz CreateTask, a zatim im postavlja članove,
+// oznake, rok, podzadatke i komentare. Uvoz ne šalje notifikacije članovima.
+func (s *TaskService) ImportTasks(ctx context.Context, projectID string, tasks []ImportedTask, actor string) ([]ImportedTaskResult, error) {
+	if _, err := primitive.ObjectIDFromHex(projectID); err != nil {
+		return nil, fmt.Errorf("invalid project ID format")
+	}
+
+	results := make([]ImportedTaskResult, 0, len(tasks))
+	for _, imported := range tasks {
+		result := ImportedTaskResult{SourceID: imported.SourceID}
+
+		status := imported.Status
+		if !models.IsValidTaskStatus(status) {
+			status = models.StatusPending
+		}
+		task, err := s.CreateTask(projectID, imported.Title, imported.Description, status, actor)
+		if err != nil {
+			result.Error = err.Error()
+			results = append(results, result)
+			continue
+		}
+		result.TaskID = task.ID.Hex()
+
+		set := bson.M{"members": importedMembers(imported.Members)}
+		if labels := normalizeLabels(imported.Labels); len(labels) > 0 {
+			set["labels"] = labels
+		}
+		if imported.DueDate != nil {
+			set["dueDate"] = imported.DueDate.UTC()
+		}
+		if len(imported.Subtasks) > 0 {
+			set["subtasks"] = importedSubtasks(imported.Subtasks)
+		}
+		if len(imported.Comments) > 0 {
+			set["comments"] = importedComments(imported.Comments)
+		}
+		if _, err := s.tasksCollection.UpdateOne(ctx, bson.M{"_id": task.ID}, bson.M{"$set": set}); err != nil {
+			logging.Logger.Errorf("Event ID: TASK_IMPORT_UPDATE_FAILED, Description: Failed to set imported details on task %s: %v", task.ID.Hex(), err)
+			result.Error = fmt.Sprintf("task created but details were not saved: %v", err)
+		}
+		results = append(results, result)
+	}
+
+	logging.Logger.Infof("Event ID: TASKS_IMPORTED, Description: Imported %d tasks into project %s.", len(results), projectID)
+	return results, nil
+}
+
+func importedMembers(members []models.Member) []models.Member {
+	result := []models.Member{}
+	seen := make(map[string]bool)
+	for _, member := range members {
+		if seen[memberKey(member)] {
+			continue
+		}
+		seen[memberKey(member)] = true
+		member.Assignment = models.AssignmentContributor
+		result = append(result, member)
+	}
+	return result
+}
+
+func importedSubtasks(subtasks []models.Subtask) []models.Subtask {
+	result := make([]models.Subtask, 0, len(subtasks))
+	for _, subtask := range subtasks {
+		result = append(result, models.Subtask{
+			ID:    primitive.NewObjectID(),
+			Title: html.EscapeString(subtask.Title),
+			Done:  subtask.Done,
+		})
+	}
+	return result
+}
+
+func importedComments(comments []models.Comment) []models.Comment {
+	result := make([]models.Comment, 0, len(comments))
+	for _, comment := range comments {
+		createdAt := comment.CreatedAt
+		if createdAt.IsZero() {
+			createdAt = time.Now()
+		}
+		result = append(result, models.Comment{
+			ID:        primitive.NewObjectID(),
+			Author:    comment.Author,
+			Text:      html.EscapeString(comment.Text),
+			CreatedAt: createdAt.UTC(),
+		})
+	}
+	return result
+}
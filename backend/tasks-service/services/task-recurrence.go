@@ -0,0 +1,303 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"trello-project/microservices/tasks-service/logging"
+	"trello-project/microservices/tasks-service/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SetTaskRecurrence postavlja rok i pravilo ponavljanja zadatka. Nil recurrence
+// uklanja pravilo, pa se serija završava sa ovim zadatkom.
+func (s *TaskService) SetTaskRecurrence(ctx context.Context, taskID primitive.ObjectID, dueDate *time.Time, recurrence *models.Recurrence) (*models.Task, error) {
+	var task models.Task
+	if err := s.tasksCollection.FindOne(ctx, bson.M{"_id": taskID}).Decode(&task); err != nil {
+		return nil, fmt.Errorf("task not found")
+	}
+	if err := ensureTaskActive(&task); err != nil {
+		return nil, err
+	}
+
+	set := bson.M{}
+	unset := bson.M{}
+	if dueDate != nil {
+		set["dueDate"] = dueDate.UTC()
+	}
+	if recurrence != nil {
+		if err := recurrence.Normalize(); err != nil {
+			return nil, err
+		}
+		set["recurrence"] = recurrence
+		if task.Occurrence == 0 {
+			set["occurrence"] = 1
+		}
+	} else {
+		unset["recurrence"] = ""
+	}
+
+	update := bson.M{}
+	if len(set) > 0 {
+		update["$set"] = set
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+	if _, err := s.tasksCollection.UpdateOne(ctx, bson.M{"_id": taskID}, update); err != nil {
+		logging.Logger.Errorf("Event ID: TASK_RECURRENCE_UPDATE_FAILED, Description: Failed to update recurrence for task %s: %v", taskID.Hex(), err)
+		return nil, fmt.Errorf("failed to update task recurrence: %v", err)
+	}
+
+	if err := s.tasksCollection.FindOne(ctx, bson.M{"_id": taskID}).Decode(&task); err != nil {
+		return nil, fmt.Errorf("failed to fetch updated task: %v", err)
+	}
+	logging.Logger.Infof("Event ID: TASK_RECURRENCE_UPDATED, Description: Recurrence for task %s updated.", taskID.Hex())
+	return &task, nil
+}
+
+// StartRecurrenceScheduler periodično kreira sledeća pojavljivanja za zadatke
+// kojima je istekao rok. Zaustavlja se kada se ctx otkaže.
+func (s *TaskService) StartRecurrenceScheduler(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.spawnDueOccurrences(ctx)
+			}
+		}
+	}()
+	logging.Logger.Infof("Event ID: RECURRENCE_SCHEDULER_STARTED, Description: Recurrence scheduler running every %s.", interval)
+}
+
+func (s *TaskService) spawnDueOccurrences(ctx context.Context) {
+	filter := activeTaskFilter(bson.M{
+		"recurrence":        bson.M{"$exists": true},
+		"recurrenceSpawned": bson.M{"$ne": true},
+		"dueDate":           bson.M{"$lte": time.Now().UTC()},
+	})
+	cursor, err := s.tasksCollection.Find(ctx, filter)
+	if err != nil {
+		logging.Logger.Errorf("Event ID: RECURRENCE_SCAN_FAILED, Description: Failed to find due recurring tasks: %v", err)
+		return
+	}
+	var due []models.Task
+	if err := cursor.All(ctx, &due); err != nil {
+		logging.Logger.Errorf("Event ID: RECURRENCE_SCAN_FAILED, Description: Failed to decode due recurring tasks: %v", err)
+		return
+	}
+
+	// Serije u arhiviranim projektima miruju dok se projekat ne vrati iz arhive
+	archived := make(map[string]bool)
+	for i := range due {
+		projectID := due[i].ProjectID
+		paused, checked := archived[projectID]
+		if !checked {
+			paused, err = s.isProjectArchived(projectID)
+			if err != nil {
+				logging.Logger.Warnf("Event ID: RECURRENCE_PROJECT_CHECK_FAILED, Description: Skipping recurring tasks of project %s: %v", projectID, err)
+				paused = true
+			}
+			archived[projectID] = paused
+		}
+		if paused {
+			continue
+		}
+		if _, err := s.spawnNextOccurrence(ctx, &due[i]); err != nil {
+			logging.Logger.Errorf("Event ID: RECURRENCE_SPAWN_FAILED, Description: Failed to create next occurrence of task %s: %v", due[i].ID.Hex(), err)
+		}
+	}
+}
+
+// isProjectArchived proverava u projects-service-u da li je projekat arhiviran.
+func (s *TaskService) isProjectArchived(projectID string) (bool, error) {
+	projectsURL := os.Getenv("PROJECTS_SERVICE_URL")
+	if projectsURL == "" {
+		return false, fmt.Errorf("PROJECTS_SERVICE_URL not set")
+	}
+
+	url := fmt.Sprintf("%s/api/projects/%s", strings.TrimRight(projectsURL, "/"), projectID)
+	result, err := s.ProjectsBreaker.Execute(func() (interface{}, error) {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Role", "manager")
+		resp, err := s.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			return nil, fmt.Errorf("projects-service error: %s", string(body))
+		}
+
+		var project struct {
+			ArchivedAt *time.Time `json:"archivedAt"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&project); err != nil {
+			return nil, fmt.Errorf("failed to decode project: %v", err)
+		}
+		return project.ArchivedAt != nil, nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to verify project: %v", err)
+	}
+	return result.(bool), nil
+}
+
+// spawnNextOccurrence kreira sledeće pojavljivanje ponavljajućeg zadatka. Zadatak se
+// prvo atomski označava kao obrađen, tako da završetak i istek roka ne mogu oba da
+// naprave novo pojavljivanje. Vraća nil ako je serija završena ili je već obrađena.
+func (s *TaskService) spawnNextOccurrence(ctx context.Context, task *models.Task) (*models.Task, error) {
+	if task.Recurrence == nil {
+		return nil, nil
+	}
+
+	claim, err := s.tasksCollection.UpdateOne(ctx,
+		bson.M{"_id": task.ID, "recurrenceSpawned": bson.M{"$ne": true}},
+		bson.M{"$set": bson.M{"recurrenceSpawned": true}},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim task: %v", err)
+	}
+	if claim.ModifiedCount == 0 {
+		return nil, nil
+	}
+
+	// Propušteni termini se preskaču, da zakasneli završetak ne bi napravio niz zadataka
+	now := time.Now().UTC()
+	base := now
+	if task.DueDate != nil {
+		base = *task.DueDate
+	}
+	due := task.Recurrence.Next(base)
+	for !due.After(now) {
+		due = task.Recurrence.Next(due)
+	}
+
+	occurrence := task.Occurrence
+	if occurrence == 0 {
+		occurrence = 1
+	}
+	occurrence++
+	if task.Recurrence.Exhausted(occurrence, due) {
+		logging.Logger.Infof("Event ID: RECURRENCE_SERIES_ENDED, Description: Recurring series of task %s has ended.", task.ID.Hex())
+		return nil, nil
+	}
+
+	// CreateTask ponovo escape-uje naslov i opis, pa se koriste originalne vrednosti
+	next, err := s.CreateTask(task.ProjectID, html.UnescapeString(task.Title), html.UnescapeString(task.Description), models.StatusPending, "system")
+	if err != nil {
+		s.releaseRecurrenceClaim(ctx, task.ID)
+		return nil, err
+	}
+
+	seriesID := task.ID
+	if task.RecurrenceSeriesID != nil {
+		seriesID = *task.RecurrenceSeriesID
+	}
+	members := task.Members
+	if members == nil {
+		members = []models.Member{}
+	}
+	_, err = s.tasksCollection.UpdateOne(ctx, bson.M{"_id": next.ID}, bson.M{"$set": bson.M{
+		"members":            members,
+		"dueDate":            due,
+		"recurrence":         task.Recurrence,
+		"recurrenceSeriesId": seriesID,
+		"occurrence":         occurrence,
+	}})
+	if err != nil {
+		s.releaseRecurrenceClaim(ctx, task.ID)
+		return nil, fmt.Errorf("failed to set up next occurrence: %v", err)
+	}
+	next.Members = members
+	next.DueDate = &due
+	next.Recurrence = task.Recurrence
+	next.RecurrenceSeriesID = &seriesID
+	next.Occurrence = occurrence
+
+	s.copyOpenDependencies(ctx, task.ID, next.ID)
+
+	message := fmt.Sprintf("A new occurrence of the recurring task '%s' is due on %s", next.Title, due.Format("2006-01-02"))
+	for _, member := range members {
+		s.notifyAsync(member, message)
+	}
+
+	logging.Logger.Infof("Event ID: RECURRENCE_SPAWNED, Description: Created occurrence %d (%s) of task %s, due %s.", occurrence, next.ID.Hex(), seriesID.Hex(), due.Format(time.RFC3339))
+	return next, nil
+}
+
+func (s *TaskService) releaseRecurrenceClaim(ctx context.Context, taskID primitive.ObjectID) {
+	if _, err := s.tasksCollection.UpdateOne(ctx, bson.M{"_id": taskID}, bson.M{"$unset": bson.M{"recurrenceSpawned": ""}}); err != nil {
+		logging.Logger.Errorf("Event ID: RECURRENCE_RELEASE_FAILED, Description: Failed to release recurrence claim for task %s: %v", taskID.Hex(), err)
+	}
+}
+
+// copyOpenDependencies prenosi zavisnosti na novo pojavljivanje, ali samo one čiji
+// zadatak još nije završen; završene zavisnosti novo pojavljivanje ne bi blokirale.
+func (s *TaskService) copyOpenDependencies(ctx context.Context, fromTaskID, toTaskID primitive.ObjectID) {
+	dependencyIDs, _ := s.getDependenciesFromWorkflow(fromTaskID.Hex())
+	for _, dependencyID := range dependencyIDs {
+		dependencyObjectID, err := primitive.ObjectIDFromHex(dependencyID)
+		if err != nil {
+			continue
+		}
+		var dependency models.Task
+		if err := s.tasksCollection.FindOne(ctx, bson.M{"_id": dependencyObjectID}).Decode(&dependency); err != nil {
+			continue
+		}
+		if dependency.Status == models.StatusCompleted || !dependency.IsActive() {
+			continue
+		}
+		if err := s.addDependencyInWorkflow(dependencyID, toTaskID.Hex()); err != nil {
+			logging.Logger.Warnf("Event ID: RECURRENCE_DEPENDENCY_COPY_FAILED, Description: Failed to copy dependency %s -> %s: %v", dependencyID, toTaskID.Hex(), err)
+		}
+	}
+}
+
+// addDependencyInWorkflow dodaje zavisnost toTaskID -> fromTaskID u workflow-service.
+func (s *TaskService) addDependencyInWorkflow(fromTaskID, toTaskID string) error {
+	workflowURL := os.Getenv("WORKFLOW_SERVICE_URL")
+	if workflowURL == "" {
+		return fmt.Errorf("WORKFLOW_SERVICE_URL not set")
+	}
+
+	body, _ := json.Marshal(map[string]string{"fromTaskId": fromTaskID, "toTaskId": toTaskID})
+	url := fmt.Sprintf("%s/api/workflow/dependency", strings.TrimRight(workflowURL, "/"))
+
+	_, err := s.WorkflowBreaker.Execute(func() (interface{}, error) {
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := s.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusConflict {
+			respBody, _ := io.ReadAll(resp.Body)
+			return nil, fmt.Errorf("workflow-service error: %s", string(respBody))
+		}
+		return nil, nil
+	})
+	return err
+}
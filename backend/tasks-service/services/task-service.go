@@ -11,19 +11,29 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"time"
 
 	"trello-project/microservices/tasks-service/logging"
 
 	"trello-project/microservices/tasks-service/models"
 
+	"trello-project/backend/utils/events"
+	"trello-project/backend/utils/projectroles"
+
 	"github.com/sony/gobreaker"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type TaskService struct {
-	tasksCollection      *mongo.Collection
+	tasksCollection     *mongo.Collection
+	settingsCollection  *mongo.Collection
+	historyCollection   *mongo.Collection
+	templatesCollection *mongo.Collection
+	// eventOutbox je nil kada NATS nije podešen; tada se domenski događaji ne objavljuju
+	eventOutbox          *events.Outbox
 	httpClient           *http.Client
 	ProjectsBreaker      *gobreaker.CircuitBreaker
 	NotificationsBreaker *gobreaker.CircuitBreaker
@@ -32,6 +42,10 @@ type TaskService struct {
 
 func NewTaskService(
 	tasksCollection *mongo.Collection,
+	settingsCollection *mongo.Collection,
+	historyCollection *mongo.Collection,
+	templatesCollection *mongo.Collection,
+	eventOutbox *events.Outbox,
 	httpClient *http.Client,
 	projectsBreaker *gobreaker.CircuitBreaker,
 	notificationsBreaker *gobreaker.CircuitBreaker,
@@ -40,6 +54,10 @@ func NewTaskService(
 ) *TaskService {
 	return &TaskService{
 		tasksCollection:      tasksCollection,
+		settingsCollection:   settingsCollection,
+		historyCollection:    historyCollection,
+		templatesCollection:  templatesCollection,
+		eventOutbox:          eventOutbox,
 		httpClient:           httpClient,
 		ProjectsBreaker:      projectsBreaker,
 		NotificationsBreaker: notificationsBreaker,
@@ -167,7 +185,11 @@ func (s *TaskService) GetAvailableMembersForTask(r *http.Request, projectID, tas
 }
 
 // Dodaj članove zadatku
-func (s *TaskService) AddMembersToTask(taskID string, membersToAdd []models.Member) error {
+func (s *TaskService) AddMembersToTask(taskID string, membersToAdd []models.Member, actor string) error {
+	return s.addMembersToTask(context.Background(), taskID, membersToAdd, actor)
+}
+
+func (s *TaskService) addMembersToTask(ctx context.Context, taskID string, membersToAdd []models.Member, actor string) error {
 	// Konvertovanje taskID u ObjectID
 	taskObjectID, err := primitive.ObjectIDFromHex(taskID)
 	if err != nil {
@@ -176,16 +198,19 @@ func (s *TaskService) AddMembersToTask(taskID string, membersToAdd []models.Memb
 
 	// Dohvati zadatak iz baze
 	var task models.Task
-	err = s.tasksCollection.FindOne(context.Background(), bson.M{"_id": taskObjectID}).Decode(&task)
+	err = s.tasksCollection.FindOne(ctx, bson.M{"_id": taskObjectID}).Decode(&task)
 	if err != nil {
 		return fmt.Errorf("task not found: %v", err)
 	}
+	if err := ensureTaskActive(&task); err != nil {
+		return err
+	}
 
 	// Proveri i inicijalizuj polje `members` ako je `nil`
 	if task.Members == nil {
 		task.Members = []models.Member{}
 		_, err := s.tasksCollection.UpdateOne(
-			context.Background(),
+			ctx,
 			bson.M{"_id": taskObjectID},
 			bson.M{"$set": bson.M{"members": task.Members}}, // Postavi `members` kao prazan niz
 		)
@@ -199,6 +224,8 @@ func (s *TaskService) AddMembersToTask(taskID string, membersToAdd []models.Memb
 	// Filtriraj nove članove koji nisu već dodeljeni
 	newMembers := []models.Member{}
 	for _, member := range membersToAdd {
+		// Odgovorni član se postavlja samo kroz SetResponsibleMember
+		member.Assignment = models.AssignmentContributor
 		alreadyAssigned := false
 		for _, assigned := range task.Members {
 			if assigned.ID == member.ID {
@@ -214,7 +241,17 @@ func (s *TaskService) AddMembersToTask(taskID string, membersToAdd []models.Memb
 	if len(newMembers) > 0 {
 		// Ažuriraj zadatak sa novim članovima
 		update := bson.M{"$addToSet": bson.M{"members": bson.M{"$each": newMembers}}}
-		_, err = s.tasksCollection.UpdateOne(context.Background(), bson.M{"_id": taskObjectID}, update)
+		err = s.inTransaction(ctx, func(ctx context.Context) error {
+			if _, err := s.tasksCollection.UpdateOne(ctx, bson.M{"_id": taskObjectID}, update); err != nil {
+				return err
+			}
+			for _, member := range newMembers {
+				if err := s.recordMemberHistory(ctx, &task, models.HistoryAddMember, member, actor); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
 		if err != nil {
 			logging.Logger.Errorf("Event ID: ADD_MEMBERS_TO_TASK_ERROR, Description: Failed to add members to task %s: %v", taskID, err)
 			return fmt.Errorf("failed to add members to task: %v", err)
@@ -224,14 +261,10 @@ func (s *TaskService) AddMembersToTask(taskID string, membersToAdd []models.Memb
 		// Slanje notifikacija za nove članove
 		for _, member := range newMembers {
 			message := fmt.Sprintf("You have been added to the task: %s!", task.Title)
-			go func(member models.Member, message string) {
-				_, err := s.NotificationsBreaker.Execute(func() (interface{}, error) {
-					return nil, s.sendNotification(member, message)
-				})
-				if err != nil {
-					logging.Logger.Errorf("Event ID: NOTIFICATION_SEND_FAILED, Description: Failed to send notification to member %s for task %s: %v", member.Username, taskID, err)
-				}
-			}(member, message)
+			s.notify(ctx, member, message)
+		}
+		for _, member := range newMembers {
+			s.notifyWatchers(ctx, &task, fmt.Sprintf("%s has been added to the task: %s", member.Username, task.Title), newMembers...)
 		}
 	} else {
 		logging.Logger.Infof("Event ID: NO_NEW_MEMBERS, Description: No new members to add to task %s. All provided members are already assigned.", taskID)
@@ -300,7 +333,7 @@ func (s *TaskService) GetMembersForTask(taskID primitive.ObjectID) ([]models.Mem
 	return task.Members, nil
 }
 
-func (s *TaskService) CreateTask(projectID string, title, description string, status models.TaskStatus) (*models.Task, error) {
+func (s *TaskService) CreateTask(projectID string, title, description string, status models.TaskStatus, actor string) (*models.Task, error) {
 	logging.Logger.Info(" Starting CreateTask...")
 
 	_, err := primitive.ObjectIDFromHex(projectID)
@@ -317,23 +350,49 @@ func (s *TaskService) CreateTask(projectID string, title, description string, st
 	sanitizedTitle := html.EscapeString(title)
 	sanitizedDescription := html.EscapeString(description)
 
+	rank, err := s.nextRankInColumn(context.Background(), projectID, status)
+	if err != nil {
+		logging.Logger.Errorf(" Failed to compute rank for new task: %v", err)
+		return nil, fmt.Errorf("failed to create task: %v", err)
+	}
+
 	task := &models.Task{
 		ID:          primitive.NewObjectID(),
 		ProjectID:   projectID,
 		Title:       sanitizedTitle,
 		Description: sanitizedDescription,
 		Status:      status,
+		Rank:        rank,
 	}
 
 	logging.Logger.Info(" Inserting task into MongoDB...")
-	result, err := s.tasksCollection.InsertOne(context.Background(), task)
+	err = s.inTransaction(context.Background(), func(ctx context.Context) error {
+		if _, err := s.tasksCollection.InsertOne(ctx, task); err != nil {
+			return err
+		}
+		return s.recordHistory(ctx, models.TaskHistoryEntry{
+			TaskID:       task.ID,
+			ProjectID:    task.ProjectID,
+			ActivityType: models.HistoryCreateTask,
+			Actor:        actor,
+			Field:        "status",
+			NewValue:     string(task.Status),
+			Details:      fmt.Sprintf("Task '%s' created", task.Title),
+		})
+	})
 	if err != nil {
 		logging.Logger.Errorf(" Failed to insert task: %v", err)
 		return nil, fmt.Errorf("failed to create task: %v", err)
 	}
-	task.ID = result.InsertedID.(primitive.ObjectID)
 	logging.Logger.Infof("Task inserted with ID: %s", task.ID.Hex())
 
+	s.publishWebhookEvent(projectID, webhookTaskCreated, map[string]interface{}{
+		"taskId": task.ID.Hex(),
+		"title":  html.UnescapeString(task.Title),
+		"status": task.Status,
+		"actor":  actor,
+	})
+
 	// Notify projects-service
 	if url := os.Getenv("PROJECTS_SERVICE_URL"); url != "" {
 		projectURL := fmt.Sprintf("%s/api/projects/%s/add-task", url, projectID)
@@ -413,7 +472,7 @@ func (s *TaskService) CreateTask(projectID string, title, description string, st
 
 func (s *TaskService) GetAllTasks() ([]*models.Task, error) {
 	var tasks []*models.Task
-	cursor, err := s.tasksCollection.Find(context.Background(), bson.M{})
+	cursor, err := s.tasksCollection.Find(context.Background(), activeTaskFilter(bson.M{}))
 	if err != nil {
 		logging.Logger.Errorf("Event ID: TASK_RETRIEVAL_FAILED, Description: Failed to retrieve tasks: %v", err)
 		return nil, fmt.Errorf("failed to retrieve tasks: %v", err)
@@ -439,7 +498,11 @@ func (s *TaskService) GetAllTasks() ([]*models.Task, error) {
 	return tasks, nil
 }
 
-func (s *TaskService) RemoveMemberFromTask(taskID string, memberID primitive.ObjectID) error {
+func (s *TaskService) RemoveMemberFromTask(taskID string, memberID primitive.ObjectID, actor string) error {
+	return s.removeMemberFromTask(context.Background(), taskID, memberID, actor)
+}
+
+func (s *TaskService) removeMemberFromTask(ctx context.Context, taskID string, memberID primitive.ObjectID, actor string) error {
 	// Konvertovanje taskID u ObjectID
 	taskObjectID, err := primitive.ObjectIDFromHex(taskID)
 	if err != nil {
@@ -449,11 +512,14 @@ func (s *TaskService) RemoveMemberFromTask(taskID string, memberID primitive.Obj
 
 	// Dohvatanje zadatka iz baze
 	var task models.Task
-	err = s.tasksCollection.FindOne(context.Background(), bson.M{"_id": taskObjectID}).Decode(&task)
+	err = s.tasksCollection.FindOne(ctx, bson.M{"_id": taskObjectID}).Decode(&task)
 	if err != nil {
 		logging.Logger.Warnf("Event ID: TASK_NOT_FOUND, Description: Task not found with ID %s: %v", taskID, err)
 		return fmt.Errorf("task not found: %v", err)
 	}
+	if err := ensureTaskActive(&task); err != nil {
+		return err
+	}
 
 	// ❗️Provera da li je task završen
 	if task.Status == "Completed" {
@@ -479,11 +545,17 @@ func (s *TaskService) RemoveMemberFromTask(taskID string, memberID primitive.Obj
 	}
 
 	// Ažuriranje zadatka u bazi
-	_, err = s.tasksCollection.UpdateOne(
-		context.Background(),
-		bson.M{"_id": taskObjectID},
-		bson.M{"$set": bson.M{"members": task.Members}},
-	)
+	err = s.inTransaction(ctx, func(ctx context.Context) error {
+		_, err := s.tasksCollection.UpdateOne(
+			ctx,
+			bson.M{"_id": taskObjectID},
+			bson.M{"$set": bson.M{"members": task.Members}},
+		)
+		if err != nil {
+			return err
+		}
+		return s.recordMemberHistory(ctx, &task, models.HistoryRemoveMember, removedMember, actor)
+	})
 	if err != nil {
 		logging.Logger.Errorf("Event ID: TASK_UPDATE_FAILED, Description: Failed to update task %s after member removal: %v", taskID, err)
 		return fmt.Errorf("failed to update task: %v", err)
@@ -492,14 +564,8 @@ func (s *TaskService) RemoveMemberFromTask(taskID string, memberID primitive.Obj
 
 	// Asinhrono slanje notifikacije preko Circuit Breaker-a
 	message := fmt.Sprintf("You have been removed from the task: %s", task.Title)
-	go func(member models.Member, message string) {
-		_, err := s.NotificationsBreaker.Execute(func() (interface{}, error) {
-			return nil, s.sendNotification(member, message)
-		})
-		if err != nil {
-			logging.Logger.Errorf("Event ID: NOTIFICATION_SEND_FAILED, Description: Failed to send removal notification to member %s: %v", member.Username, err)
-		}
-	}(removedMember, message)
+	s.notify(ctx, removedMember, message)
+	s.notifyWatchers(ctx, &task, fmt.Sprintf("%s has been removed from the task: %s", removedMember.Username, task.Title), removedMember)
 
 	return nil
 }
@@ -515,17 +581,34 @@ func (s *TaskService) GetTaskByID(taskID primitive.ObjectID) (*models.Task, erro
 	return &task, nil
 }
 
-func (s *TaskService) ChangeTaskStatus(taskID primitive.ObjectID, status models.TaskStatus, username string) (*models.Task, error) {
+// ChangeTaskStatus menja status zadatka. role je uloga pozivaoca na projektu zadatka:
+// maintainer i owner mogu da promene status bilo kog zadatka i jedini mogu da zaobiđu WIP
+// limit kolone (overrideWIP); ostali menjaju samo zadatke na kojima su članovi.
+func (s *TaskService) ChangeTaskStatus(taskID primitive.ObjectID, status models.TaskStatus, username string, role projectroles.Role, overrideWIP bool) (*models.Task, error) {
+	return s.changeTaskStatus(context.Background(), taskID, status, username, role, overrideWIP)
+}
+
+func (s *TaskService) changeTaskStatus(ctx context.Context, taskID primitive.ObjectID, status models.TaskStatus, username string, role projectroles.Role, overrideWIP bool) (*models.Task, error) {
 	var task models.Task
-	if err := s.tasksCollection.FindOne(context.Background(), bson.M{"_id": taskID}).Decode(&task); err != nil {
+	if err := s.tasksCollection.FindOne(ctx, bson.M{"_id": taskID}).Decode(&task); err != nil {
 		return nil, fmt.Errorf("task not found: %v", err)
 	}
+	if err := ensureTaskActive(&task); err != nil {
+		return nil, err
+	}
 
 	logging.Logger.Infof("Task '%s' current status: %s", task.Title, task.Status)
 	logging.Logger.Infof("Attempting to change status to: %s", status)
 
-	isAuthorized := false
-	for _, member := range append(task.Members, task.Assignees...) {
+	if !models.IsValidTaskStatus(status) {
+		return nil, fmt.Errorf("invalid task status: %s", status)
+	}
+	if overrideWIP && !role.AtLeast(projectroles.Maintainer) {
+		return nil, fmt.Errorf("only project maintainers can override WIP limits")
+	}
+
+	isAuthorized := role.AtLeast(projectroles.Maintainer)
+	for _, member := range task.Members {
 		if member.Username == username {
 			isAuthorized = true
 			break
@@ -550,10 +633,17 @@ func (s *TaskService) ChangeTaskStatus(taskID primitive.ObjectID, status models.
 			}
 
 			var depTask models.Task
-			err = s.tasksCollection.FindOne(context.Background(), bson.M{"_id": depID}).Decode(&depTask)
+			err = s.tasksCollection.FindOne(ctx, bson.M{"_id": depID}).Decode(&depTask)
+			if err == mongo.ErrNoDocuments {
+				// Zavisnost je trajno obrisana; čvor je ostao samo u istoriji workflow-a
+				continue
+			}
 			if err != nil {
 				return nil, fmt.Errorf("dependent task not found: %v", err)
 			}
+			if !depTask.IsActive() {
+				continue
+			}
 
 			if depTask.Status != models.StatusInProgress && depTask.Status != models.StatusCompleted {
 				return nil, fmt.Errorf("cannot change status: dependent task '%s' is neither in progress nor completed", depTask.Title)
@@ -563,18 +653,57 @@ func (s *TaskService) ChangeTaskStatus(taskID primitive.ObjectID, status models.
 		dependencyIDs, _ = s.getDependenciesFromWorkflow(task.ID.Hex())
 	}
 
+	previousStatus := task.Status
 	update := bson.M{"$set": bson.M{"status": status}}
-	_, err = s.tasksCollection.UpdateOne(context.Background(), bson.M{"_id": taskID}, update)
-	if err != nil {
-		return nil, fmt.Errorf("failed to update task status: %v", err)
-	}
+	if status != task.Status {
+		if err := s.checkWIPLimit(ctx, task.ProjectID, status, overrideWIP); err != nil {
+			return nil, err
+		}
 
-	if err := s.tasksCollection.FindOne(context.Background(), bson.M{"_id": taskID}).Decode(&task); err != nil {
-		return nil, fmt.Errorf("failed to fetch updated task: %v", err)
+		// Zadatak koji ulazi u novu kolonu ide na njen kraj
+		rank, err := s.nextRankInColumn(ctx, task.ProjectID, status)
+		if err != nil {
+			return nil, err
+		}
+		update = bson.M{"$set": bson.M{"status": status, "rank": rank}}
+	}
+	err = s.inTransaction(ctx, func(ctx context.Context) error {
+		if _, err := s.tasksCollection.UpdateOne(ctx, bson.M{"_id": taskID}, update); err != nil {
+			return fmt.Errorf("failed to update task status: %v", err)
+		}
+		if err := s.tasksCollection.FindOne(ctx, bson.M{"_id": taskID}).Decode(&task); err != nil {
+			return fmt.Errorf("failed to fetch updated task: %v", err)
+		}
+		if previousStatus == task.Status {
+			return nil
+		}
+		return s.recordHistory(ctx, models.TaskHistoryEntry{
+			TaskID:       task.ID,
+			ProjectID:    task.ProjectID,
+			ActivityType: models.HistoryChangeTaskStatus,
+			Actor:        username,
+			Field:        "status",
+			OldValue:     string(previousStatus),
+			NewValue:     string(task.Status),
+			Details:      fmt.Sprintf("Status of task '%s' changed from %s to %s", task.Title, previousStatus, task.Status),
+		})
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	logging.Logger.Infof("✅ Successfully updated task '%s' to status: %s", task.Title, task.Status)
 
+	if previousStatus != task.Status {
+		s.publishWebhookEvent(task.ProjectID, webhookTaskStatusChanged, map[string]interface{}{
+			"taskId":         task.ID.Hex(),
+			"title":          html.UnescapeString(task.Title),
+			"previousStatus": previousStatus,
+			"status":         task.Status,
+			"actor":          username,
+		})
+	}
+
 	isBlocked := false
 	if len(dependencyIDs) > 0 {
 		isBlocked = true
@@ -587,30 +716,63 @@ func (s *TaskService) ChangeTaskStatus(taskID primitive.ObjectID, status models.
 		logging.Logger.Warnf("⚠️ Failed to refresh blocked status in workflow-service: %v", err)
 	}
 
-	message := fmt.Sprintf("The status of task '%s' has been changed to: %s", task.Title, status)
-	for _, member := range append(task.Members, task.Assignees...) {
-		err := s.sendNotification(member, message)
-		if err != nil {
-			logging.Logger.Warnf("⚠️ Failed to notify user %s: %v", member.Username, err)
+	if previousStatus != models.StatusCompleted && task.Status == models.StatusCompleted && task.Recurrence != nil {
+		if _, err := s.spawnNextOccurrence(ctx, &task); err != nil {
+			logging.Logger.Errorf("Event ID: RECURRENCE_SPAWN_FAILED, Description: Failed to create next occurrence of task %s: %v", task.ID.Hex(), err)
 		}
 	}
 
+	message := fmt.Sprintf("The status of task '%s' has been changed to: %s", task.Title, status)
+	for _, member := range taskRecipients(&task) {
+		s.notify(ctx, member, message)
+	}
+
 	return &task, nil
 }
 
+// ProjectDeletionActor označava zadatke obrisane zajedno sa projektom, kako bi
+// RestoreTasksByProject vratio samo njih, a ne i ranije pojedinačno obrisane.
+const ProjectDeletionActor = "project-deletion"
+
+// DeleteTasksByProject označava sve zadatke projekta kao obrisane. Trajno ih uklanja
+// StartTaskPurger nakon perioda čuvanja, a čvorovi u workflow-service-u ostaju arhivirani.
 func (s *TaskService) DeleteTasksByProject(projectID string) error {
-	filter := bson.M{"projectId": projectID}
+	filter := bson.M{"projectId": projectID, "deletedAt": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"deletedAt": time.Now().UTC(), "removedBy": ProjectDeletionActor}}
 
-	result, err := s.tasksCollection.DeleteMany(context.Background(), filter)
+	result, err := s.tasksCollection.UpdateMany(context.Background(), filter, update)
 	if err != nil {
 		logging.Logger.Errorf("Event ID: TASKS_DELETE_FAILED, Description: Failed to delete tasks for project ID %s: %v", projectID, err)
 		return fmt.Errorf("failed to delete tasks: %v", err)
 	}
 
-	logging.Logger.Infof("Event ID: TASKS_DELETED_BY_PROJECT, Description: Successfully deleted %d tasks for project ID %s", result.DeletedCount, projectID)
+	if err := s.setProjectArchivedInWorkflow(projectID, true); err != nil {
+		logging.Logger.Warnf("Event ID: WORKFLOW_ARCHIVE_FAILED, Description: Failed to mark task nodes of project %s as archived: %v", projectID, err)
+	}
+
+	logging.Logger.Infof("Event ID: TASKS_DELETED_BY_PROJECT, Description: Successfully deleted %d tasks for project ID %s", result.ModifiedCount, projectID)
 	return nil
 }
 
+// RestoreTasksByProject poništava DeleteTasksByProject kada brisanje projekta ne uspe.
+func (s *TaskService) RestoreTasksByProject(ctx context.Context, projectID string) (int64, error) {
+	filter := bson.M{"projectId": projectID, "removedBy": ProjectDeletionActor, "deletedAt": bson.M{"$exists": true}}
+	update := bson.M{"$unset": bson.M{"deletedAt": "", "removedBy": ""}}
+
+	result, err := s.tasksCollection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		logging.Logger.Errorf("Event ID: TASKS_RESTORE_FAILED, Description: Failed to restore tasks for project ID %s: %v", projectID, err)
+		return 0, fmt.Errorf("failed to restore tasks: %v", err)
+	}
+
+	if err := s.setProjectArchivedInWorkflow(projectID, false); err != nil {
+		logging.Logger.Warnf("Event ID: WORKFLOW_ARCHIVE_FAILED, Description: Failed to mark task nodes of project %s as active: %v", projectID, err)
+	}
+
+	logging.Logger.Infof("Event ID: TASKS_RESTORED_BY_PROJECT, Description: Restored %d tasks for project ID %s", result.ModifiedCount, projectID)
+	return result.ModifiedCount, nil
+}
+
 func (s *TaskService) HasActiveTasks(ctx context.Context, projectID, memberID string) (bool, error) {
 	memberObjectID, err := primitive.ObjectIDFromHex(memberID)
 	if err != nil {
@@ -618,11 +780,11 @@ func (s *TaskService) HasActiveTasks(ctx context.Context, projectID, memberID st
 		return false, err
 	}
 
-	filter := bson.M{
+	filter := activeTaskFilter(bson.M{
 		"projectId":   projectID,
 		"members._id": memberObjectID,
 		"status":      "In progress",
-	}
+	})
 
 	count, err := s.tasksCollection.CountDocuments(ctx, filter)
 	if err != nil {
@@ -636,8 +798,9 @@ func (s *TaskService) HasActiveTasks(ctx context.Context, projectID, memberID st
 }
 
 func (s *TaskService) GetTasksByProjectID(projectID string) ([]models.Task, error) {
-	filter := bson.M{"projectId": projectID}
-	cursor, err := s.tasksCollection.Find(context.Background(), filter)
+	filter := activeTaskFilter(bson.M{"projectId": projectID})
+	opts := options.Find().SetSort(bson.D{{Key: "rank", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := s.tasksCollection.Find(context.Background(), filter, opts)
 	if err != nil {
 		logging.Logger.Errorf("Event ID: TASKS_BY_PROJECT_FETCH_FAILED, Description: Failed to find tasks for project %s: %v", projectID, err)
 		return nil, fmt.Errorf("failed to find tasks: %w", err)
@@ -680,25 +843,17 @@ func (s *TaskService) RemoveUserFromAllTasksByUsername(username string) error {
 		return fmt.Errorf("failed to remove user from members: %v", err)
 	}
 
-	// Uklanjanje iz assignees
-	filterAssignees := bson.M{
-		"$and": []bson.M{
-			{"assignees": bson.M{"$type": "array"}},
-			{"assignees": username},
-		},
-	}
-	updateAssignees := bson.M{
-		"$pull": bson.M{
-			"assignees": username,
-		},
-	}
-	resultAssignees, err := s.tasksCollection.UpdateMany(context.Background(), filterAssignees, updateAssignees)
+	// Uklanjanje iz watchers
+	resultWatchers, err := s.tasksCollection.UpdateMany(context.Background(),
+		bson.M{"watchers.username": username},
+		bson.M{"$pull": bson.M{"watchers": bson.M{"username": username}}},
+	)
 	if err != nil {
-		logging.Logger.Errorf("Event ID: REMOVE_USER_FROM_ASSIGNEES_FAILED, Description: Failed to remove user '%s' from assignees: %v", username, err)
-		return fmt.Errorf("failed to remove user from assignees: %v", err)
+		logging.Logger.Errorf("Event ID: REMOVE_USER_FROM_WATCHERS_FAILED, Description: Failed to remove user '%s' from watchers: %v", username, err)
+		return fmt.Errorf("failed to remove user from watchers: %v", err)
 	}
 
-	totalModified := resultMembers.ModifiedCount + resultAssignees.ModifiedCount
+	totalModified := resultMembers.ModifiedCount + resultWatchers.ModifiedCount
 	logging.Logger.Infof("Event ID: REMOVE_USER_FROM_ALL_TASKS_SUCCESS, Description: Successfully removed user '%s' from %d tasks.", username, totalModified)
 
 	return nil
@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"trello-project/microservices/tasks-service/logging"
+	"trello-project/microservices/tasks-service/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SprintSummary je stanje zadataka u sprintu; projects-service ga čuva pri zatvaranju
+// sprinta i iz njega računa brzinu tima.
+type SprintSummary struct {
+	SprintID        string `json:"sprintId"`
+	TotalTasks      int    `json:"totalTasks"`
+	CompletedTasks  int    `json:"completedTasks"`
+	TotalPoints     int    `json:"totalPoints"`
+	CompletedPoints int    `json:"completedPoints"`
+	RolledOver      int    `json:"rolledOver"`
+}
+
+// SetTaskSprint dodeljuje zadatak sprintu i/ili menja broj poena. Nil vrednost ostavlja
+// polje nepromenjeno, a prazan sprintID vraća zadatak u backlog.
+func (s *TaskService) SetTaskSprint(ctx context.Context, taskID primitive.ObjectID, sprintID *string, storyPoints *int, actor string) (*models.Task, error) {
+	var task models.Task
+	if err := s.tasksCollection.FindOne(ctx, bson.M{"_id": taskID}).Decode(&task); err != nil {
+		return nil, fmt.Errorf("task not found")
+	}
+	if err := ensureTaskActive(&task); err != nil {
+		return nil, err
+	}
+
+	set := bson.M{}
+	unset := bson.M{}
+	var changes []models.TaskHistoryEntry
+	if sprintID != nil && *sprintID != task.SprintID {
+		if *sprintID == "" {
+			unset["sprintId"] = ""
+		} else {
+			status, err := s.fetchSprintStatus(task.ProjectID, *sprintID)
+			if err != nil {
+				return nil, err
+			}
+			if status == "closed" {
+				return nil, fmt.Errorf("cannot assign task to a closed sprint")
+			}
+			set["sprintId"] = *sprintID
+		}
+		changes = append(changes, models.TaskHistoryEntry{Field: "sprintId", OldValue: task.SprintID, NewValue: *sprintID})
+	}
+	if storyPoints != nil && *storyPoints != task.StoryPoints {
+		if *storyPoints < 0 {
+			return nil, fmt.Errorf("story points must not be negative")
+		}
+		set["storyPoints"] = *storyPoints
+		changes = append(changes, models.TaskHistoryEntry{Field: "storyPoints", OldValue: strconv.Itoa(task.StoryPoints), NewValue: strconv.Itoa(*storyPoints)})
+	}
+	if len(changes) == 0 {
+		return &task, nil
+	}
+
+	update := bson.M{}
+	if len(set) > 0 {
+		update["$set"] = set
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+	if _, err := s.tasksCollection.UpdateOne(ctx, bson.M{"_id": taskID}, update); err != nil {
+		logging.Logger.Errorf("Event ID: TASK_SPRINT_UPDATE_FAILED, Description: Failed to update sprint of task %s: %v", taskID.Hex(), err)
+		return nil, fmt.Errorf("failed to update task sprint: %v", err)
+	}
+
+	for _, change := range changes {
+		change.TaskID = task.ID
+		change.ProjectID = task.ProjectID
+		change.ActivityType = models.HistoryUpdateTask
+		change.Actor = actor
+		change.Details = fmt.Sprintf("Field '%s' of task '%s' changed", change.Field, task.Title)
+		s.recordHistory(ctx, change)
+	}
+
+	logging.Logger.Infof("Event ID: TASK_SPRINT_UPDATED, Description: Sprint planning of task %s updated by %s.", taskID.Hex(), actor)
+	return s.GetTaskByID(taskID)
+}
+
+// GetSprintTasks vraća aktivne zadatke sprinta u redosledu table.
+func (s *TaskService) GetSprintTasks(ctx context.Context, sprintID string) ([]models.Task, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "rank", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := s.tasksCollection.Find(ctx, activeTaskFilter(bson.M{"sprintId": sprintID}), opts)
+	if err != nil {
+		logging.Logger.Errorf("Event ID: SPRINT_TASKS_FETCH_FAILED, Description: Failed to find tasks of sprint %s: %v", sprintID, err)
+		return nil, fmt.Errorf("failed to find sprint tasks: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	tasks := []models.Task{}
+	if err := cursor.All(ctx, &tasks); err != nil {
+		return nil, fmt.Errorf("failed to decode sprint tasks: %v", err)
+	}
+	return tasks, nil
+}
+
+// GetSprintSummary broji zadatke i poene sprinta, ukupno i završene.
+func (s *TaskService) GetSprintSummary(ctx context.Context, sprintID string) (*SprintSummary, error) {
+	tasks, err := s.GetSprintTasks(ctx, sprintID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &SprintSummary{SprintID: sprintID, TotalTasks: len(tasks)}
+	for _, task := range tasks {
+		summary.TotalPoints += task.StoryPoints
+		if task.Status == models.StatusCompleted {
+			summary.CompletedTasks++
+			summary.CompletedPoints += task.StoryPoints
+		}
+	}
+	return summary, nil
+}
+
+// RolloverSprint prebacuje nezavršene zadatke sprinta u targetSprintID (prazan string
+// znači backlog). Vraća stanje sprinta pre prebacivanja.
+func (s *TaskService) RolloverSprint(ctx context.Context, sprintID, targetSprintID string) (*SprintSummary, error) {
+	summary, err := s.GetSprintSummary(ctx, sprintID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := activeTaskFilter(bson.M{"sprintId": sprintID, "status": bson.M{"$ne": models.StatusCompleted}})
+	update := bson.M{"$unset": bson.M{"sprintId": ""}}
+	if targetSprintID != "" {
+		update = bson.M{"$set": bson.M{"sprintId": targetSprintID}}
+	}
+	result, err := s.tasksCollection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		logging.Logger.Errorf("Event ID: SPRINT_ROLLOVER_FAILED, Description: Failed to roll over tasks of sprint %s: %v", sprintID, err)
+		return nil, fmt.Errorf("failed to roll over sprint tasks: %v", err)
+	}
+	summary.RolledOver = int(result.ModifiedCount)
+
+	logging.Logger.Infof("Event ID: SPRINT_ROLLED_OVER, Description: %d unfinished tasks of sprint %s moved to '%s'.", summary.RolledOver, sprintID, targetSprintID)
+	return summary, nil
+}
+
+// fetchSprintStatus proverava u projects-service-u da sprint pripada projektu i vraća njegov status.
+func (s *TaskService) fetchSprintStatus(projectID, sprintID string) (string, error) {
+	projectsURL := os.Getenv("PROJECTS_SERVICE_URL")
+	if projectsURL == "" {
+		return "", fmt.Errorf("PROJECTS_SERVICE_URL not set")
+	}
+
+	url := fmt.Sprintf("%s/api/projects/%s/sprints/%s", strings.TrimRight(projectsURL, "/"), projectID, sprintID)
+	result, err := s.ProjectsBreaker.Execute(func() (interface{}, error) {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Role", "manager")
+		resp, err := s.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusBadRequest {
+			return "", nil
+		}
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			return nil, fmt.Errorf("projects-service error: %s", string(body))
+		}
+
+		var sprint struct {
+			Status string `json:"status"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&sprint); err != nil {
+			return nil, fmt.Errorf("failed to decode sprint: %v", err)
+		}
+		return sprint.Status, nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to verify sprint: %v", err)
+	}
+	if result.(string) == "" {
+		return "", fmt.Errorf("sprint not found in project")
+	}
+	return result.(string), nil
+}
+
+// SprintProjectID vraća projekat sprinta na osnovu njegovih zadataka; prazan string ako
+// sprint nema zadataka.
+func (s *TaskService) SprintProjectID(ctx context.Context, sprintID string) (string, error) {
+	var task models.Task
+	err := s.tasksCollection.FindOne(ctx, bson.M{"sprintId": sprintID}, options.FindOne().SetProjection(bson.M{"projectId": 1})).Decode(&task)
+	if err == mongo.ErrNoDocuments {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve sprint project: %v", err)
+	}
+	return task.ProjectID, nil
+}
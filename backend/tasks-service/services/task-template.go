@@ -0,0 +1,230 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"trello-project/microservices/tasks-service/logging"
+	"trello-project/microservices/tasks-service/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// sanitizeTemplate proverava šablon i escape-uje tekstualna polja, isto kao kod zadataka.
+func sanitizeTemplate(template *models.TaskTemplate) error {
+	template.Name = strings.TrimSpace(template.Name)
+	if template.Name == "" {
+		return fmt.Errorf("template name must not be empty")
+	}
+	if strings.TrimSpace(template.Title) == "" {
+		return fmt.Errorf("template title must not be empty")
+	}
+	if template.ProjectID != "" {
+		if _, err := primitive.ObjectIDFromHex(template.ProjectID); err != nil {
+			return fmt.Errorf("invalid project ID format")
+		}
+	}
+	if template.Status != "" && !models.IsValidTaskStatus(template.Status) {
+		return fmt.Errorf("invalid task status: %s", template.Status)
+	}
+	if template.DueInDays < 0 {
+		return fmt.Errorf("dueInDays must not be negative")
+	}
+
+	template.Name = html.EscapeString(template.Name)
+	template.Title = html.EscapeString(template.Title)
+	template.Description = html.EscapeString(template.Description)
+	template.Labels = normalizeLabels(template.Labels)
+	template.Checklist = normalizeLabels(template.Checklist)
+	return nil
+}
+
+// CreateTemplate čuva novi šablon. Ime šablona je jedinstveno u okviru projekta,
+// odnosno među globalnim šablonima.
+func (s *TaskService) CreateTemplate(ctx context.Context, template models.TaskTemplate, actor string) (*models.TaskTemplate, error) {
+	if err := sanitizeTemplate(&template); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	template.ID = primitive.NewObjectID()
+	template.CreatedBy = actor
+	template.CreatedAt = now
+	template.UpdatedAt = now
+
+	if _, err := s.templatesCollection.InsertOne(ctx, template); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, fmt.Errorf("template with this name already exists")
+		}
+		logging.Logger.Errorf("Event ID: TASK_TEMPLATE_CREATE_FAILED, Description: Failed to create template '%s': %v", template.Name, err)
+		return nil, fmt.Errorf("failed to create template: %v", err)
+	}
+
+	logging.Logger.Infof("Event ID: TASK_TEMPLATE_CREATED, Description: Template %s ('%s') created by %s.", template.ID.Hex(), template.Name, actor)
+	return &template, nil
+}
+
+// ListTemplates vraća šablone projekta zajedno sa globalnim šablonima. Bez projectID
+// vraća samo globalne šablone.
+func (s *TaskService) ListTemplates(ctx context.Context, projectID string) ([]models.TaskTemplate, error) {
+	filter := bson.M{"projectId": bson.M{"$exists": false}}
+	if projectID != "" {
+		filter = bson.M{"$or": []bson.M{
+			{"projectId": projectID},
+			{"projectId": bson.M{"$exists": false}},
+		}}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := s.templatesCollection.Find(ctx, filter, opts)
+	if err != nil {
+		logging.Logger.Errorf("Event ID: TASK_TEMPLATES_FETCH_FAILED, Description: Failed to fetch templates for project %s: %v", projectID, err)
+		return nil, fmt.Errorf("failed to fetch templates: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	templates := []models.TaskTemplate{}
+	if err := cursor.All(ctx, &templates); err != nil {
+		return nil, fmt.Errorf("failed to decode templates: %v", err)
+	}
+	return templates, nil
+}
+
+func (s *TaskService) GetTemplate(ctx context.Context, templateID primitive.ObjectID) (*models.TaskTemplate, error) {
+	var template models.TaskTemplate
+	if err := s.templatesCollection.FindOne(ctx, bson.M{"_id": templateID}).Decode(&template); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("template not found")
+		}
+		return nil, fmt.Errorf("failed to fetch template: %v", err)
+	}
+	return &template, nil
+}
+
+// UpdateTemplate zamenjuje sadržaj šablona. Projekat kome šablon pripada se ne menja.
+func (s *TaskService) UpdateTemplate(ctx context.Context, templateID primitive.ObjectID, update models.TaskTemplate) (*models.TaskTemplate, error) {
+	existing, err := s.GetTemplate(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	update.ProjectID = existing.ProjectID
+	if err := sanitizeTemplate(&update); err != nil {
+		return nil, err
+	}
+
+	set := bson.M{
+		"name":        update.Name,
+		"title":       update.Title,
+		"description": update.Description,
+		"status":      update.Status,
+		"labels":      update.Labels,
+		"checklist":   update.Checklist,
+		"dueInDays":   update.DueInDays,
+		"updatedAt":   time.Now().UTC(),
+	}
+	if _, err := s.templatesCollection.UpdateOne(ctx, bson.M{"_id": templateID}, bson.M{"$set": set}); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, fmt.Errorf("template with this name already exists")
+		}
+		logging.Logger.Errorf("Event ID: TASK_TEMPLATE_UPDATE_FAILED, Description: Failed to update template %s: %v", templateID.Hex(), err)
+		return nil, fmt.Errorf("failed to update template: %v", err)
+	}
+
+	logging.Logger.Infof("Event ID: TASK_TEMPLATE_UPDATED, Description: Template %s updated.", templateID.Hex())
+	return s.GetTemplate(ctx, templateID)
+}
+
+func (s *TaskService) DeleteTemplate(ctx context.Context, templateID primitive.ObjectID) error {
+	result, err := s.templatesCollection.DeleteOne(ctx, bson.M{"_id": templateID})
+	if err != nil {
+		logging.Logger.Errorf("Event ID: TASK_TEMPLATE_DELETE_FAILED, Description: Failed to delete template %s: %v", templateID.Hex(), err)
+		return fmt.Errorf("failed to delete template: %v", err)
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("template not found")
+	}
+
+	logging.Logger.Infof("Event ID: TASK_TEMPLATE_DELETED, Description: Template %s deleted.", templateID.Hex())
+	return nil
+}
+
+// CreateTaskFromTemplate kreira zadatak u projektu na osnovu šablona; polja iz
+// overrides imaju prednost nad vrednostima iz šablona.
+func (s *TaskService) CreateTaskFromTemplate(ctx context.Context, templateID primitive.ObjectID, projectID string, overrides models.TemplateOverrides, actor string) (*models.Task, error) {
+	template, err := s.GetTemplate(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if template.ProjectID != "" && template.ProjectID != projectID {
+		return nil, fmt.Errorf("template belongs to another project")
+	}
+
+	title := html.UnescapeString(template.Title)
+	if overrides.Title != nil {
+		title = *overrides.Title
+	}
+	if strings.TrimSpace(title) == "" {
+		return nil, fmt.Errorf("task title must not be empty")
+	}
+	description := html.UnescapeString(template.Description)
+	if overrides.Description != nil {
+		description = *overrides.Description
+	}
+	status := template.Status
+	if overrides.Status != "" {
+		status = overrides.Status
+	}
+	if status != "" && !models.IsValidTaskStatus(status) {
+		return nil, fmt.Errorf("invalid task status: %s", status)
+	}
+
+	task, err := s.CreateTask(projectID, title, description, status, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := template.Labels
+	if overrides.Labels != nil {
+		labels = normalizeLabels(overrides.Labels)
+	}
+	checklist := template.Checklist
+	if overrides.Checklist != nil {
+		checklist = normalizeLabels(overrides.Checklist)
+	}
+	dueDate := overrides.DueDate
+	if dueDate == nil && template.DueInDays > 0 {
+		due := time.Now().UTC().AddDate(0, 0, template.DueInDays)
+		dueDate = &due
+	}
+
+	set := bson.M{}
+	if len(labels) > 0 {
+		set["labels"] = labels
+	}
+	if len(checklist) > 0 {
+		subtasks := make([]models.Subtask, 0, len(checklist))
+		for _, item := range checklist {
+			subtasks = append(subtasks, models.Subtask{ID: primitive.NewObjectID(), Title: item})
+		}
+		set["subtasks"] = subtasks
+	}
+	if dueDate != nil {
+		set["dueDate"] = dueDate.UTC()
+	}
+	if len(set) > 0 {
+		if _, err := s.tasksCollection.UpdateOne(ctx, bson.M{"_id": task.ID}, bson.M{"$set": set}); err != nil {
+			logging.Logger.Errorf("Event ID: TASK_TEMPLATE_APPLY_FAILED, Description: Failed to apply template %s to task %s: %v", templateID.Hex(), task.ID.Hex(), err)
+			return nil, fmt.Errorf("task created but template fields were not saved: %v", err)
+		}
+	}
+
+	logging.Logger.Infof("Event ID: TASK_CREATED_FROM_TEMPLATE, Description: Task %s created from template %s.", task.ID.Hex(), templateID.Hex())
+	return s.GetTaskByID(task.ID)
+}
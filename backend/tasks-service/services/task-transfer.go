@@ -0,0 +1,448 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"trello-project/microservices/tasks-service/logging"
+	"trello-project/microservices/tasks-service/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type TransferMode string
+
+const (
+	TransferMove TransferMode = "move"
+	TransferCopy TransferMode = "copy"
+)
+
+// DependencyPolicy određuje šta se dešava sa zavisnostima zadatka pri prenosu.
+// Kod "migrate" zavisnosti ostaju (kod kopije se prenose samo one od kojih kopija
+// zavisi), a kod "sever" se premeštenom zadatku uklanjaju, odnosno ne kopiraju.
+type DependencyPolicy string
+
+const (
+	DependenciesMigrate DependencyPolicy = "migrate"
+	DependenciesSever   DependencyPolicy = "sever"
+)
+
+type TaskTransferRequest struct {
+	TargetProjectID string           `json:"targetProjectId"`
+	Mode            TransferMode     `json:"mode"`
+	Dependencies    DependencyPolicy `json:"dependencies"`
+}
+
+// TaskTransferReport opisuje rezultat prenosa: šta je preneto, a šta je odbačeno.
+type TaskTransferReport struct {
+	Mode                 TransferMode         `json:"mode"`
+	SourceTaskID         string               `json:"sourceTaskId"`
+	TaskID               string               `json:"taskId"`
+	SourceProjectID      string               `json:"sourceProjectId"`
+	TargetProjectID      string               `json:"targetProjectId"`
+	DroppedMembers       []models.Member      `json:"droppedMembers"`
+	DroppedWatchers      []models.Member      `json:"droppedWatchers"`
+	MigratedDependencies []dependencyRelation `json:"migratedDependencies"`
+	DroppedDependencies  []dependencyRelation `json:"droppedDependencies"`
+	Warnings             []string             `json:"warnings"`
+}
+
+func (r *TaskTransferRequest) validate() error {
+	if _, err := primitive.ObjectIDFromHex(r.TargetProjectID); err != nil {
+		return fmt.Errorf("invalid target project ID format")
+	}
+	if r.Mode == "" {
+		r.Mode = TransferMove
+	}
+	if r.Mode != TransferMove && r.Mode != TransferCopy {
+		return fmt.Errorf("unsupported transfer mode: %s", r.Mode)
+	}
+	if r.Dependencies == "" {
+		r.Dependencies = DependenciesSever
+	}
+	if r.Dependencies != DependenciesMigrate && r.Dependencies != DependenciesSever {
+		return fmt.Errorf("unsupported dependency policy: %s", r.Dependencies)
+	}
+	return nil
+}
+
+// TransferTask premešta ili kopira zadatak u drugi projekat. Članovi i posmatrači koji
+// nisu članovi ciljnog projekta se uklanjaju, a zavisnosti se prenose ili prekidaju
+// prema request.Dependencies. Sve što nije preneto navedeno je u izveštaju.
+func (s *TaskService) TransferTask(ctx context.Context, taskID primitive.ObjectID, request TaskTransferRequest, actor string) (*TaskTransferReport, error) {
+	if err := request.validate(); err != nil {
+		return nil, err
+	}
+
+	var task models.Task
+	if err := s.tasksCollection.FindOne(ctx, bson.M{"_id": taskID}).Decode(&task); err != nil {
+		return nil, fmt.Errorf("task not found")
+	}
+	if err := ensureTaskActive(&task); err != nil {
+		return nil, err
+	}
+	if task.ProjectID == request.TargetProjectID {
+		return nil, fmt.Errorf("task already belongs to the target project")
+	}
+
+	targetMembers, err := s.fetchProjectMembers(request.TargetProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch target project members: %v", err)
+	}
+
+	var relations []dependencyRelation
+	if request.Mode == TransferMove || request.Dependencies == DependenciesMigrate {
+		relations, err = s.getTaskRelations(taskID.Hex())
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch task dependencies: %v", err)
+		}
+	}
+
+	report := &TaskTransferReport{
+		Mode:                 request.Mode,
+		SourceTaskID:         taskID.Hex(),
+		SourceProjectID:      task.ProjectID,
+		TargetProjectID:      request.TargetProjectID,
+		DroppedMembers:       []models.Member{},
+		DroppedWatchers:      []models.Member{},
+		MigratedDependencies: []dependencyRelation{},
+		DroppedDependencies:  []dependencyRelation{},
+		Warnings:             []string{},
+	}
+
+	var keptMembers, keptWatchers []models.Member
+	keptMembers, report.DroppedMembers = splitByProjectMembership(task.Members, targetMembers)
+	keptWatchers, report.DroppedWatchers = splitByProjectMembership(task.Watchers, targetMembers)
+
+	if request.Mode == TransferCopy {
+		err = s.copyTaskToProject(ctx, &task, request, keptMembers, relations, actor, report)
+	} else {
+		err = s.moveTaskToProject(ctx, &task, request, keptMembers, keptWatchers, relations, actor, report)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	logging.Logger.Infof("Event ID: TASK_TRANSFERRED, Description: Task %s %s to project %s by %s (%d members dropped, %d dependencies dropped).",
+		taskID.Hex(), request.Mode, request.TargetProjectID, actor, len(report.DroppedMembers), len(report.DroppedDependencies))
+	return report, nil
+}
+
+func (s *TaskService) moveTaskToProject(ctx context.Context, task *models.Task, request TaskTransferRequest, keptMembers, keptWatchers []models.Member, relations []dependencyRelation, actor string, report *TaskTransferReport) error {
+	taskID := task.ID.Hex()
+	target := request.TargetProjectID
+	report.TaskID = taskID
+
+	if err := s.checkWIPLimit(ctx, target, task.Status, false); err != nil {
+		return err
+	}
+	rank, err := s.nextRankInColumn(ctx, target, task.Status)
+	if err != nil {
+		return err
+	}
+
+	// Ciljni projekat se ažurira prvi: ako ne postoji, zadatak ostaje gde je bio
+	if err := s.addTaskToProject(target, taskID); err != nil {
+		return fmt.Errorf("failed to add task to target project: %v", err)
+	}
+
+	// Sprintovi pripadaju projektu, pa premešteni zadatak ide u backlog ciljnog projekta
+	update := bson.M{
+		"$set": bson.M{
+			"projectId": target,
+			"members":   nonNilMembers(keptMembers),
+			"watchers":  nonNilMembers(keptWatchers),
+			"rank":      rank,
+		},
+		"$unset": bson.M{"sprintId": ""},
+	}
+	err = s.inTransaction(ctx, func(ctx context.Context) error {
+		if _, err := s.tasksCollection.UpdateOne(ctx, bson.M{"_id": task.ID}, update); err != nil {
+			return err
+		}
+		err := s.recordHistory(ctx, models.TaskHistoryEntry{
+			TaskID:       task.ID,
+			ProjectID:    target,
+			ActivityType: models.HistoryUpdateTask,
+			Actor:        actor,
+			Field:        "projectId",
+			OldValue:     report.SourceProjectID,
+			NewValue:     target,
+			Details:      fmt.Sprintf("Task '%s' moved to another project", task.Title),
+		})
+		if err != nil {
+			return err
+		}
+		for _, member := range report.DroppedMembers {
+			if err := s.recordMemberHistory(ctx, task, models.HistoryRemoveMember, member, actor); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logging.Logger.Errorf("Event ID: TASK_MOVE_FAILED, Description: Failed to move task %s to project %s: %v", taskID, target, err)
+		if rollbackErr := s.removeTaskFromProject(target, taskID); rollbackErr != nil {
+			logging.Logger.Warnf("Event ID: TASK_MOVE_ROLLBACK_FAILED, Description: Failed to remove task %s from project %s: %v", taskID, target, rollbackErr)
+		}
+		return fmt.Errorf("failed to move task: %v", err)
+	}
+
+	if err := s.removeTaskFromProject(report.SourceProjectID, taskID); err != nil {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("task could not be removed from source project: %v", err))
+	}
+	if err := s.setTaskProjectInWorkflow(taskID, target); err != nil {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("workflow node was not moved: %v", err))
+	}
+
+	for _, relation := range relations {
+		if request.Dependencies == DependenciesMigrate {
+			report.MigratedDependencies = append(report.MigratedDependencies, relation)
+			continue
+		}
+		if err := s.removeDependencyInWorkflow(relation.FromTaskID, relation.ToTaskID); err != nil {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("dependency %s -> %s could not be removed: %v", relation.FromTaskID, relation.ToTaskID, err))
+			continue
+		}
+		report.DroppedDependencies = append(report.DroppedDependencies, relation)
+	}
+
+	for _, member := range report.DroppedMembers {
+		s.notify(ctx, member, fmt.Sprintf("You have been removed from the task '%s' because it moved to a project you are not a member of", task.Title))
+	}
+
+	task.Members = keptMembers
+	task.Watchers = keptWatchers
+	message := fmt.Sprintf("The task '%s' has been moved to another project", task.Title)
+	for _, member := range taskRecipients(task) {
+		s.notify(ctx, member, message)
+	}
+	return nil
+}
+
+// copyTaskToProject kreira novi zadatak u ciljnom projektu sa istim naslovom, opisom,
+// oznakama, rokom i podzadacima. Kopija kreće od statusa Pending i ne nasleđuje
+// pravilo ponavljanja, posmatrače ni komentare.
+func (s *TaskService) copyTaskToProject(ctx context.Context, task *models.Task, request TaskTransferRequest, keptMembers []models.Member, relations []dependencyRelation, actor string, report *TaskTransferReport) error {
+	if err := s.checkWIPLimit(ctx, request.TargetProjectID, models.StatusPending, false); err != nil {
+		return err
+	}
+
+	copied, err := s.CreateTask(request.TargetProjectID, html.UnescapeString(task.Title), html.UnescapeString(task.Description), models.StatusPending, actor)
+	if err != nil {
+		return err
+	}
+	report.TaskID = copied.ID.Hex()
+
+	members := make([]models.Member, 0, len(keptMembers))
+	for _, member := range keptMembers {
+		member.Assignment = models.AssignmentContributor
+		members = append(members, member)
+	}
+	set := bson.M{"members": members}
+	if len(task.Labels) > 0 {
+		set["labels"] = task.Labels
+	}
+	if task.DueDate != nil {
+		set["dueDate"] = task.DueDate
+	}
+	if len(task.Subtasks) > 0 {
+		subtasks := make([]models.Subtask, 0, len(task.Subtasks))
+		for _, subtask := range task.Subtasks {
+			subtasks = append(subtasks, models.Subtask{ID: primitive.NewObjectID(), Title: subtask.Title})
+		}
+		set["subtasks"] = subtasks
+	}
+	err = s.inTransaction(ctx, func(ctx context.Context) error {
+		if _, err := s.tasksCollection.UpdateOne(ctx, bson.M{"_id": copied.ID}, bson.M{"$set": set}); err != nil {
+			return err
+		}
+		for _, member := range members {
+			if err := s.recordMemberHistory(ctx, copied, models.HistoryAddMember, member, actor); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logging.Logger.Errorf("Event ID: TASK_COPY_UPDATE_FAILED, Description: Failed to set copied details on task %s: %v", copied.ID.Hex(), err)
+		report.Warnings = append(report.Warnings, fmt.Sprintf("task copied but details were not saved: %v", err))
+		members = nil
+	}
+
+	for _, relation := range relations {
+		// Kopija može da zavisi od istih zadataka kao original, ali drugi zadaci ne
+		// počinju da zavise od kopije
+		if relation.ToTaskID != task.ID.Hex() {
+			report.DroppedDependencies = append(report.DroppedDependencies, relation)
+			continue
+		}
+		copiedRelation := dependencyRelation{FromTaskID: relation.FromTaskID, ToTaskID: copied.ID.Hex()}
+		if err := s.addDependencyInWorkflow(copiedRelation.FromTaskID, copiedRelation.ToTaskID); err != nil {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("dependency on %s could not be copied: %v", relation.FromTaskID, err))
+			report.DroppedDependencies = append(report.DroppedDependencies, relation)
+			continue
+		}
+		report.MigratedDependencies = append(report.MigratedDependencies, copiedRelation)
+	}
+
+	for _, member := range members {
+		s.notify(ctx, member, fmt.Sprintf("You have been added to the task: %s!", copied.Title))
+	}
+	return nil
+}
+
+// splitByProjectMembership deli članove zadatka na one koji su članovi projekta i ostale.
+func splitByProjectMembership(members, projectMembers []models.Member) (kept, dropped []models.Member) {
+	inProject := make(map[string]bool)
+	for _, member := range projectMembers {
+		inProject[member.ID.Hex()] = true
+		inProject["username:"+member.Username] = true
+	}
+	dropped = []models.Member{}
+	for _, member := range members {
+		if inProject[memberKey(member)] || inProject["username:"+member.Username] {
+			kept = append(kept, member)
+		} else {
+			dropped = append(dropped, member)
+		}
+	}
+	return kept, dropped
+}
+
+func nonNilMembers(members []models.Member) []models.Member {
+	if members == nil {
+		return []models.Member{}
+	}
+	return members
+}
+
+// fetchProjectMembers dohvata članove projekta iz projects-service-a.
+func (s *TaskService) fetchProjectMembers(projectID string) ([]models.Member, error) {
+	projectsURL := os.Getenv("PROJECTS_SERVICE_URL")
+	if projectsURL == "" {
+		return nil, fmt.Errorf("PROJECTS_SERVICE_URL not set")
+	}
+
+	url := fmt.Sprintf("%s/api/projects/%s/members/all", strings.TrimRight(projectsURL, "/"), projectID)
+	result, err := s.ProjectsBreaker.Execute(func() (interface{}, error) {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Role", "manager")
+		resp, err := s.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			return nil, fmt.Errorf("projects-service returned status %d: %s", resp.StatusCode, string(body))
+		}
+
+		var rawMembers []struct {
+			ID       string `json:"_id"`
+			Name     string `json:"name"`
+			LastName string `json:"lastName"`
+			Username string `json:"username"`
+			Role     string `json:"role"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&rawMembers); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %v", err)
+		}
+
+		members := []models.Member{}
+		for _, raw := range rawMembers {
+			objectID, err := primitive.ObjectIDFromHex(raw.ID)
+			if err != nil {
+				continue
+			}
+			members = append(members, models.Member{ID: objectID, Name: raw.Name, LastName: raw.LastName, Username: raw.Username, Role: raw.Role})
+		}
+		return members, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]models.Member), nil
+}
+
+// getTaskRelations vraća sve zavisnosti zadatka iz workflow-service-a, u oba smera.
+func (s *TaskService) getTaskRelations(taskID string) ([]dependencyRelation, error) {
+	workflowURL := os.Getenv("WORKFLOW_SERVICE_URL")
+	if workflowURL == "" {
+		return nil, fmt.Errorf("WORKFLOW_SERVICE_URL not set")
+	}
+
+	url := fmt.Sprintf("%s/api/workflow/task-node/%s/relations", strings.TrimRight(workflowURL, "/"), taskID)
+	result, err := s.WorkflowBreaker.Execute(func() (interface{}, error) {
+		resp, err := s.httpClient.Get(url)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("workflow-service returned status: %d", resp.StatusCode)
+		}
+
+		var relations []dependencyRelation
+		if err := json.NewDecoder(resp.Body).Decode(&relations); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %v", err)
+		}
+		return relations, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]dependencyRelation), nil
+}
+
+// removeDependencyInWorkflow uklanja zavisnost toTaskID -> fromTaskID iz workflow-service-a.
+func (s *TaskService) removeDependencyInWorkflow(fromTaskID, toTaskID string) error {
+	body, _ := json.Marshal(map[string]string{"fromTaskId": fromTaskID, "toTaskId": toTaskID})
+	return s.sendToWorkflow(http.MethodDelete, "/api/workflow/dependency", body, http.StatusOK, http.StatusNotFound)
+}
+
+// setTaskProjectInWorkflow premešta čvor zadatka u drugi projekat.
+func (s *TaskService) setTaskProjectInWorkflow(taskID, projectID string) error {
+	body, _ := json.Marshal(map[string]string{"projectId": projectID})
+	return s.sendToWorkflow(http.MethodPut, fmt.Sprintf("/api/workflow/task-node/%s/project", taskID), body, http.StatusOK)
+}
+
+func (s *TaskService) sendToWorkflow(method, path string, body []byte, okStatuses ...int) error {
+	workflowURL := os.Getenv("WORKFLOW_SERVICE_URL")
+	if workflowURL == "" {
+		return fmt.Errorf("WORKFLOW_SERVICE_URL not set")
+	}
+
+	url := strings.TrimRight(workflowURL, "/") + path
+	_, err := s.WorkflowBreaker.Execute(func() (interface{}, error) {
+		req, err := http.NewRequest(method, url, bytes.NewBuffer(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := s.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		for _, status := range okStatuses {
+			if resp.StatusCode == status {
+				return nil, nil
+			}
+		}
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("workflow-service error: %s", string(respBody))
+	})
+	return err
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"trello-project/microservices/tasks-service/logging"
+	"trello-project/microservices/tasks-service/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memberKey vraća ključ za poređenje članova; stariji zapisi ponekad nemaju _id.
+func memberKey(member models.Member) string {
+	if !member.ID.IsZero() {
+		return member.ID.Hex()
+	}
+	return "username:" + member.Username
+}
+
+// taskRecipients vraća sve korisnike koje treba obavestiti o događaju na zadatku:
+// članove i posmatrače (watchers), bez duplikata. Članovi iz exclude
+// liste se preskaču (npr. kada već dobijaju posebnu poruku).
+func taskRecipients(task *models.Task, exclude ...models.Member) []models.Member {
+	seen := make(map[string]bool)
+	for _, member := range exclude {
+		seen[memberKey(member)] = true
+		seen["username:"+member.Username] = true
+	}
+
+	var recipients []models.Member
+	groups := [][]models.Member{task.Members, task.Watchers}
+	for _, group := range groups {
+		for _, member := range group {
+			if seen[memberKey(member)] || seen["username:"+member.Username] {
+				continue
+			}
+			seen[memberKey(member)] = true
+			seen["username:"+member.Username] = true
+			recipients = append(recipients, member)
+		}
+	}
+	return recipients
+}
+
+// notifyAsync šalje notifikaciju kroz circuit breaker u pozadini.
+func (s *TaskService) notifyAsync(member models.Member, message string) {
+	go func(member models.Member, message string) {
+		_, err := s.NotificationsBreaker.Execute(func() (interface{}, error) {
+			return nil, s.sendNotification(member, message)
+		})
+		if err != nil {
+			logging.Logger.Errorf("Event ID: NOTIFICATION_SEND_FAILED, Description: Failed to send notification to member %s: %v", member.Username, err)
+		}
+	}(member, message)
+}
+
+// notify šalje notifikaciju odmah, ili je dodaje u paket ako je u ctx-u
+// (videti withNotificationBatch), da bi bulk operacije slale jednu poruku po korisniku.
+func (s *TaskService) notify(ctx context.Context, member models.Member, message string) {
+	if batch := notificationBatchFrom(ctx); batch != nil {
+		batch.add(member, message)
+		return
+	}
+	s.notifyAsync(member, message)
+}
+
+// notifyWatchers obaveštava posmatrače zadatka, osim onih koji za isti događaj
+// već dobijaju posebnu poruku (exclude).
+func (s *TaskService) notifyWatchers(ctx context.Context, task *models.Task, message string, exclude ...models.Member) {
+	watchersOnly := &models.Task{Watchers: task.Watchers}
+	for _, watcher := range taskRecipients(watchersOnly, exclude...) {
+		s.notify(ctx, watcher, message)
+	}
+}
+
+// WatchTask dodaje korisnika među posmatrače zadatka.
+func (s *TaskService) WatchTask(ctx context.Context, taskID primitive.ObjectID, watcher models.Member) (*models.Task, error) {
+	if watcher.Username == "" {
+		return nil, fmt.Errorf("watcher username is required")
+	}
+
+	var task models.Task
+	if err := s.tasksCollection.FindOne(ctx, bson.M{"_id": taskID}).Decode(&task); err != nil {
+		logging.Logger.Warnf("Event ID: TASK_NOT_FOUND, Description: Task %s not found for watch: %v", taskID.Hex(), err)
+		return nil, fmt.Errorf("task not found")
+	}
+
+	for _, existing := range task.Watchers {
+		if existing.Username == watcher.Username {
+			logging.Logger.Infof("Event ID: TASK_ALREADY_WATCHED, Description: User %s already watches task %s.", watcher.Username, taskID.Hex())
+			return &task, nil
+		}
+	}
+
+	update := bson.M{"$push": bson.M{"watchers": watcher}}
+	if task.Watchers == nil {
+		update = bson.M{"$set": bson.M{"watchers": []models.Member{watcher}}}
+	}
+	if _, err := s.tasksCollection.UpdateOne(ctx, bson.M{"_id": taskID}, update); err != nil {
+		logging.Logger.Errorf("Event ID: TASK_WATCH_FAILED, Description: Failed to add watcher %s to task %s: %v", watcher.Username, taskID.Hex(), err)
+		return nil, fmt.Errorf("failed to watch task: %v", err)
+	}
+
+	task.Watchers = append(task.Watchers, watcher)
+	logging.Logger.Infof("Event ID: TASK_WATCHED, Description: User %s now watches task %s.", watcher.Username, taskID.Hex())
+	return &task, nil
+}
+
+// UnwatchTask uklanja korisnika iz posmatrača zadatka.
+func (s *TaskService) UnwatchTask(ctx context.Context, taskID primitive.ObjectID, username string) error {
+	result, err := s.tasksCollection.UpdateOne(ctx,
+		bson.M{"_id": taskID},
+		bson.M{"$pull": bson.M{"watchers": bson.M{"username": username}}},
+	)
+	if err != nil {
+		logging.Logger.Errorf("Event ID: TASK_UNWATCH_FAILED, Description: Failed to remove watcher %s from task %s: %v", username, taskID.Hex(), err)
+		return fmt.Errorf("failed to unwatch task: %v", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("task not found")
+	}
+	if result.ModifiedCount == 0 {
+		return fmt.Errorf("user is not watching this task")
+	}
+
+	logging.Logger.Infof("Event ID: TASK_UNWATCHED, Description: User %s stopped watching task %s.", username, taskID.Hex())
+	return nil
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"trello-project/microservices/tasks-service/logging"
+)
+
+// Događaji zadataka koje projects-service prosleđuje webhook-ovima projekta.
+const (
+	webhookTaskCreated       = "task.created"
+	webhookTaskStatusChanged = "task.status_changed"
+)
+
+// publishWebhookEvent prijavljuje događaj projects-service-u u pozadini; neuspeh se samo
+// beleži i ne utiče na izmenu zadatka.
+func (s *TaskService) publishWebhookEvent(projectID, event string, data map[string]interface{}) {
+	s.postToProjectsAsync(projectID, "webhooks/events", map[string]interface{}{"event": event, "data": data}, http.StatusAccepted)
+}
+
+// postToProjectsAsync šalje payload na /api/projects/{projectID}/{path} kao servis, u pozadini.
+func (s *TaskService) postToProjectsAsync(projectID, path string, payload interface{}, expectedStatus int) {
+	projectsURL := os.Getenv("PROJECTS_SERVICE_URL")
+	if projectsURL == "" {
+		return
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+	url := fmt.Sprintf("%s/api/projects/%s/%s", strings.TrimRight(projectsURL, "/"), projectID, path)
+
+	go func() {
+		_, err := s.ProjectsBreaker.Execute(func() (interface{}, error) {
+			req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+			if err != nil {
+				return nil, err
+			}
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Role", "manager")
+			resp, err := s.httpClient.Do(req)
+			if err != nil {
+				return nil, err
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != expectedStatus {
+				respBody, _ := io.ReadAll(resp.Body)
+				return nil, fmt.Errorf("projects-service error: %s", string(respBody))
+			}
+			return nil, nil
+		})
+		if err != nil {
+			logging.Logger.Warnf("Event ID: PROJECTS_SERVICE_PUBLISH_FAILED, Description: Failed to post %s for project %s: %v", path, projectID, err)
+		}
+	}()
+}
@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"trello-project/microservices/tasks-service/logging"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// ExtractUsernameFromToken vraća username iz JWT tokena (bez "Bearer " prefiksa).
+func ExtractUsernameFromToken(tokenString string) (string, error) {
+	secretKey := os.Getenv("JWT_SECRET")
+	if secretKey == "" {
+		logging.Logger.Error("Event ID: CONFIG_ERROR, Description: JWT_SECRET is not set in environment variables.")
+		return "", fmt.Errorf("JWT_SECRET is not set")
+	}
+
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method")
+		}
+		return []byte(secretKey), nil
+	})
+	if err != nil {
+		logging.Logger.Warnf("Event ID: TOKEN_PARSE_ERROR, Description: Error parsing token: %v", err)
+		return "", fmt.Errorf("error parsing token: %v", err)
+	}
+
+	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
+		username, ok := claims["username"].(string)
+		if !ok || username == "" {
+			return "", fmt.Errorf("username claim not found in token")
+		}
+		return username, nil
+	}
+	return "", fmt.Errorf("invalid token")
+}
@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// XLSXWriter upisuje jedan radni list u .xlsx (Office Open XML) format red po red,
+// bez držanja celog dokumenta u memoriji. Sve ćelije se upisuju kao tekst.
+type XLSXWriter struct {
+	zip   *zip.Writer
+	sheet io.Writer
+	rows  int
+}
+
+var xlsxStaticParts = []struct {
+	name    string
+	content string
+}{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+</Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+</Relationships>`},
+}
+
+// NewXLSXWriter počinje .xlsx dokument sa jednim radnim listom.
+func NewXLSXWriter(w io.Writer, sheetName string) (*XLSXWriter, error) {
+	zw := zip.NewWriter(w)
+	for _, part := range xlsxStaticParts {
+		if err := writeZipPart(zw, part.name, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	var name strings.Builder
+	xml.EscapeText(&name, []byte(sheetName))
+	workbook := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets>
+</workbook>`, name.String())
+	if err := writeZipPart(zw, "xl/workbook.xml", workbook); err != nil {
+		return nil, err
+	}
+
+	// Radni list je poslednji deo arhive, pa se redovi upisuju direktno u njega
+	sheet, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(sheet, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`); err != nil {
+		return nil, err
+	}
+	return &XLSXWriter{zip: zw, sheet: sheet}, nil
+}
+
+func writeZipPart(zw *zip.Writer, name, content string) error {
+	part, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(part, content)
+	return err
+}
+
+// WriteRow dodaje jedan red u radni list.
+func (x *XLSXWriter) WriteRow(values []string) error {
+	x.rows++
+	var row strings.Builder
+	fmt.Fprintf(&row, `<row r="%d">`, x.rows)
+	for i, value := range values {
+		fmt.Fprintf(&row, `<c r="%s%d" t="inlineStr"><is><t xml:space="preserve">`, xlsxColumn(i), x.rows)
+		xml.EscapeText(&row, []byte(value))
+		row.WriteString(`</t></is></c>`)
+	}
+	row.WriteString(`</row>`)
+	_, err := io.WriteString(x.sheet, row.String())
+	return err
+}
+
+// Flush šalje do sada upisane redove ka izlazu.
+func (x *XLSXWriter) Flush() error {
+	return x.zip.Flush()
+}
+
+// Close završava radni list i arhivu.
+func (x *XLSXWriter) Close() error {
+	if _, err := io.WriteString(x.sheet, `</sheetData></worksheet>`); err != nil {
+		return err
+	}
+	return x.zip.Close()
+}
+
+// xlsxColumn pretvara indeks kolone (od 0) u oznaku kolone: A, B, ..., Z, AA, ...
+func xlsxColumn(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
@@ -79,6 +79,12 @@ func (h *UserHandler) Register(w http.ResponseWriter, r *http.Request) {
 
 	user := requestData.User
 
+	if !services.ValidRegistrationRole(user.Role) {
+		logging.Logger.Warnf("Event ID: REGISTER_INVALID_ROLE, Description: Registration of '%s' rejected, role '%s' is not allowed.", user.Username, user.Role)
+		http.Error(w, "Invalid role: must be manager or member", http.StatusBadRequest)
+		return
+	}
+
 	if err := h.UserService.ValidatePassword(user.Password); err != nil {
 		logging.Logger.Warnf("Event ID: REGISTER_PASSWORD_VALIDATION_FAILED, Description: Password validation failed for username '%s': %v", user.Username, err)
 		w.Header().Set("Content-Type", "application/json")
@@ -284,9 +290,12 @@ func (h *UserHandler) DeleteAccountHandler(w http.ResponseWriter, r *http.Reques
 
 	err = h.UserService.DeleteAccount(username, tokenString) // Prosledi token dalje
 	if err != nil {
-		if strings.Contains(err.Error(), "unfinished tasks") {
-			logging.Logger.Warnf("Event ID: DELETE_ACCOUNT_UNFINISHED_TASKS, Description: User '%s' cannot be deleted due to unfinished tasks: %v", username, err)
+		if strings.HasPrefix(err.Error(), "cannot delete account") {
+			logging.Logger.Warnf("Event ID: DELETE_ACCOUNT_CONFLICT, Description: User '%s' cannot be deleted: %v", username, err)
 			http.Error(w, err.Error(), http.StatusConflict)
+		} else if strings.HasPrefix(err.Error(), "projects service unavailable") {
+			logging.Logger.Warnf("Event ID: DELETE_ACCOUNT_UNAVAILABLE, Description: Account '%s' not deleted: %v", username, err)
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
 		} else {
 			logging.Logger.Errorf("Event ID: DELETE_ACCOUNT_FAILED, Description: Failed to delete account for '%s': %v", username, err)
 			http.Error(w, "Failed to delete account: "+err.Error(), http.StatusInternalServerError)
@@ -56,10 +56,21 @@ func NewUserService(
 	}
 }
 
+// ValidRegistrationRole vraća true za uloge koje korisnik može sam da izabere pri
+// registraciji. Administratori se ne registruju kroz aplikaciju.
+func ValidRegistrationRole(role string) bool {
+	return role == "manager" || role == "member"
+}
+
 // RegisterUser šalje verifikacioni email korisniku i čuva podatke u kešu
 func (s *UserService) RegisterUser(user models.User) error {
 	logging.Logger.Debugf("Event ID: REGISTER_USER_START, Description: Attempting to register user with username: %s, email: %s", user.Username, user.Email)
 
+	if !ValidRegistrationRole(user.Role) {
+		logging.Logger.Warnf("Event ID: REGISTER_USER_INVALID_ROLE, Description: Rejected registration of '%s' with role '%s'.", user.Username, user.Role)
+		return fmt.Errorf("invalid role: must be manager or member")
+	}
+
 	// Provera da li korisnik već postoji
 	var existingUser models.User
 	if err := s.UserCollection.FindOne(context.Background(), bson.M{"username": user.Username}).Decode(&existingUser); err == nil {
@@ -272,10 +283,10 @@ func (s *UserService) DeleteAccount(username string, authToken string) error {
 
 	var projectIDs []string
 	if role == "manager" {
-		url := fmt.Sprintf("%s/api/projects/username/%s", strings.TrimRight(projectsServiceURL, "/"), username)
+		url := fmt.Sprintf("%s/api/projects/username/%s?archived=include", strings.TrimRight(projectsServiceURL, "/"), username)
 		projectIDs, _ = getProjectIDs(url, role)
 	} else if role == "member" {
-		url := fmt.Sprintf("%s/api/projects/user-projects/%s", strings.TrimRight(projectsServiceURL, "/"), username)
+		url := fmt.Sprintf("%s/api/projects/user-projects/%s?archived=include", strings.TrimRight(projectsServiceURL, "/"), username)
 		projectIDs, _ = getProjectIDs(url, role)
 	}
 
@@ -306,38 +317,9 @@ func (s *UserService) DeleteAccount(username string, authToken string) error {
 		}
 	}
 
-	if role == "manager" {
-		for _, projectID := range projectIDs {
-			projectID := projectID
-
-			_, err := s.ProjectsBreaker.Execute(func() (interface{}, error) {
-				deleteURL := fmt.Sprintf("%s/api/projects/%s", strings.TrimRight(projectsServiceURL, "/"), projectID)
-				req, err := http.NewRequest(http.MethodDelete, deleteURL, nil)
-				if err != nil {
-					return nil, fmt.Errorf("failed to create delete request: %v", err)
-				}
-				req.Header.Set("Authorization", "Bearer "+authToken)
-				req.Header.Set("Role", role)
-
-				resp, err := s.HTTPClient.Do(req)
-				if err != nil {
-					return nil, fmt.Errorf("failed to delete project: %v", err)
-				}
-				defer resp.Body.Close()
-
-				if resp.StatusCode != http.StatusOK {
-					return nil, fmt.Errorf("failed to delete project %s: status %d", projectID, resp.StatusCode)
-				}
-				return nil, nil
-			})
-
-			if err != nil {
-				logging.Logger.Warnf("[Fallback] Failed to delete project %s: %v", projectID, err)
-			}
-		}
-	}
-
-	_, err = s.ProjectsBreaker.Execute(func() (interface{}, error) {
+	// Menadžer koji je još vlasnik projekata ne može da obriše nalog dok ne prenese
+	// vlasništvo; projects-service tada vraća 409 sa razlogom.
+	conflict, err := s.ProjectsBreaker.Execute(func() (interface{}, error) {
 		patchURL := fmt.Sprintf("%s/api/projects/remove-user/%s?role=%s", strings.TrimRight(projectsServiceURL, "/"), userID, role)
 		req, err := http.NewRequest(http.MethodPatch, patchURL, nil)
 		if err != nil {
@@ -351,6 +333,10 @@ func (s *UserService) DeleteAccount(username string, authToken string) error {
 		}
 		defer resp.Body.Close()
 
+		if resp.StatusCode == http.StatusConflict {
+			bodyBytes, _ := io.ReadAll(resp.Body)
+			return strings.TrimSpace(string(bodyBytes)), nil
+		}
 		if resp.StatusCode != http.StatusOK {
 			bodyBytes, _ := io.ReadAll(resp.Body)
 			return nil, fmt.Errorf("failed to remove user from projects: status %d, response: %s", resp.StatusCode, string(bodyBytes))
@@ -359,8 +345,18 @@ func (s *UserService) DeleteAccount(username string, authToken string) error {
 		return nil, nil
 	})
 	if err != nil {
+		// Bez odgovora projects-service-a ne zna se da li je menadžer još vlasnik projekata,
+		// pa se nalog ne briše da projekti ne bi ostali bez vlasnika
+		if role == "manager" {
+			logging.Logger.Errorf("Event ID: DELETE_ACCOUNT_OWNERSHIP_UNKNOWN, Description: Project ownership of '%s' could not be checked: %v", username, err)
+			return fmt.Errorf("projects service unavailable: project ownership could not be checked")
+		}
 		logging.Logger.Warnf("[Fallback] User not removed from projects (project-service down?): %v", err)
 	}
+	if reason, ok := conflict.(string); ok {
+		logging.Logger.Warnf("Event ID: DELETE_ACCOUNT_OWNS_PROJECTS, Description: Account '%s' still owns projects: %s", username, reason)
+		return fmt.Errorf("cannot delete account: %s", reason)
+	}
 
 	if role == "member" {
 		_, err := s.TasksBreaker.Execute(func() (interface{}, error) {
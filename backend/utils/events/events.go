@@ -0,0 +1,133 @@
+// Package events sadrži domenske događaje koje tasks-service i projects-service objavljuju
+// na NATS JetStream, kao i outbox preko kog se objavljuju bez gubitaka.
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+const (
+	// StreamName je JetStream stream koji čuva sve događaje, bez isteka, da bi
+	// potrošači mogli da obnove svoje podatke ponovnim čitanjem od početka.
+	StreamName    = "TRELLO_EVENTS"
+	SubjectPrefix = "trello."
+	SubjectAll    = SubjectPrefix + ">"
+)
+
+type Type string
+
+const (
+	TaskCreated       Type = "task.created"
+	TaskStatusChanged Type = "task.status_changed"
+	TaskMemberAdded   Type = "task.member_added"
+	TaskMemberRemoved Type = "task.member_removed"
+	TaskMoved         Type = "task.moved"
+	TaskArchived      Type = "task.archived"
+	TaskDeleted       Type = "task.deleted"
+	TaskRestored      Type = "task.restored"
+
+	ProjectCreated       Type = "project.created"
+	ProjectUpdated       Type = "project.updated"
+	ProjectDeleted       Type = "project.deleted"
+	ProjectMemberAdded   Type = "project.member_added"
+	ProjectMemberRemoved Type = "project.member_removed"
+)
+
+// Event je jedan domenski događaj. ID je jedinstven i stabilan: ponovno objavljivanje
+// istog događaja nosi isti ID, pa potrošači po njemu odbacuju duplikate.
+type Event struct {
+	ID              string     `json:"id" bson:"_id"`
+	Type            Type       `json:"type" bson:"type"`
+	ProjectID       string     `json:"projectId" bson:"projectId"`
+	TaskID          string     `json:"taskId,omitempty" bson:"taskId,omitempty"`
+	MemberID        string     `json:"memberId,omitempty" bson:"memberId,omitempty"`
+	Actor           string     `json:"actor,omitempty" bson:"actor,omitempty"`
+	OldStatus       string     `json:"oldStatus,omitempty" bson:"oldStatus,omitempty"`
+	NewStatus       string     `json:"newStatus,omitempty" bson:"newStatus,omitempty"`
+	ExpectedEndDate *time.Time `json:"expectedEndDate,omitempty" bson:"expectedEndDate,omitempty"`
+	OccurredAt      time.Time  `json:"occurredAt" bson:"occurredAt"`
+}
+
+// Subject vraća NATS subject na kome se objavljuje događaj datog tipa.
+func Subject(eventType Type) string {
+	return SubjectPrefix + string(eventType)
+}
+
+// Connect otvara konekciju ka NATS-u koja se sama ponovo uspostavlja, pa servis može
+// da se pokrene i pre broker-a.
+func Connect(url, name string) (*nats.Conn, error) {
+	return nats.Connect(url,
+		nats.Name(name),
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1),
+		nats.ReconnectWait(2*time.Second),
+	)
+}
+
+// EnsureStream kreira StreamName ako još ne postoji.
+func EnsureStream(js nats.JetStreamContext) error {
+	_, err := js.StreamInfo(StreamName)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, nats.ErrStreamNotFound) {
+		return err
+	}
+	_, err = js.AddStream(&nats.StreamConfig{
+		Name:       StreamName,
+		Subjects:   []string{SubjectAll},
+		Storage:    nats.FileStorage,
+		Retention:  nats.LimitsPolicy,
+		Duplicates: 10 * time.Minute,
+	})
+	if err != nil && !errors.Is(err, nats.ErrStreamNameAlreadyInUse) {
+		return err
+	}
+	return nil
+}
+
+// Publisher objavljuje događaje na JetStream. ID događaja se šalje kao Nats-Msg-Id, pa
+// broker u okviru Duplicates prozora sam odbacuje ponovljena objavljivanja.
+type Publisher struct {
+	js nats.JetStreamContext
+
+	mu            sync.Mutex
+	streamEnsured bool
+}
+
+func NewPublisher(nc *nats.Conn) (*Publisher, error) {
+	js, err := nc.JetStream()
+	if err != nil {
+		return nil, err
+	}
+	return &Publisher{js: js}, nil
+}
+
+func (p *Publisher) Publish(event Event) error {
+	// Stream se proverava pri prvom uspešnom objavljivanju, jer broker možda nije bio
+	// dostupan kada je servis pokrenut
+	p.mu.Lock()
+	if !p.streamEnsured {
+		if err := EnsureStream(p.js); err != nil {
+			p.mu.Unlock()
+			return fmt.Errorf("failed to ensure event stream: %v", err)
+		}
+		p.streamEnsured = true
+	}
+	p.mu.Unlock()
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %v", err)
+	}
+	if _, err := p.js.Publish(Subject(event.Type), data, nats.MsgId(event.ID)); err != nil {
+		return fmt.Errorf("failed to publish event %s: %v", event.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// publishedRetention je koliko se objavljeni događaji zadržavaju u outbox-u.
+const publishedRetention = 7 * 24 * time.Hour
+
+type outboxEntry struct {
+	ID          string     `bson:"_id"`
+	Event       Event      `bson:"event"`
+	CreatedAt   time.Time  `bson:"createdAt"`
+	PublishedAt *time.Time `bson:"publishedAt,omitempty"`
+}
+
+// Outbox čuva događaje u bazi servisa pre objavljivanja. Događaj koji ne uspe da se
+// objavi odmah (npr. broker nije dostupan) objavljuje Relay pri sledećem prolazu.
+type Outbox struct {
+	collection *mongo.Collection
+	publisher  *Publisher
+}
+
+func NewOutbox(collection *mongo.Collection, publisher *Publisher) *Outbox {
+	return &Outbox{collection: collection, publisher: publisher}
+}
+
+// EnsureIndexes kreira indekse za Relay i za brisanje starih objavljenih događaja.
+func (o *Outbox) EnsureIndexes(ctx context.Context) error {
+	_, err := o.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "publishedAt", Value: 1}, {Key: "createdAt", Value: 1}}},
+		{
+			Keys:    bson.D{{Key: "publishedAt", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(publishedRetention.Seconds())).SetName("publishedAt_ttl"),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create event outbox indexes: %v", err)
+	}
+	return nil
+}
+
+// Add upisuje događaj u outbox. Događaj sa istim ID-jem se upisuje samo jednom.
+func (o *Outbox) Add(ctx context.Context, event Event) error {
+	entry := outboxEntry{ID: event.ID, Event: event, CreatedAt: time.Now().UTC()}
+	if _, err := o.collection.InsertOne(ctx, entry); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to store event %s: %v", event.ID, err)
+	}
+	return nil
+}
+
+// Transaction izvršava write u Mongo transakciji na bazi outbox-a. Događaji koje write
+// upiše kroz Add sa dobijenim kontekstom čuvaju se samo ako je sačuvana i izmena, pa pad
+// servisa između izmene i upisa događaja ne može da izgubi događaj. write može biti
+// pozvan više puta ako se transakcija ponavlja, pa ne sme imati spoljne efekte.
+func (o *Outbox) Transaction(ctx context.Context, write func(ctx mongo.SessionContext) error) error {
+	return o.collection.Database().Client().UseSession(ctx, func(session mongo.SessionContext) error {
+		_, err := session.WithTransaction(session, func(txCtx mongo.SessionContext) (interface{}, error) {
+			return nil, write(txCtx)
+		})
+		return err
+	})
+}
+
+// Send objavljuje događaj i označava ga kao objavljen.
+func (o *Outbox) Send(ctx context.Context, event Event) error {
+	if err := o.publisher.Publish(event); err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	if _, err := o.collection.UpdateOne(ctx, bson.M{"_id": event.ID}, bson.M{"$set": bson.M{"publishedAt": now}}); err != nil {
+		return fmt.Errorf("failed to mark event %s as published: %v", event.ID, err)
+	}
+	return nil
+}
+
+// Relay objavljuje neobjavljene događaje starije od olderThan, redom kojim su nastali.
+// Mlađe događaje preskače jer ih Send verovatno upravo objavljuje. Zaustavlja se na
+// prvoj grešci i vraća broj objavljenih događaja.
+func (o *Outbox) Relay(ctx context.Context, olderThan time.Duration, limit int64) (int, error) {
+	filter := bson.M{
+		"publishedAt": bson.M{"$exists": false},
+		"createdAt":   bson.M{"$lte": time.Now().UTC().Add(-olderThan)},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}}).SetLimit(limit)
+	cursor, err := o.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load pending events: %v", err)
+	}
+	var pending []outboxEntry
+	if err := cursor.All(ctx, &pending); err != nil {
+		return 0, fmt.Errorf("failed to decode pending events: %v", err)
+	}
+
+	for i, entry := range pending {
+		if err := o.Send(ctx, entry.Event); err != nil {
+			return i, err
+		}
+	}
+	return len(pending), nil
+}
@@ -0,0 +1,178 @@
+// Package projectroles sadrži uloge korisnika na nivou projekta i zajedničku proveru
+// koju koriste projects-service i tasks-service.
+package projectroles
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+type Role string
+
+const (
+	Owner       Role = "owner"
+	Maintainer  Role = "maintainer"
+	Contributor Role = "contributor"
+	Viewer      Role = "viewer"
+)
+
+// DefaultMemberRole dobijaju novi članovi i članovi dodati pre uvođenja uloga po projektu.
+const DefaultMemberRole = Contributor
+
+var rank = map[Role]int{
+	Viewer:      1,
+	Contributor: 2,
+	Maintainer:  3,
+	Owner:       4,
+}
+
+var (
+	ErrNoAccess         = errors.New("user is not a member of this project")
+	ErrInsufficientRole = errors.New("insufficient project role")
+	ErrProjectNotFound  = errors.New("project not found")
+	ErrProjectDeleting  = errors.New("project is being deleted")
+	ErrProjectArchived  = errors.New("project is archived and read-only")
+)
+
+// Access je uloga korisnika na projektu zajedno sa time da li je projekat arhiviran.
+type Access struct {
+	Role     Role
+	Archived bool
+}
+
+// Parse vraća ulogu za dati naziv; false ako uloga ne postoji.
+func Parse(value string) (Role, bool) {
+	role := Role(strings.ToLower(strings.TrimSpace(value)))
+	_, ok := rank[role]
+	return role, ok
+}
+
+// Effective vraća ulogu sačuvanu uz člana projekta, odnosno DefaultMemberRole ako je prazna.
+func Effective(stored string) Role {
+	if role, ok := Parse(stored); ok {
+		return role
+	}
+	return DefaultMemberRole
+}
+
+// AtLeast proverava da li uloga ima bar prava uloge min.
+func (r Role) AtLeast(min Role) bool {
+	return rank[r] > 0 && rank[r] >= rank[min]
+}
+
+// Breaker odgovara gobreaker.CircuitBreaker-u, pa servisi prosleđuju svoj breaker za projects-service.
+type Breaker interface {
+	Execute(req func() (interface{}, error)) (interface{}, error)
+}
+
+// Resolver dohvata ulogu korisnika na projektu iz projects-service-a.
+type Resolver struct {
+	projectsServiceURL string
+	client             *http.Client
+	breaker            Breaker
+}
+
+func NewResolver(projectsServiceURL string, client *http.Client, breaker Breaker) *Resolver {
+	return &Resolver{
+		projectsServiceURL: strings.TrimRight(projectsServiceURL, "/"),
+		client:             client,
+		breaker:            breaker,
+	}
+}
+
+// Resolve vraća ulogu korisnika iz Authorization zaglavlja na projektu projectID.
+func (r *Resolver) Resolve(projectID, authorization string) (Role, error) {
+	access, err := r.ResolveAccess(projectID, authorization)
+	return access.Role, err
+}
+
+// ResolveAccess vraća ulogu korisnika i stanje arhiviranosti projekta projectID.
+func (r *Resolver) ResolveAccess(projectID, authorization string) (Access, error) {
+	if r.projectsServiceURL == "" {
+		return Access{}, fmt.Errorf("projects service URL not set")
+	}
+	url := fmt.Sprintf("%s/api/projects/%s/role", r.projectsServiceURL, projectID)
+
+	result, err := r.breaker.Execute(func() (interface{}, error) {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", authorization)
+		resp, err := r.client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		switch resp.StatusCode {
+		case http.StatusOK:
+		case http.StatusNotFound, http.StatusBadRequest:
+			// Nepostojeći projekat nije kvar projects-service-a i ne sme da otvori breaker
+			return nil, nil
+		case http.StatusConflict:
+			// Projekat koji se briše odbija pristup, ali to takođe nije kvar servisa
+			return ErrProjectDeleting, nil
+		default:
+			body, _ := io.ReadAll(resp.Body)
+			return nil, fmt.Errorf("projects-service error (%d): %s", resp.StatusCode, strings.TrimSpace(string(body)))
+		}
+
+		var data struct {
+			Role     string `json:"role"`
+			Archived bool   `json:"archived"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+			return nil, fmt.Errorf("failed to decode project role: %v", err)
+		}
+		return Access{Role: Role(data.Role), Archived: data.Archived}, nil
+	})
+	if err != nil {
+		return Access{}, err
+	}
+
+	if result == nil {
+		return Access{}, ErrProjectNotFound
+	}
+	if err, ok := result.(error); ok {
+		return Access{}, err
+	}
+	access := result.(Access)
+	if access.Role == "" {
+		return Access{}, ErrNoAccess
+	}
+	return access, nil
+}
+
+// Require vraća ulogu korisnika ako ima bar prava uloge min, a u suprotnom ErrNoAccess
+// ili ErrInsufficientRole.
+func (r *Resolver) Require(projectID, authorization string, min Role) (Role, error) {
+	role, err := r.Resolve(projectID, authorization)
+	if err != nil {
+		return "", err
+	}
+	if !role.AtLeast(min) {
+		return role, ErrInsufficientRole
+	}
+	return role, nil
+}
+
+// RequireWrite je Require za izmene: na arhiviranom projektu vraća ErrProjectArchived,
+// bez obzira na ulogu.
+func (r *Resolver) RequireWrite(projectID, authorization string, min Role) (Role, error) {
+	access, err := r.ResolveAccess(projectID, authorization)
+	if err != nil {
+		return "", err
+	}
+	if !access.Role.AtLeast(min) {
+		return access.Role, ErrInsufficientRole
+	}
+	if access.Archived {
+		return access.Role, ErrProjectArchived
+	}
+	return access.Role, nil
+}
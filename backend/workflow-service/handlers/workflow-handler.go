@@ -155,3 +155,149 @@ func (h *WorkflowHandler) GetWorkflowGraph(w http.ResponseWriter, r *http.Reques
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
+
+func (h *WorkflowHandler) SetTaskNodeArchived(w http.ResponseWriter, r *http.Request) {
+	taskID := mux.Vars(r)["id"]
+
+	var request struct {
+		Archived bool `json:"archived"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		logging.Logger.Errorf("Failed to decode archived request: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.WorkflowService.SetArchivedStatus(r.Context(), taskID, request.Archived); err != nil {
+		if err.Error() == "task node not found" {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to set archived status: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Archived status updated"))
+}
+
+func (h *WorkflowHandler) SetProjectArchived(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["projectId"]
+
+	var request struct {
+		Archived bool `json:"archived"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		logging.Logger.Errorf("Failed to decode archived request: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	updated, err := h.WorkflowService.SetProjectArchivedStatus(r.Context(), projectID, request.Archived)
+	if err != nil {
+		http.Error(w, "Failed to set archived status: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]int64{"updated": updated})
+}
+
+func (h *WorkflowHandler) DeleteProjectNodes(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["projectId"]
+
+	deleted, err := h.WorkflowService.DeleteProjectNodes(r.Context(), projectID)
+	if err != nil {
+		http.Error(w, "Failed to delete project nodes: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]int64{"deleted": deleted})
+}
+
+func (h *WorkflowHandler) CloneProjectDependencies(w http.ResponseWriter, r *http.Request) {
+	projectID := mux.Vars(r)["projectId"]
+
+	var request struct {
+		TargetProjectID string            `json:"targetProjectId"`
+		TaskIDMap       map[string]string `json:"taskIdMap"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.TargetProjectID == "" {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	copied, err := h.WorkflowService.CloneProjectDependencies(r.Context(), projectID, request.TargetProjectID, request.TaskIDMap)
+	if err != nil {
+		http.Error(w, "Failed to clone project dependencies: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]int64{"copied": copied})
+}
+
+func (h *WorkflowHandler) RemoveDependency(w http.ResponseWriter, r *http.Request) {
+	var relation models.TaskDependencyRelation
+	if err := json.NewDecoder(r.Body).Decode(&relation); err != nil {
+		logging.Logger.Errorf("Failed to decode request body: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if relation.FromTaskID == "" || relation.ToTaskID == "" {
+		http.Error(w, "Missing task IDs", http.StatusBadRequest)
+		return
+	}
+
+	handler := commands.NewRemoveDependencyHandler(h.WorkflowService)
+	if err := handler.Handle(r.Context(), commands.RemoveDependencyCommand{Dependency: relation}); err != nil {
+		logging.Logger.Errorf("Failed to remove dependency: %v", err)
+		if err.Error() == "dependency not found" {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Dependency successfully removed"))
+}
+
+func (h *WorkflowHandler) GetTaskRelations(w http.ResponseWriter, r *http.Request) {
+	taskID := mux.Vars(r)["id"]
+
+	relations, err := h.WorkflowService.GetTaskRelations(r.Context(), taskID)
+	if err != nil {
+		http.Error(w, "Failed to fetch task relations: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(relations)
+}
+
+func (h *WorkflowHandler) SetTaskNodeProject(w http.ResponseWriter, r *http.Request) {
+	taskID := mux.Vars(r)["id"]
+
+	var request struct {
+		ProjectID string `json:"projectId"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.ProjectID == "" {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.WorkflowService.SetTaskProject(r.Context(), taskID, request.ProjectID); err != nil {
+		if err.Error() == "task node not found" {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Task node project updated"))
+}
@@ -15,6 +15,7 @@ type Query interface {
 
 type WorkflowCommandContext interface {
 	AddDependency(ctx context.Context, dependency models.TaskDependencyRelation) error
+	RemoveDependency(ctx context.Context, dependency models.TaskDependencyRelation) error
 	UpdateBlockedStatus(ctx context.Context, taskID string) error
 }
 
@@ -44,10 +44,17 @@ func main() {
 	router := mux.NewRouter()
 
 	router.HandleFunc("/api/workflow/dependency", workflowHandler.AddDependency).Methods("POST")
+	router.HandleFunc("/api/workflow/dependency", workflowHandler.RemoveDependency).Methods("DELETE")
 	router.HandleFunc("/api/workflow/task-node", workflowHandler.EnsureTaskNode).Methods("POST")
 	router.HandleFunc("/api/workflow/dependencies/{taskId}", workflowHandler.GetDependencies).Methods("GET")
 	router.HandleFunc("/api/workflow/project/{projectId}/dependencies", workflowHandler.GetProjectDependencies).Methods("GET")
 	router.HandleFunc("/api/workflow/graph/{projectId}", workflowHandler.GetWorkflowGraph).Methods("GET")
+	router.HandleFunc("/api/workflow/task-node/{id}/relations", workflowHandler.GetTaskRelations).Methods("GET")
+	router.HandleFunc("/api/workflow/task-node/{id}/project", workflowHandler.SetTaskNodeProject).Methods("PUT")
+	router.HandleFunc("/api/workflow/task-node/{id}/archived", workflowHandler.SetTaskNodeArchived).Methods("PUT")
+	router.HandleFunc("/api/workflow/project/{projectId}/archived", workflowHandler.SetProjectArchived).Methods("PUT")
+	router.HandleFunc("/api/workflow/project/{projectId}", workflowHandler.DeleteProjectNodes).Methods("DELETE")
+	router.HandleFunc("/api/workflow/project/{projectId}/clone", workflowHandler.CloneProjectDependencies).Methods("POST")
 
 	port := os.Getenv("SERVER_PORT")
 	if port == "" {
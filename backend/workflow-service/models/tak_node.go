@@ -6,4 +6,6 @@ type TaskNode struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 	Blocked     bool   `json:"blocked"`
+	// Archived čvorovi ostaju u grafu da bi istorija zavisnosti bila sačuvana
+	Archived bool `json:"archived"`
 }
@@ -0,0 +1,37 @@
+package commands
+
+import (
+	"context"
+	"log"
+	"trello-project/microservices/workflow-service/interfaces"
+	"trello-project/microservices/workflow-service/models"
+)
+
+type RemoveDependencyCommand struct {
+	Dependency models.TaskDependencyRelation
+}
+
+type RemoveDependencyHandler struct {
+	GraphService interfaces.WorkflowCommandContext
+}
+
+func NewRemoveDependencyHandler(ctx interfaces.WorkflowCommandContext) *RemoveDependencyHandler {
+	return &RemoveDependencyHandler{GraphService: ctx}
+}
+
+func (h *RemoveDependencyHandler) Handle(ctx context.Context, cmd RemoveDependencyCommand) error {
+	if err := h.GraphService.RemoveDependency(ctx, cmd.Dependency); err != nil {
+		return err
+	}
+
+	// Nakon uklanjanja zavisnosti, apdejtuj blokiranost zavisnog taska
+	updateCmd := UpdateBlockedStatusCommand{
+		TaskID: cmd.Dependency.ToTaskID,
+		Svc:    h.GraphService,
+	}
+	if err := updateCmd.Execute(ctx); err != nil {
+		log.Printf("warning: dependency removed, but failed to update blocked statuses: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"trello-project/microservices/workflow-service/logging"
+	"trello-project/microservices/workflow-service/models"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+var webhookClient = &http.Client{Timeout: 5 * time.Second}
+
+// PublishDependencyAdded prijavljuje projects-service-u novu zavisnost, da bi je prosledio
+// webhook-ovima projekta. Radi u pozadini; neuspeh se samo beleži.
+func (s *WorkflowService) PublishDependencyAdded(rel models.TaskDependencyRelation) {
+	projectsURL := os.Getenv("PROJECTS_SERVICE_URL")
+	if projectsURL == "" {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		data, projectID, err := s.dependencyEventData(ctx, rel)
+		if err != nil {
+			logging.Logger.Warnf("Failed to load tasks of dependency %s <- %s for webhook event: %v", rel.ToTaskID, rel.FromTaskID, err)
+			return
+		}
+		body, _ := json.Marshal(map[string]any{"event": "dependency.added", "data": data})
+
+		url := fmt.Sprintf("%s/api/projects/%s/webhooks/events", strings.TrimRight(projectsURL, "/"), projectID)
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Role", "manager")
+
+		resp, err := webhookClient.Do(req)
+		if err != nil {
+			logging.Logger.Warnf("Failed to publish dependency.added for project %s: %v", projectID, err)
+			return
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusAccepted {
+			respBody, _ := io.ReadAll(resp.Body)
+			logging.Logger.Warnf("projects-service rejected dependency.added for project %s: %s", projectID, string(respBody))
+		}
+	}()
+}
+
+func (s *WorkflowService) dependencyEventData(ctx context.Context, rel models.TaskDependencyRelation) (map[string]any, string, error) {
+	session := s.Driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
+	defer session.Close(ctx)
+
+	result, err := session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		res, err := tx.Run(ctx, `
+			MATCH (from:Task {id: $fromId}), (to:Task {id: $toId})
+			RETURN to.projectId AS projectId, from.name AS fromName, to.name AS toName
+		`, map[string]any{"fromId": rel.FromTaskID, "toId": rel.ToTaskID})
+		if err != nil {
+			return nil, err
+		}
+		record, err := res.Single(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return record.AsMap(), nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	values := result.(map[string]any)
+	projectID, _ := values["projectId"].(string)
+	if projectID == "" {
+		return nil, "", fmt.Errorf("task node has no project")
+	}
+	return map[string]any{
+		"fromTaskId":   rel.FromTaskID,
+		"fromTaskName": values["fromName"],
+		"toTaskId":     rel.ToTaskID,
+		"toTaskName":   values["toName"],
+	}, projectID, nil
+}
@@ -83,6 +83,110 @@ func (s *WorkflowService) AddDependency(ctx context.Context, rel models.TaskDepe
 		logging.Logger.Warnf("Failed to update blocked status for task %s: %v", rel.ToTaskID, err)
 	}
 
+	s.PublishDependencyAdded(rel)
+	return nil
+}
+
+// RemoveDependency briše vezu (to)-[:DEPENDS_ON]->(from). Čvorovi ostaju u grafu.
+func (s *WorkflowService) RemoveDependency(ctx context.Context, rel models.TaskDependencyRelation) error {
+	session := s.Driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
+	defer session.Close(ctx)
+
+	logging.Logger.Infof("Attempting to remove dependency: %s <- %s", rel.ToTaskID, rel.FromTaskID)
+
+	result, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		res, err := tx.Run(ctx, `
+			MATCH (to:Task {id: $toId})-[r:DEPENDS_ON]->(from:Task {id: $fromId})
+			DELETE r
+			RETURN count(r) AS removed
+		`, map[string]any{
+			"fromId": rel.FromTaskID,
+			"toId":   rel.ToTaskID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		record, err := res.Single(ctx)
+		if err != nil {
+			return nil, err
+		}
+		removed, _ := record.Get("removed")
+		return removed.(int64), nil
+	})
+	if err != nil {
+		logging.Logger.Errorf("Failed to remove dependency relation: %v", err)
+		return fmt.Errorf("failed to remove dependency relation: %v", err)
+	}
+	if result.(int64) == 0 {
+		return fmt.Errorf("dependency not found")
+	}
+
+	logging.Logger.Infof("Dependency successfully removed: %s <- %s", rel.ToTaskID, rel.FromTaskID)
+	return nil
+}
+
+// GetTaskRelations vraća sve zavisnosti u kojima učestvuje zadatak, u oba smera.
+func (s *WorkflowService) GetTaskRelations(ctx context.Context, taskID string) ([]models.TaskDependencyRelation, error) {
+	session := s.Driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
+	defer session.Close(ctx)
+
+	result, err := session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		res, err := tx.Run(ctx, `
+			MATCH (to:Task)-[:DEPENDS_ON]->(from:Task)
+			WHERE to.id = $taskId OR from.id = $taskId
+			RETURN from.id AS fromTaskId, to.id AS toTaskId
+		`, map[string]any{"taskId": taskID})
+		if err != nil {
+			return nil, err
+		}
+
+		deps := []models.TaskDependencyRelation{}
+		for res.Next(ctx) {
+			record := res.Record()
+			deps = append(deps, models.TaskDependencyRelation{
+				FromTaskID: record.Values[0].(string),
+				ToTaskID:   record.Values[1].(string),
+			})
+		}
+		return deps, res.Err()
+	})
+	if err != nil {
+		logging.Logger.Errorf("Failed to fetch relations for task %s: %v", taskID, err)
+		return nil, err
+	}
+	return result.([]models.TaskDependencyRelation), nil
+}
+
+// SetTaskProject premešta čvor zadatka u drugi projekat; zavisnosti se ne menjaju.
+func (s *WorkflowService) SetTaskProject(ctx context.Context, taskID, projectID string) error {
+	session := s.Driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
+	defer session.Close(ctx)
+
+	result, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		res, err := tx.Run(ctx, `
+			MATCH (t:Task {id: $taskId})
+			SET t.projectId = $projectId
+			RETURN count(t) AS updated
+		`, map[string]any{"taskId": taskID, "projectId": projectID})
+		if err != nil {
+			return nil, err
+		}
+		record, err := res.Single(ctx)
+		if err != nil {
+			return nil, err
+		}
+		updated, _ := record.Get("updated")
+		return updated.(int64), nil
+	})
+	if err != nil {
+		logging.Logger.Errorf("Failed to move task node %s to project %s: %v", taskID, projectID, err)
+		return fmt.Errorf("failed to update task node project: %v", err)
+	}
+	if result.(int64) == 0 {
+		return fmt.Errorf("task node not found")
+	}
+
+	logging.Logger.Infof("Task node %s moved to project %s", taskID, projectID)
 	return nil
 }
 
@@ -208,7 +312,8 @@ func (s *WorkflowService) GetDependencies(ctx context.Context, taskId string) ([
 		query := `
 			MATCH (to:Task {id: $taskId})-[:DEPENDS_ON]->(from:Task)
 			RETURN from.id AS id, from.projectId AS projectId, from.name AS name,
-			       from.description AS description, from.blocked AS blocked
+			       from.description AS description, from.blocked AS blocked,
+			       coalesce(from.archived, false) AS archived
 		`
 		res, err := tx.Run(ctx, query, map[string]any{"taskId": taskId})
 		if err != nil {
@@ -224,6 +329,7 @@ func (s *WorkflowService) GetDependencies(ctx context.Context, taskId string) ([
 			name, _ := record.Get("name")
 			description, _ := record.Get("description")
 			blocked, _ := record.Get("blocked")
+			archived, _ := record.Get("archived")
 
 			task := models.TaskNode{
 				ID:          id.(string),
@@ -231,6 +337,7 @@ func (s *WorkflowService) GetDependencies(ctx context.Context, taskId string) ([
 				Name:        name.(string),
 				Description: description.(string),
 				Blocked:     blocked.(bool),
+				Archived:    archived.(bool),
 			}
 			dependencies = append(dependencies, task)
 		}
@@ -344,6 +451,164 @@ func (s *WorkflowService) SetBlockedStatus(ctx context.Context, taskID string, b
 	return nil
 }
 
+// SetArchivedStatus označava čvor zadatka kao arhiviran (ili ga vraća). Čvor i njegove
+// zavisnosti se ne brišu.
+func (s *WorkflowService) SetArchivedStatus(ctx context.Context, taskID string, archived bool) error {
+	logging.Logger.Infof("Setting archived status for task %s to %v", taskID, archived)
+
+	session := s.Driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
+	defer session.Close(ctx)
+
+	result, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		res, err := tx.Run(ctx, `
+			MATCH (t:Task {id: $taskID})
+			SET t.archived = $archived
+			RETURN count(t) AS updated
+		`, map[string]interface{}{"taskID": taskID, "archived": archived})
+		if err != nil {
+			return nil, err
+		}
+		record, err := res.Single(ctx)
+		if err != nil {
+			return nil, err
+		}
+		updated, _ := record.Get("updated")
+		return updated.(int64), nil
+	})
+	if err != nil {
+		logging.Logger.Errorf("Failed to set archived status for task %s: %v", taskID, err)
+		return fmt.Errorf("failed to set archived status in db: %w", err)
+	}
+	if result.(int64) == 0 {
+		return fmt.Errorf("task node not found")
+	}
+
+	logging.Logger.Infof("Archived status for task %s successfully set to %v", taskID, archived)
+	return nil
+}
+
+// SetProjectArchivedStatus označava sve čvorove projekta kao arhivirane (ili ih vraća).
+func (s *WorkflowService) SetProjectArchivedStatus(ctx context.Context, projectID string, archived bool) (int64, error) {
+	logging.Logger.Infof("Setting archived status for all tasks of project %s to %v", projectID, archived)
+
+	session := s.Driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
+	defer session.Close(ctx)
+
+	result, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		res, err := tx.Run(ctx, `
+			MATCH (t:Task {projectId: $projectId})
+			SET t.archived = $archived
+			RETURN count(t) AS updated
+		`, map[string]interface{}{"projectId": projectID, "archived": archived})
+		if err != nil {
+			return nil, err
+		}
+		record, err := res.Single(ctx)
+		if err != nil {
+			return nil, err
+		}
+		updated, _ := record.Get("updated")
+		return updated.(int64), nil
+	})
+	if err != nil {
+		logging.Logger.Errorf("Failed to set archived status for project %s: %v", projectID, err)
+		return 0, fmt.Errorf("failed to set archived status in db: %w", err)
+	}
+
+	logging.Logger.Infof("Archived status set to %v for %d tasks of project %s", archived, result.(int64), projectID)
+	return result.(int64), nil
+}
+
+// DeleteProjectNodes briše sve čvorove projekta zajedno sa njihovim zavisnostima.
+// Poziva se kada se projekat trajno briše; ponovni poziv samo vraća 0.
+func (s *WorkflowService) DeleteProjectNodes(ctx context.Context, projectID string) (int64, error) {
+	logging.Logger.Infof("Deleting all task nodes of project %s", projectID)
+
+	session := s.Driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
+	defer session.Close(ctx)
+
+	result, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		res, err := tx.Run(ctx, `
+			MATCH (t:Task {projectId: $projectId})
+			WITH t, t.id AS id
+			DETACH DELETE t
+			RETURN count(id) AS deleted
+		`, map[string]interface{}{"projectId": projectID})
+		if err != nil {
+			return nil, err
+		}
+		record, err := res.Single(ctx)
+		if err != nil {
+			return nil, err
+		}
+		deleted, _ := record.Get("deleted")
+		return deleted.(int64), nil
+	})
+	if err != nil {
+		logging.Logger.Errorf("Failed to delete task nodes of project %s: %v", projectID, err)
+		return 0, fmt.Errorf("failed to delete project nodes in db: %w", err)
+	}
+
+	logging.Logger.Infof("Deleted %d task nodes of project %s", result.(int64), projectID)
+	return result.(int64), nil
+}
+
+// CloneProjectDependencies kopira zavisnosti između zadataka projekta sourceProjectID na
+// njihove kopije u projektu targetProjectID; taskIDMap mapira ID originala na ID kopije.
+// Zavisnosti prema zadacima koji nisu kopirani se preskaču. Kopije kreću od statusa
+// Pending, pa je zavisni zadatak odmah blokiran.
+func (s *WorkflowService) CloneProjectDependencies(ctx context.Context, sourceProjectID, targetProjectID string, taskIDMap map[string]string) (int64, error) {
+	dependencies, err := s.GetProjectDependencies(ctx, sourceProjectID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch project dependencies: %w", err)
+	}
+
+	pairs := []map[string]any{}
+	for _, dependency := range dependencies {
+		fromID, fromCopied := taskIDMap[dependency.FromTaskID]
+		toID, toCopied := taskIDMap[dependency.ToTaskID]
+		if fromCopied && toCopied {
+			pairs = append(pairs, map[string]any{"from": fromID, "to": toID})
+		}
+	}
+	if len(pairs) == 0 {
+		return 0, nil
+	}
+
+	session := s.Driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
+	defer session.Close(ctx)
+
+	result, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		res, err := tx.Run(ctx, `
+			UNWIND $pairs AS pair
+			MATCH (to:Task {id: pair.to, projectId: $projectId}), (from:Task {id: pair.from, projectId: $projectId})
+			MERGE (to)-[:DEPENDS_ON]->(from)
+			SET to.blocked = true
+			RETURN count(*) AS copied
+		`, map[string]interface{}{"pairs": pairs, "projectId": targetProjectID})
+		if err != nil {
+			return nil, err
+		}
+		record, err := res.Single(ctx)
+		if err != nil {
+			return nil, err
+		}
+		copied, _ := record.Get("copied")
+		return copied.(int64), nil
+	})
+	if err != nil {
+		logging.Logger.Errorf("Failed to clone dependencies of project %s into %s: %v", sourceProjectID, targetProjectID, err)
+		return 0, fmt.Errorf("failed to clone project dependencies in db: %w", err)
+	}
+
+	copied := result.(int64)
+	if copied < int64(len(pairs)) {
+		return copied, fmt.Errorf("only %d of %d dependencies could be cloned; task nodes are missing", copied, len(pairs))
+	}
+	logging.Logger.Infof("Cloned %d dependencies of project %s into %s", copied, sourceProjectID, targetProjectID)
+	return copied, nil
+}
+
 func (s *WorkflowService) GetProjectDependencies(ctx context.Context, projectID string) ([]models.TaskDependencyRelation, error) {
 	logging.Logger.Infof("Fetching project dependencies for project: %s", projectID)
 
@@ -391,7 +656,8 @@ func (s *WorkflowService) GetWorkflowByProject(ctx context.Context, projectID st
 		query := `
 			MATCH (t:Task {projectId: $projectId})
 			RETURN t.id AS id, t.projectId AS projectId, t.name AS name,
-			       t.description AS description, t.blocked AS blocked
+			       t.description AS description, t.blocked AS blocked,
+			       coalesce(t.archived, false) AS archived
 		`
 		res, err := tx.Run(ctx, query, map[string]any{"projectId": projectID})
 		if err != nil {
@@ -407,6 +673,7 @@ func (s *WorkflowService) GetWorkflowByProject(ctx context.Context, projectID st
 			name, _ := record.Get("name")
 			description, _ := record.Get("description")
 			blocked, _ := record.Get("blocked")
+			archived, _ := record.Get("archived")
 
 			taskNodes = append(taskNodes, models.TaskNode{
 				ID:          id.(string),
@@ -414,6 +681,7 @@ func (s *WorkflowService) GetWorkflowByProject(ctx context.Context, projectID st
 				Name:        name.(string),
 				Description: description.(string),
 				Blocked:     blocked.(bool),
+				Archived:    archived.(bool),
 			})
 		}
 
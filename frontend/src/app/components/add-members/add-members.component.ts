@@ -117,7 +117,7 @@ export class AddMembersComponent implements OnInit {
     this.projectMembersService.addMembers(this.projectId, newMembersToAdd).subscribe(
       () => {
         this.errorMessage = '';
-        this.successMessage = 'Members added successfully!';
+        this.successMessage = 'Invitations sent successfully!';
         
         setTimeout(() => {
           this.successMessage = '';
@@ -52,7 +52,7 @@ export class ProjectMembersService {
   addMembers(projectId: string, usernames: string[]): Observable<any> {
     const headers = this.getHeadersWithRole();
     headers.append('Content-Type', 'application/json');
-    return this.http.post(`${this.apiUrl}/projects/${projectId}/members`, { usernames }, { headers }).pipe(
+    return this.http.post(`${this.apiUrl}/projects/${projectId}/invitations`, { usernames }, { headers }).pipe(
       catchError((error) => {
         console.error('Error in addMembers:', error);
         return throwError(error);
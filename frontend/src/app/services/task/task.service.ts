@@ -37,7 +37,7 @@ export class TaskService {
   
   getTasksByProject(projectId: string): Observable<any[]> {
     const headers = this.getAuthHeaders();
-    return this.http.get<any[]>(`${this.apiUrl}/project/${projectId}`, { headers });
+    return this.http.get<any[]>(`http://localhost:8000/api/project-tasks/${projectId}`, { headers });
   }
 
   getTasksForProject(projectId: string): Observable<any[]> {